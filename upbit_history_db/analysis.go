@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// TypicalPrice - 대표 가격 (고가 + 저가 + 종가) / 3
+func (c Candle) TypicalPrice() float64 {
+	return (c.HighPrice + c.LowPrice + c.TradePrice) / 3
+}
+
+// CandleWithReturn - 직전 캔들 대비 수익률이 붙은 캔들
+type CandleWithReturn struct {
+	Candle
+	PctReturn float64 // 단순 수익률 (0.01 = 1%)
+	LogReturn float64 // 로그 수익률 ln(close / prevClose)
+}
+
+// WithReturns - 캔들별 직전 종가 대비 수익률 계산
+//
+// 첫 번째 캔들(및 직전 종가가 0 이하인 캔들)의 수익률은 NaN 이 아닌 0 으로 둔다.
+// 입력은 시간 오름차순이어야 한다.
+func WithReturns(candles []Candle) []CandleWithReturn {
+	result := make([]CandleWithReturn, len(candles))
+	for i, candle := range candles {
+		result[i].Candle = candle
+		if i == 0 {
+			continue
+		}
+
+		prev := candles[i-1].TradePrice
+		if prev <= 0 || candle.TradePrice <= 0 {
+			continue
+		}
+		result[i].PctReturn = candle.TradePrice/prev - 1
+		result[i].LogReturn = math.Log(candle.TradePrice / prev)
+	}
+	return result
+}
+
+// NormalizeSeries - 첫 종가가 base 가 되도록 종가를 비율로 환산 (다른 시간단위/마켓과 상대 성과 비교용)
+//
+// 앞쪽의 종가 0 이하 캔들은 기준으로 쓸 수 없으므로 건너뛰고, 첫 양수 종가를 기준으로 삼는다.
+// 빈 입력이나 양수 종가가 없으면 nil 을 돌려준다.
+func NormalizeSeries(candles []Candle, base float64) []IndicatorPoint {
+	start := 0
+	for start < len(candles) && candles[start].TradePrice <= 0 {
+		start++
+	}
+	if start == len(candles) {
+		return nil
+	}
+
+	ref := candles[start].TradePrice
+	points := make([]IndicatorPoint, 0, len(candles)-start)
+	for _, candle := range candles[start:] {
+		points = append(points, IndicatorPoint{
+			Timestamp: candle.CandleDateTimeKST,
+			Value:     candle.TradePrice / ref * base,
+		})
+	}
+	return points
+}
+
+// ComputeHeikinAshi - 저장된 캔들로 Heikin-Ashi 캔들 계산 (from/to 는 GetCandles 와 동일)
+func (c *Collector) ComputeHeikinAshi(tf Timeframe, from, to time.Time) ([]Candle, error) {
+	candles, err := c.GetCandles(tf, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return HeikinAshi(candles), nil
+}
+
+// HeikinAshi - 표준 Heikin-Ashi 변환 (timestamp/거래량은 원본 유지)
+//
+//	HA close = (O + H + L + C) / 4
+//	HA open  = (이전 HA open + 이전 HA close) / 2, 첫 캔들은 (O + C) / 2
+//	HA high  = max(H, HA open, HA close), HA low = min(L, HA open, HA close)
+func HeikinAshi(candles []Candle) []Candle {
+	result := make([]Candle, len(candles))
+	for i, candle := range candles {
+		ha := candle
+		ha.TradePrice = (candle.OpeningPrice + candle.HighPrice + candle.LowPrice + candle.TradePrice) / 4
+		if i == 0 {
+			ha.OpeningPrice = (candle.OpeningPrice + candle.TradePrice) / 2
+		} else {
+			ha.OpeningPrice = (result[i-1].OpeningPrice + result[i-1].TradePrice) / 2
+		}
+		ha.HighPrice = math.Max(candle.HighPrice, math.Max(ha.OpeningPrice, ha.TradePrice))
+		ha.LowPrice = math.Min(candle.LowPrice, math.Min(ha.OpeningPrice, ha.TradePrice))
+		result[i] = ha
+	}
+	return result
+}
+
+// VolReport - 시간단위 변동성/VaR 요약 (수익률은 비율, 0.01 = 1%)
+type VolReport struct {
+	Timeframe            string  `json:"timeframe"`
+	Window               int     `json:"window"`
+	Confidence           float64 `json:"confidence"`
+	AnnualizedVolatility float64 `json:"annualized_volatility"` // 전체 로그 수익률 표준편차 × √(연간 캔들 수)
+	RollingSigma         float64 `json:"rolling_sigma"`         // 최근 window 개 로그 수익률 표준편차
+	VaR                  float64 `json:"var"`                   // 최근 window 개 단순 수익률 기준 historical VaR (손실을 양수로)
+	AsOf                 string  `json:"as_of"`                 // 마지막 캔들 timestamp (KST)
+}
+
+// VolatilityReport - 저장된 캔들로 변동성과 historical VaR 계산
+//
+// VaR 는 최근 window 개 수익률의 (1 - confidence) 분위수를 부호를 바꿔 돌려준다.
+// 예: confidence 0.95, VaR 0.03 이면 캔들 하나 동안 95% 확률로 손실이 3% 이내.
+func (c *Collector) VolatilityReport(tf Timeframe, window int, confidence float64) (VolReport, error) {
+	if window < 2 {
+		return VolReport{}, fmt.Errorf("window 는 2 이상이어야 합니다: %d", window)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return VolReport{}, fmt.Errorf("confidence 는 0 과 1 사이여야 합니다: %v", confidence)
+	}
+
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		return VolReport{}, err
+	}
+	// 첫 캔들은 수익률이 없으므로 window + 1 개 필요
+	if len(candles) < window+1 {
+		return VolReport{}, fmt.Errorf("%s 데이터 부족: 캔들 %d개 (window %d 에는 %d개 필요)",
+			tf.Name, len(candles), window, window+1)
+	}
+
+	returns := WithReturns(candles)[1:]
+	logReturns := make([]float64, len(returns))
+	pctReturns := make([]float64, len(returns))
+	for i, r := range returns {
+		logReturns[i] = r.LogReturn
+		pctReturns[i] = r.PctReturn
+	}
+
+	recent := append([]float64(nil), pctReturns[len(pctReturns)-window:]...)
+	sort.Float64s(recent)
+	rank := int(math.Floor((1 - confidence) * float64(window)))
+	if rank >= window {
+		rank = window - 1
+	}
+
+	return VolReport{
+		Timeframe:            tf.Name,
+		Window:               window,
+		Confidence:           confidence,
+		AnnualizedVolatility: stdDev(logReturns) * math.Sqrt(periodsPerYear(tf)),
+		RollingSigma:         stdDev(logReturns[len(logReturns)-window:]),
+		VaR:                  -recent[rank],
+		AsOf:                 candles[len(candles)-1].CandleDateTimeKST,
+	}, nil
+}
+
+// periodsPerYear - 1년 동안의 캔들 수 (24시간 거래, 월봉은 12)
+func periodsPerYear(tf Timeframe) float64 {
+	if tf.Name == "month" {
+		return 12
+	}
+	return 365 * 24 * 60 / float64(tf.Minutes)
+}
+
+// stdDev - 표본 표준편차 (n - 1)
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// closeCandles - 종가가 closes 인 캔들 (고가/저가는 종가 ±1)
+func closeCandles(closes ...float64) []Candle {
+	candles := make([]Candle, len(closes))
+	for i, v := range closes {
+		candles[i] = Candle{OpeningPrice: v, HighPrice: v + 1, LowPrice: v - 1, TradePrice: v}
+	}
+	return candles
+}
+
+// closeTo - 두 값의 차이가 eps 이하인지
+func closeTo(a, b, eps float64) bool {
+	return math.Abs(a-b) <= eps
+}
+
+func TestTypicalPrice(t *testing.T) {
+	candle := Candle{HighPrice: 110, LowPrice: 90, TradePrice: 103}
+	if got := candle.TypicalPrice(); got != 101 {
+		t.Errorf("TypicalPrice = %v, want 101", got)
+	}
+}
+
+func TestWithReturns(t *testing.T) {
+	got := WithReturns(closeCandles(100, 110, 99, 0, 50))
+	want := []struct{ pct, log float64 }{
+		{0, 0},
+		{0.1, math.Log(1.1)},
+		{-0.1, math.Log(0.9)},
+		{0, 0}, // 종가 0
+		{0, 0}, // 직전 종가 0
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d개, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if !closeTo(got[i].PctReturn, w.pct, 1e-12) || !closeTo(got[i].LogReturn, w.log, 1e-12) {
+			t.Errorf("[%d] pct = %v, log = %v, want %v, %v", i, got[i].PctReturn, got[i].LogReturn, w.pct, w.log)
+		}
+		if math.IsNaN(got[i].PctReturn) || math.IsNaN(got[i].LogReturn) {
+			t.Errorf("[%d] NaN 수익률", i)
+		}
+	}
+}
+
+func TestNormalizeSeries(t *testing.T) {
+	candles := closeCandles(0, 200, 250, 150, 300)
+	got := NormalizeSeries(candles, 100)
+	// 앞쪽 종가 0 캔들은 건너뛰고 200 이 기준
+	want := []float64{100, 125, 75, 150}
+	if len(got) != len(want) {
+		t.Fatalf("%d개, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if !closeTo(got[i].Value, w, 1e-12) {
+			t.Errorf("[%d] = %v, want %v", i, got[i].Value, w)
+		}
+		if ratio := got[i].Value / got[0].Value; !closeTo(ratio, candles[i+1].TradePrice/candles[1].TradePrice, 1e-12) {
+			t.Errorf("[%d] 비율 = %v, 원래 종가 비율과 다름", i, ratio)
+		}
+	}
+
+	if got := NormalizeSeries(nil, 100); got != nil {
+		t.Errorf("빈 입력 = %v, want nil", got)
+	}
+	if got := NormalizeSeries(closeCandles(0, 0), 100); got != nil {
+		t.Errorf("종가가 모두 0 = %v, want nil", got)
+	}
+}
+
+func TestComputeHeikinAshi(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ohlc := [][4]float64{{10, 12, 9, 11}, {11, 14, 10, 13}, {13, 13.5, 11, 11.5}}
+	candles := make([]Candle, len(ohlc))
+	for i, v := range ohlc {
+		candles[i] = testCandle(t0.AddDate(0, 0, i), 0)
+		candles[i].OpeningPrice, candles[i].HighPrice, candles[i].LowPrice, candles[i].TradePrice = v[0], v[1], v[2], v[3]
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+
+	ha, err := c.ComputeHeikinAshi(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 손으로 계산한 값 (open, high, low, close)
+	want := [][4]float64{
+		{10.5, 12, 9, 10.5},
+		{10.5, 14, 10, 12},
+		{11.25, 13.5, 11, 12.25},
+	}
+	if len(ha) != len(want) {
+		t.Fatalf("%d개, want %d", len(ha), len(want))
+	}
+	for i, w := range want {
+		got := [4]float64{ha[i].OpeningPrice, ha[i].HighPrice, ha[i].LowPrice, ha[i].TradePrice}
+		if got != w {
+			t.Errorf("[%d] = %v, want %v", i, got, w)
+		}
+		if ha[i].CandleDateTimeKST != candles[i].CandleDateTimeKST {
+			t.Errorf("[%d] timestamp = %s, want %s", i, ha[i].CandleDateTimeKST, candles[i].CandleDateTimeKST)
+		}
+	}
+}
+
+func TestVolatilityReport(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	const sigma = 0.02
+	rng := rand.New(rand.NewSource(1))
+	t0 := time.Date(2018, 1, 1, 9, 0, 0, 0, time.UTC)
+	price := 1000.0
+	candles := make([]Candle, 2000)
+	for i := range candles {
+		candles[i] = testCandle(t0.AddDate(0, 0, i), price)
+		price *= math.Exp(rng.NormFloat64() * sigma)
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := c.VolatilityReport(tf, 1000, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 정규분포 근사: 표준편차 σ, 95% VaR ≈ 1.645σ
+	if !closeTo(report.RollingSigma, sigma, 0.1*sigma) {
+		t.Errorf("rolling sigma = %v, want ≈ %v", report.RollingSigma, sigma)
+	}
+	if want := sigma * math.Sqrt(365); !closeTo(report.AnnualizedVolatility, want, 0.1*want) {
+		t.Errorf("annualized = %v, want ≈ %v", report.AnnualizedVolatility, want)
+	}
+	if want := 1.645 * sigma; !closeTo(report.VaR, want, 0.15*want) {
+		t.Errorf("VaR = %v, want ≈ %v", report.VaR, want)
+	}
+	if report.AsOf != candles[len(candles)-1].CandleDateTimeKST {
+		t.Errorf("as_of = %s", report.AsOf)
+	}
+
+	if _, err := c.VolatilityReport(tf, 2000, 0.95); err == nil {
+		t.Error("window+1 개보다 캔들이 적은데 오류 없음")
+	}
+	if _, err := c.VolatilityReport(tf, 100, 1); err == nil {
+		t.Error("confidence 1 을 허용함")
+	}
+}
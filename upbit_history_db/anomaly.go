@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// anomalyWindow - 이동 중앙값 계산에 쓰는 앞뒤 캔들 수 (대상 포함 2*anomalyWindow+1 개)
+const anomalyWindow = 10
+
+// Anomaly - 이동 중앙값에서 크게 벗어난 캔들
+type Anomaly struct {
+	Timestamp  string  `json:"timestamp"`
+	TradePrice float64 `json:"trade_price"`
+	Median     float64 `json:"median"`
+	Score      float64 `json:"score"` // |종가 - 중앙값| / MAD
+}
+
+// DetectAnomalies - 종가가 주변 캔들 이동 중앙값에서 zThreshold MAD 이상 벗어난 캔들 조회 (읽기 전용)
+//
+// 앞뒤 anomalyWindow 개 캔들로 중앙값과 MAD(중앙 절대 편차)를 구한다.
+// 가격이 완전히 평탄해 MAD 가 0 인 구간은 판단하지 않는다.
+func (c *Collector) DetectAnomalies(tf Timeframe, zThreshold float64) ([]Anomaly, error) {
+	if zThreshold <= 0 {
+		return nil, fmt.Errorf("zThreshold 는 0 보다 커야 합니다: %v", zThreshold)
+	}
+
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return findAnomalies(candles, zThreshold), nil
+}
+
+func findAnomalies(candles []Candle, zThreshold float64) []Anomaly {
+	var anomalies []Anomaly
+	window := make([]float64, 0, 2*anomalyWindow+1)
+	deviations := make([]float64, 0, 2*anomalyWindow+1)
+
+	for i, candle := range candles {
+		window = window[:0]
+		for j := max(0, i-anomalyWindow); j <= min(len(candles)-1, i+anomalyWindow); j++ {
+			window = append(window, candles[j].TradePrice)
+		}
+		if len(window) < 3 {
+			continue
+		}
+
+		med := median(window)
+		deviations = deviations[:0]
+		for _, v := range window {
+			deviations = append(deviations, math.Abs(v-med))
+		}
+		mad := median(deviations)
+		if mad == 0 {
+			continue
+		}
+
+		if score := math.Abs(candle.TradePrice-med) / mad; score > zThreshold {
+			anomalies = append(anomalies, Anomaly{
+				Timestamp:  candle.CandleDateTimeKST,
+				TradePrice: candle.TradePrice,
+				Median:     med,
+				Score:      score,
+			})
+		}
+	}
+	return anomalies
+}
+
+// median - values 의 중앙값 (values 순서가 바뀜)
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// MarkAnomalies - DetectAnomalies 결과를 is_anomaly 컬럼에 기록 (기존 표시는 초기화, 컬럼이 없으면 추가)
+func (c *Collector) MarkAnomalies(tf Timeframe, zThreshold float64) ([]Anomaly, error) {
+	anomalies, err := c.DetectAnomalies(tf, zThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, db := range c.candleDBs() {
+		_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN is_anomaly INTEGER DEFAULT 0", c.table(tf)))
+		if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return nil, err
+		}
+		if _, err := db.Exec(fmt.Sprintf("UPDATE %s SET is_anomaly = 0 WHERE is_anomaly != 0", c.table(tf))); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range anomalies {
+		db, err := c.candleDBFor(a.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := db.Exec(fmt.Sprintf("UPDATE %s SET is_anomaly = 1 WHERE timestamp = ?", c.table(tf)), a.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+	return anomalies, nil
+}
+
+func runAnomalies(args []string) error {
+	fs := flag.NewFlagSet("anomalies", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "검사할 시간단위 (필수, 예: minute1)")
+	z := fs.Float64("z", 10, "이동 중앙값에서 벗어난 정도 기준 (MAD 배수)")
+	mark := fs.Bool("mark", false, "발견한 캔들을 is_anomaly 컬럼에 기록")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	var anomalies []Anomaly
+	if *mark {
+		anomalies, err = collector.MarkAnomalies(tf, *z)
+	} else {
+		anomalies, err = collector.DetectAnomalies(tf, *z)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, a := range anomalies {
+		fmt.Printf("[%s] %s %s 종가 %s (중앙값 %s, %.1f MAD)\n", tf.Name, collector.mark(markWarn),
+			a.Timestamp, formatFloat(a.TradePrice), formatFloat(a.Median), a.Score)
+	}
+	fmt.Printf("[%s] %s 이상 캔들 %d개\n", tf.Name, collector.mark(markOK), len(anomalies))
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMarkAnomaliesSingleSpike(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	t0 := time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)
+	spike := t0.AddDate(0, 0, 50).Format(timestampLayout)
+
+	// 완만한 사인 곡선에 하루만 10배로 튄 값
+	candles := make([]Candle, 100)
+	for i := range candles {
+		price := 100 + 5*math.Sin(float64(i)/5)
+		if i == 50 {
+			price *= 10
+		}
+		candles[i] = testCandle(t0.AddDate(0, 0, i), price)
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+
+	// 두 번 실행해도 표시는 하나 (이전 표시는 초기화)
+	for run := 1; run <= 2; run++ {
+		anomalies, err := c.MarkAnomalies(tf, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(anomalies) != 1 || anomalies[0].Timestamp != spike {
+			t.Fatalf("%d 번째 실행 이상치 = %+v, want %s 하나", run, anomalies, spike)
+		}
+		if n := countRows(t, c, tf, "is_anomaly = 1"); n != 1 {
+			t.Errorf("%d 번째 실행 is_anomaly 행 %d개, want 1", run, n)
+		}
+		if n := countRows(t, c, tf, "is_anomaly = 1 AND timestamp = ?", spike); n != 1 {
+			t.Errorf("%d 번째 실행 %s 가 표시되지 않음", run, spike)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDecodeCandlesUpbitError(t *testing.T) {
+	for _, tc := range []struct {
+		status    int
+		body      string
+		name      string
+		retryable bool
+	}{
+		{http.StatusBadRequest, `{"error":{"name":"invalid_query_payload","message":"잘못된 요청"}}`, "invalid_query_payload", false},
+		{http.StatusNotFound, `{"error":{"name":404,"message":"Code not found"}}`, "404", false},
+		{http.StatusTooManyRequests, `{"error":{"name":"too_many_requests","message":"요청 제한"}}`, "too_many_requests", true},
+	} {
+		_, err := decodeCandles(tc.status, []byte(tc.body))
+		var apiErr *UpbitAPIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: UpbitAPIError 아님: %v", tc.body, err)
+			continue
+		}
+		if apiErr.Name != tc.name || apiErr.StatusCode != tc.status {
+			t.Errorf("%s: name = %q, status = %d", tc.body, apiErr.Name, apiErr.StatusCode)
+		}
+		if got := isRetryableFetch(err); got != tc.retryable {
+			t.Errorf("%s: retryable = %v, want %v", tc.body, got, tc.retryable)
+		}
+	}
+}
+
+func TestIsRetryableFetchMalformedBody(t *testing.T) {
+	for _, tc := range []struct {
+		status    int
+		body      string
+		retryable bool
+	}{
+		{http.StatusBadGateway, `<html>bad gateway</html>`, true},
+		{http.StatusOK, `<html>`, false},
+		{http.StatusOK, `[{"opening_price":"abc"}]`, false},
+		{http.StatusOK, `{"market":"KRW-BTC"}`, false},
+	} {
+		_, err := decodeCandles(tc.status, []byte(tc.body))
+		if err == nil {
+			t.Errorf("%s: 오류 없음", tc.body)
+			continue
+		}
+		if got := isRetryableFetch(err); got != tc.retryable {
+			t.Errorf("%s: retryable = %v, want %v (%v)", tc.body, got, tc.retryable, err)
+		}
+	}
+}
+
+func TestFetchCandlesFailsFastOnClientError(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"name":"invalid_market","message":"market not found"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := openTestDB(t, "KRW-NOPE")
+	c.apiURL = srv.URL
+
+	if _, err := c.fetchCandles(context.Background(), mustTimeframe(t, "day"), "", nil); err == nil {
+		t.Fatal("오류 응답인데 오류 없음")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("요청 %d회, want 1 (재시도하지 않아야 함)", n)
+	}
+}
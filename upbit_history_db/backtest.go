@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// BacktestConfig - 백테스트 설정
+type BacktestConfig struct {
+	InitialCash float64 // 시작 자금 (KRW)
+	FeeRate     float64 // 체결 금액 대비 수수료 (업비트 KRW 마켓 0.0005)
+	MinVolume   float64 // 거래량이 이 값 미만인 캔들은 거래하지 않음 (보간/거래 희박 캔들 제외, 0 = 제한 없음)
+	SlippageBps float64 // 체결가를 불리한 방향으로 0~SlippageBps bp 무작위 조정 (0 = 종가 그대로)
+	Seed        int64   // 무작위 요소(슬리피지 등) 시드 - 같은 시드면 같은 결과
+}
+
+// DefaultBacktestConfig - 100만 원, 업비트 수수료
+func DefaultBacktestConfig() BacktestConfig {
+	return BacktestConfig{InitialCash: 1_000_000, FeeRate: 0.0005}
+}
+
+// Trade - 백테스트 체결 기록
+type Trade struct {
+	Timestamp string  `json:"timestamp"`
+	Side      string  `json:"side"` // buy / sell
+	Price     float64 `json:"price"`
+	Quantity  float64 `json:"quantity"`
+	Fee       float64 `json:"fee"`
+}
+
+// BacktestResult - 백테스트 결과 (Equity 는 캔들마다 종가 기준 평가금액)
+type BacktestResult struct {
+	Trades      []Trade          `json:"trades"`
+	Equity      []IndicatorPoint `json:"equity"`
+	FinalEquity float64          `json:"final_equity"`
+	TotalReturn float64          `json:"total_return"` // 0.1 = 10%
+	MaxDrawdown float64          `json:"max_drawdown"` // 고점 대비 최대 하락률 (양수)
+}
+
+// RunBacktest - 저장된 캔들로 백테스트 (from/to 는 GetCandles 와 동일)
+func (c *Collector) RunBacktest(tf Timeframe, from, to time.Time, strategy Strategy, cfg BacktestConfig) (BacktestResult, error) {
+	candles, err := c.GetCandles(tf, from, to)
+	if err != nil {
+		return BacktestResult{}, err
+	}
+	if len(candles) == 0 {
+		return BacktestResult{}, fmt.Errorf("%s 구간에 캔들이 없습니다", tf.Name)
+	}
+	return Backtest(candles, strategy, cfg), nil
+}
+
+// Backtest - 캔들마다 전략을 평가해 종가에 전액 매수/전량 매도하는 현물 롱 전용 백테스트
+//
+// 전략에는 매 캔들까지의 전체 이력을 넘긴다. MinVolume 미만 캔들에서는 신호를 무시(Hold)하지만
+// 평가금액 곡선에는 그대로 포함된다.
+//
+// 결과는 입력 순서(GetCandles 는 ORDER BY timestamp 로 항상 같은 순서)와 cfg.Seed 로만 정해지며,
+// 무작위 요소는 전역 rand 대신 Seed 로 만든 전용 *rand.Rand 를 쓴다.
+func Backtest(candles []Candle, strategy Strategy, cfg BacktestConfig) BacktestResult {
+	return backtestWithRand(candles, strategy, cfg, rand.New(rand.NewSource(cfg.Seed)))
+}
+
+func backtestWithRand(candles []Candle, strategy Strategy, cfg BacktestConfig, rng *rand.Rand) BacktestResult {
+	var result BacktestResult
+	cash := cfg.InitialCash
+	position := 0.0
+	peak := cfg.InitialCash
+
+	for i, candle := range candles {
+		sig := strategy.Evaluate(candles[:i+1])
+		if cfg.MinVolume > 0 && candle.CandleAccTradeVolume < cfg.MinVolume {
+			sig = Hold
+		}
+
+		price := candle.TradePrice
+		switch {
+		case sig == Buy && position == 0 && cash > 0 && price > 0:
+			price *= 1 + slippage(cfg, rng)
+			fee := cash * cfg.FeeRate
+			position = (cash - fee) / price
+			result.Trades = append(result.Trades, Trade{
+				Timestamp: candle.CandleDateTimeKST, Side: Buy.String(), Price: price, Quantity: position, Fee: fee,
+			})
+			cash = 0
+		case sig == Sell && position > 0:
+			price *= 1 - slippage(cfg, rng)
+			proceeds := position * price
+			fee := proceeds * cfg.FeeRate
+			result.Trades = append(result.Trades, Trade{
+				Timestamp: candle.CandleDateTimeKST, Side: Sell.String(), Price: price, Quantity: position, Fee: fee,
+			})
+			cash += proceeds - fee
+			position = 0
+		}
+
+		equity := cash + position*candle.TradePrice
+		result.Equity = append(result.Equity, IndicatorPoint{Timestamp: candle.CandleDateTimeKST, Value: equity})
+		if equity > peak {
+			peak = equity
+		}
+		if peak > 0 {
+			result.MaxDrawdown = max(result.MaxDrawdown, (peak-equity)/peak)
+		}
+	}
+
+	if n := len(result.Equity); n > 0 {
+		result.FinalEquity = result.Equity[n-1].Value
+	} else {
+		result.FinalEquity = cfg.InitialCash
+	}
+	if cfg.InitialCash > 0 {
+		result.TotalReturn = result.FinalEquity/cfg.InitialCash - 1
+	}
+	return result
+}
+
+// slippage - 0 ~ SlippageBps bp 사이 무작위 비율
+func slippage(cfg BacktestConfig, rng *rand.Rand) float64 {
+	if cfg.SlippageBps <= 0 {
+		return 0
+	}
+	return rng.Float64() * cfg.SlippageBps / 10000
+}
+
+func (c *Collector) printBacktest(tf Timeframe, result BacktestResult) {
+	fmt.Fprintln(c.Output, "\n"+c.mark(markStats)+" 백테스트 결과:")
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  %-12s %s (%d개 캔들)\n", "시간단위", tf.Name, len(result.Equity))
+	fmt.Fprintf(c.Output, "  %-12s %d\n", "거래 수", len(result.Trades))
+	fmt.Fprintf(c.Output, "  %-12s %s원\n", "최종 자산", formatNumber(int(result.FinalEquity)))
+	fmt.Fprintf(c.Output, "  %-12s %.2f%%\n", "수익률", result.TotalReturn*100)
+	fmt.Fprintf(c.Output, "  %-12s %.2f%%\n", "최대 낙폭", result.MaxDrawdown*100)
+}
+
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	defaults := DefaultBacktestConfig()
+	timeframe := fs.String("timeframe", "day", "백테스트 시간단위")
+	from := fs.String("from", "", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "종료 시각 (KST, 포함)")
+	short := fs.Int("short", 5, "단기 SMA 기간")
+	long := fs.Int("long", 20, "장기 SMA 기간")
+	cash := fs.Float64("cash", defaults.InitialCash, "시작 자금 (KRW)")
+	fee := fs.Float64("fee", defaults.FeeRate, "수수료율")
+	minVolume := fs.Float64("min-volume", 0, "거래량이 이 값 미만인 캔들은 거래하지 않음 (0 = 제한 없음)")
+	slippageBps := fs.Float64("slippage-bps", 0, "체결가 무작위 슬리피지 최대값 (bp)")
+	seed := fs.Int64("seed", 1, "무작위 요소 시드 (같은 시드면 같은 결과)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if *short < 1 || *long <= *short {
+		return fmt.Errorf("--long 은 --short 보다 커야 합니다")
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	cfg := BacktestConfig{
+		InitialCash: *cash,
+		FeeRate:     *fee,
+		MinVolume:   *minVolume,
+		SlippageBps: *slippageBps,
+		Seed:        *seed,
+	}
+	result, err := collector.RunBacktest(tf, fromTime, toTime, SMACrossover{Short: *short, Long: *long}, cfg)
+	if err != nil {
+		return err
+	}
+	collector.printBacktest(tf, result)
+	return nil
+}
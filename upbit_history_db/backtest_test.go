@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// crossoverCandles - SMA(2)/SMA(4) 가 인덱스 5 에서 위로, 8 에서 아래로 교차하는 1분봉 (거래량 10)
+func crossoverCandles() []Candle {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	prices := []float64{10, 10, 10, 10, 10, 12, 14, 14, 8, 6}
+	candles := make([]Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = testCandle(start.Add(time.Duration(i)*time.Minute), p)
+		candles[i].CandleAccTradeVolume = 10
+	}
+	return candles
+}
+
+func TestBacktestMinVolume(t *testing.T) {
+	candles := crossoverCandles()
+	candles[5].CandleAccTradeVolume = 0 // 매수 신호 캔들이 보간(거래량 0) 캔들
+	strategy := SMACrossover{Short: 2, Long: 4}
+
+	unfiltered := Backtest(candles, strategy, DefaultBacktestConfig())
+	if len(unfiltered.Trades) != 2 || unfiltered.Trades[0].Timestamp != candles[5].CandleDateTimeKST {
+		t.Fatalf("필터 없이 체결 = %+v, want 인덱스 5 매수, 8 매도", unfiltered.Trades)
+	}
+	if unfiltered.TotalReturn >= 0 {
+		t.Errorf("필터 없이 수익률 = %v, 12 매수 8 매도라 손실이어야 함", unfiltered.TotalReturn)
+	}
+
+	cfg := DefaultBacktestConfig()
+	cfg.MinVolume = 1
+	filtered := Backtest(candles, strategy, cfg)
+	if len(filtered.Trades) != 0 {
+		t.Errorf("MinVolume 필터 체결 = %+v, want 없음 (매수 캔들이 걸러짐)", filtered.Trades)
+	}
+	// 걸러진 캔들도 평가금액 곡선에는 남음
+	if len(filtered.Equity) != len(candles) {
+		t.Errorf("평가금액 %d개, want %d", len(filtered.Equity), len(candles))
+	}
+	if filtered.FinalEquity != cfg.InitialCash || filtered.TotalReturn != 0 {
+		t.Errorf("최종 평가금액 = %v, 수익률 = %v, want 시작 자금 그대로", filtered.FinalEquity, filtered.TotalReturn)
+	}
+}
+
+func TestBacktestSeedIsReproducible(t *testing.T) {
+	candles := crossoverCandles()
+	strategy := SMACrossover{Short: 2, Long: 4}
+	tradeLog := func(seed int64) []byte {
+		t.Helper()
+		cfg := DefaultBacktestConfig()
+		cfg.SlippageBps = 50
+		cfg.Seed = seed
+		data, err := json.Marshal(Backtest(candles, strategy, cfg).Trades)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first, second := tradeLog(42), tradeLog(42)
+	if !bytes.Equal(first, second) {
+		t.Errorf("같은 시드 체결 기록이 다름:\n%s\n%s", first, second)
+	}
+	if bytes.Equal(first, tradeLog(7)) {
+		t.Error("다른 시드인데 슬리피지가 같음")
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BenchmarkResult - API 응답 지연 및 처리량 측정 결과
+type BenchmarkResult struct {
+	Timeframe   string
+	Concurrency int
+	Requests    int
+	Failures    int
+	Throttled   int // 429 응답 수
+	Elapsed     time.Duration
+	P50         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+}
+
+// RequestsPerSecond - 실제 달성한 초당 요청 수
+func (r BenchmarkResult) RequestsPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Benchmark - tf 의 pages 개 페이지를 concurrency 개 worker 로 요청하며 지연 시간 측정 (DB 저장 없음)
+//
+// 각 페이지는 현재 시각에서 200 캔들 단위로 떨어진 독립 커서를 사용하므로 순서와 무관하게 병렬 요청된다.
+// 지연 시간은 rate limit 대기를 제외한 HTTP 요청 시간만 측정한다.
+func (c *Collector) Benchmark(tf Timeframe, pages int, concurrency int) BenchmarkResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	result := BenchmarkResult{Timeframe: tf.Name, Concurrency: concurrency}
+	latencies := make([]time.Duration, 0, pages)
+	var mu sync.Mutex
+
+	now := time.Now().UTC()
+	pageSpan := time.Duration(tf.Minutes) * time.Minute * 200
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range work {
+				to := ""
+				if page > 0 {
+					to = now.Add(-pageSpan * time.Duration(page)).Format(timestampLayout)
+				}
+
+				c.rateLimiter.Wait()
+				begin := time.Now()
+				_, err := c.requestCandles(context.Background(), tf, to, nil)
+				latency := time.Since(begin)
+
+				mu.Lock()
+				result.Requests++
+				latencies = append(latencies, latency)
+				if err != nil {
+					result.Failures++
+					if responseStatus(err) == http.StatusTooManyRequests {
+						result.Throttled++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for page := 0; page < pages; page++ {
+		work <- page
+	}
+	close(work)
+	wg.Wait()
+
+	result.Elapsed = time.Since(start)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = percentile(latencies, 0.50)
+	result.P95 = percentile(latencies, 0.95)
+	result.P99 = percentile(latencies, 0.99)
+
+	return result
+}
+
+// percentile - 정렬된 값에서 nearest-rank 백분위수
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func (c *Collector) printBenchmark(r BenchmarkResult) {
+	fmt.Fprintln(c.Output, "\n"+c.mark(markStats)+" 벤치마크 결과:")
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  %-12s %s (동시 %d, 초당 제한 %d회)\n", "시간단위", r.Timeframe, r.Concurrency, c.rateLimiter.PerSecond())
+	fmt.Fprintf(c.Output, "  %-12s %d (실패 %d, 429 %d)\n", "요청", r.Requests, r.Failures, r.Throttled)
+	fmt.Fprintf(c.Output, "  %-12s %.1fs\n", "소요 시간", r.Elapsed.Seconds())
+	fmt.Fprintf(c.Output, "  %-12s %.2f req/s\n", "처리량", r.RequestsPerSecond())
+	fmt.Fprintf(c.Output, "  %-12s p50 %v / p95 %v / p99 %v\n", "지연",
+		r.P50.Round(time.Millisecond), r.P95.Round(time.Millisecond), r.P99.Round(time.Millisecond))
+	if r.Throttled > 0 {
+		fmt.Fprintf(c.Output, "  %s 429 응답 발생 - --rate 또는 --concurrency 를 낮추세요\n", c.mark(markWarn))
+	}
+}
+
+func runBenchmark(args []string) error {
+	fs := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	market := fs.String("market", defaultMarket, "마켓 코드")
+	plain := fs.Bool("plain", false, "이모지 없이 ASCII 접두어로 출력")
+	timeframe := fs.String("timeframe", "minute1", "측정할 시간단위")
+	pages := fs.Int("pages", 20, "요청할 페이지 수")
+	concurrency := fs.Int("concurrency", 1, "동시 요청 worker 수")
+	rate := fs.Int("rate", 9, "초당 최대 API 요청 수")
+	apiURL := fs.String("api-url", "", "캔들 API 주소 (가짜 서버 등, 지정 시 --live 불필요)")
+	live := fs.Bool("live", false, "실제 업비트 API 로 측정")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *apiURL == "" && !*live {
+		return fmt.Errorf("실제 API 측정은 --live 를 지정하세요 (또는 --api-url 로 가짜 서버 지정)")
+	}
+	if *rate <= 0 || *pages <= 0 {
+		return fmt.Errorf("--rate 와 --pages 는 1 이상이어야 합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	collector, err := newFetcher(*market)
+	if err != nil {
+		return err
+	}
+	collector.PlainOutput = *plain
+	collector.rateLimiter = NewRateLimiter(*rate)
+	if *apiURL != "" {
+		collector.apiURL = *apiURL
+	}
+
+	collector.printBenchmark(collector.Benchmark(tf, *pages, *concurrency))
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	src := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	var candles []Candle
+	for i := 0; i < 50; i++ {
+		if i%7 == 3 {
+			continue // 보간으로 채워질 빈 자리
+		}
+		candle := testCandle(t0.Add(time.Duration(i)*5*time.Minute), 1e8/3+float64(i)/7)
+		candle.CandleAccTradeVolume = 0.1 + float64(i)*1e-9
+		candles = append(candles, candle)
+	}
+	if _, _, err := src.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+	want, err := src.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump bytes.Buffer
+	if err := src.ExportBinary(tf, &dump); err != nil {
+		t.Fatal(err)
+	}
+	// 헤더 + 고정 길이 레코드
+	if size, wantSize := dump.Len(), len(binaryMagic)+2+(2+len("KRW-BTC"))+(2+len(tf.Name))+len(want)*(2+binaryRecordSize); size != wantSize {
+		t.Errorf("덤프 %d 바이트, want %d", size, wantSize)
+	}
+
+	dst := openTestDB(t, "KRW-BTC")
+	result, err := dst.ImportBinary(tf, bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Read != len(want) || result.Saved != len(want) || result.Rejected != 0 {
+		t.Errorf("결과 = %+v, want %d개 모두 저장", result, len(want))
+	}
+	got, err := dst.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("복원 %d행, want %d", len(got), len(want))
+	}
+	interpolated := 0
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("[%d] %+v\nwant %+v", i, got[i], want[i])
+		}
+		if got[i].IsInterpolated {
+			interpolated++
+		}
+	}
+	if interpolated != 7 {
+		t.Errorf("보간 캔들 %d개, want 7 (플래그 유지)", interpolated)
+	}
+
+	if _, err := dst.ImportBinary(mustTimeframe(t, "minute1"), bytes.NewReader(dump.Bytes())); err == nil {
+		t.Error("다른 시간단위로 가져오기 오류 없음")
+	}
+	if _, err := openTestDB(t, "KRW-ETH").ImportBinary(tf, bytes.NewReader(dump.Bytes())); err == nil {
+		t.Error("다른 마켓으로 가져오기 오류 없음")
+	}
+	if _, err := dst.ImportBinary(tf, bytes.NewReader([]byte("timestamp,open\n"))); err == nil {
+		t.Error("CSV 를 바이너리로 가져오기 오류 없음")
+	}
+}
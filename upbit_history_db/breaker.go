@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errCircuitOpen - 회로 차단기가 열려 있어 요청하지 않음
+var errCircuitOpen = errors.New("회로 차단기 열림 - API 요청 중단 중")
+
+// breakerState - 회로 차단기 상태
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 정상 - 모든 요청 허용
+	breakerOpen                         // 연속 실패 - cooldown 동안 요청 없이 실패
+	breakerHalfOpen                     // cooldown 후 - 복구 확인 요청 1개만 허용
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker - fetchCandles 연속 실패 시 요청을 막는 회로 차단기 (모든 시간단위가 공유)
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    breakerState
+	failures int       // 연속 실패 수 (closed 상태)
+	openedAt time.Time // 마지막으로 열린 시각
+	probing  bool      // half-open 에서 복구 확인 요청이 진행 중
+	trips    int       // 이번 Collector 에서 열린 횟수
+}
+
+// allow - 요청 가능 여부 (open 이고 cooldown 이 지났으면 half-open 으로 바꾸고 1개 허용)
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		remaining := b.cooldown - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w (%v 후 재시도)", errCircuitOpen, remaining.Round(time.Second))
+		}
+		b.state, b.probing = breakerHalfOpen, true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w (복구 확인 중)", errCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record - 요청 결과 반영 후 바뀌기 전/후 상태 반환
+func (b *circuitBreaker) record(failed bool) (from, to breakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = b.state
+	b.probing = false
+	switch {
+	case !failed:
+		b.state, b.failures = breakerClosed, 0
+	case b.state == breakerHalfOpen:
+		b.state, b.openedAt = breakerOpen, b.now()
+		b.trips++
+	case b.state == breakerClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state, b.openedAt, b.failures = breakerOpen, b.now(), 0
+			b.trips++
+		}
+	}
+	return from, b.state
+}
+
+// skip - 결과를 판단하지 않음 (half-open 복구 확인 요청이면 다른 요청이 다시 확인하도록 풀어 줌)
+func (b *circuitBreaker) skip() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+// stats - 현재 상태와 열린 횟수
+func (b *circuitBreaker) stats() (breakerState, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.trips
+}
+
+// circuitBreaker - BreakerThreshold 로 만든 공유 회로 차단기 (0 이면 nil, 첫 요청 시 설정을 읽음)
+func (c *Collector) circuitBreaker() *circuitBreaker {
+	c.breakerOnce.Do(func() {
+		if c.BreakerThreshold > 0 {
+			c.breaker = &circuitBreaker{threshold: c.BreakerThreshold, cooldown: c.BreakerCooldown, now: c.now}
+		}
+	})
+	return c.breaker
+}
+
+// recordFetch - fetchCandles 결과를 회로 차단기에 반영하고 상태가 바뀌면 로그 출력
+//
+// 취소, 요청 예산 소진, 점검(503)은 MaintenanceBackoff 등 다른 경로가 처리하므로 실패로 세지 않는다.
+func (c *Collector) recordFetch(ctx context.Context, b *circuitBreaker, tf Timeframe, err error) {
+	if ctx.Err() != nil || errors.Is(err, errRequestBudget) ||
+		responseStatus(err) == http.StatusServiceUnavailable {
+		b.skip()
+		return
+	}
+
+	from, to := b.record(err != nil)
+	if from == to {
+		return
+	}
+	switch to {
+	case breakerOpen:
+		fmt.Fprintf(c.Output, "[%s] %s 회로 차단기 열림 - API 요청 연속 실패, %v 동안 요청 없이 실패 처리: %v\n",
+			tf.Name, c.mark(markFail), c.BreakerCooldown, err)
+	case breakerClosed:
+		fmt.Fprintf(c.Output, "[%s] %s 회로 차단기 닫힘 - API 요청 복구\n", tf.Name, c.mark(markOK))
+	}
+}
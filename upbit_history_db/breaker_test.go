@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchCandlesCircuitBreaker(t *testing.T) {
+	var calls atomic.Int64
+	var down atomic.Bool
+	down.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if down.Load() {
+			// 인증 취소 - 재시도하지 않는 오류
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":{"name":"invalid_access_key","message":"revoked"}}`)
+			return
+		}
+		io.WriteString(w, `[]`)
+	}))
+	defer srv.Close()
+
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	c.apiURL = srv.URL
+	c.BreakerThreshold = 3
+	c.BreakerCooldown = time.Minute
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	tf := mustTimeframe(t, "day")
+	fetch := func() error {
+		_, err := c.fetchCandles(context.Background(), tf, "", nil)
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := fetch(); err == nil || errors.Is(err, errCircuitOpen) {
+			t.Fatalf("[%d] err = %v, want API 오류", i, err)
+		}
+	}
+	if state, trips := c.breaker.stats(); state != breakerOpen || trips != 1 {
+		t.Fatalf("연속 3회 실패 후 %s (열림 %d회), want open", state, trips)
+	}
+
+	// 열린 동안은 요청 없이 바로 실패
+	if err := fetch(); !errors.Is(err, errCircuitOpen) || calls.Load() != 3 {
+		t.Errorf("열린 상태 err = %v, 요청 %d회, want errCircuitOpen, 3회", err, calls.Load())
+	}
+
+	// cooldown 후 복구 확인 요청이 실패하면 다시 열림
+	now = now.Add(time.Minute)
+	if err := fetch(); err == nil || calls.Load() != 4 {
+		t.Errorf("half-open 확인 err = %v, 요청 %d회", err, calls.Load())
+	}
+	if state, trips := c.breaker.stats(); state != breakerOpen || trips != 2 {
+		t.Errorf("확인 실패 후 %s (열림 %d회), want 다시 open", state, trips)
+	}
+
+	// 서버가 복구되면 다음 확인 요청으로 닫힘
+	down.Store(false)
+	now = now.Add(time.Minute)
+	if err := fetch(); err != nil {
+		t.Fatalf("복구 후 요청 실패: %v", err)
+	}
+	if state, _ := c.breaker.stats(); state != breakerClosed {
+		t.Errorf("복구 후 %s, want closed", state)
+	}
+	if err := fetch(); err != nil || calls.Load() != 6 {
+		t.Errorf("닫힌 뒤 err = %v, 요청 %d회, want 6", err, calls.Load())
+	}
+	log := out.String()
+	if strings.Count(log, "회로 차단기 열림") != 2 || !strings.Contains(log, "회로 차단기 닫힘") {
+		t.Errorf("상태 변경 로그:\n%s", log)
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsOneProbe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := &circuitBreaker{threshold: 1, cooldown: time.Second, now: func() time.Time { return now }}
+	b.record(true)
+	now = now.Add(time.Second)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("cooldown 후 첫 요청 거부: %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("확인 중 두 번째 요청 err = %v, want errCircuitOpen", err)
+	}
+	// 판단하지 않은 확인 요청(취소 등)이면 다른 요청이 다시 확인
+	b.skip()
+	if err := b.allow(); err != nil {
+		t.Errorf("skip 후 확인 요청 거부: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// errRequestBudget - MaxRequests 예산을 다 써서 요청하지 않음
+var errRequestBudget = errors.New("API 요청 예산 소진")
+
+// requestBudget - CollectAll 1회 실행의 API 요청 예산 (모든 시간단위가 공유)
+type requestBudget struct {
+	limit int64
+	used  atomic.Int64
+
+	mu    sync.Mutex
+	spent map[string]int // label(시간단위) 별 사용 요청 수
+}
+
+func newRequestBudget(limit int) *requestBudget {
+	return &requestBudget{limit: int64(limit), spent: make(map[string]int)}
+}
+
+// take - 요청 1회분을 차감 (남은 예산이 없으면 false, 차감하지 않음)
+func (b *requestBudget) take(label string) bool {
+	if b.used.Add(1) > b.limit {
+		b.used.Add(-1)
+		return false
+	}
+	b.mu.Lock()
+	b.spent[label]++
+	b.mu.Unlock()
+	return true
+}
+
+// spentBy - label 이 사용한 요청 수
+func (b *requestBudget) spentBy(label string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent[label]
+}
+
+// ensureCheckpointTable - 예산 소진으로 멈춘 수집 위치 테이블 (기본 DB 파일에 생성)
+//
+// 다음 수집은 최신 캔들부터 받다가 이미 있는 구간에 닿으면 그보다 오래된 체크포인트 중
+// 가장 최근 것부터 이어서 받는다. 여러 번 멈추면 체크포인트가 여러 개 쌓일 수 있다.
+func (c *Collector) ensureCheckpointTable() error {
+	_, err := c.db.Exec(`
+		CREATE TABLE IF NOT EXISTS collect_checkpoints (
+			market TEXT NOT NULL,
+			timeframe TEXT NOT NULL,
+			oldest TEXT NOT NULL,
+			cursor TEXT NOT NULL,
+			PRIMARY KEY (market, timeframe, oldest)
+		)
+	`)
+	return err
+}
+
+// saveCheckpoint - cur 위치(마지막으로 저장한 페이지)를 체크포인트로 기록
+func (c *Collector) saveCheckpoint(tf Timeframe, cur *collectCursor) error {
+	if cur.prevOldest == "" {
+		return nil
+	}
+	if err := c.ensureCheckpointTable(); err != nil {
+		return err
+	}
+	_, err := c.db.Exec("INSERT OR REPLACE INTO collect_checkpoints (market, timeframe, oldest, cursor) VALUES (?, ?, ?, ?)",
+		c.market, tf.Name, cur.prevOldest, cur.toTimestamp)
+	return err
+}
+
+// takeCheckpoint - before(KST) 이하인 체크포인트 중 가장 최근 것을 꺼냄 (꺼낸 체크포인트는 삭제)
+//
+// 첫 페이지만 받고 멈춘 경우 다음 실행의 첫 페이지 oldest 가 체크포인트와 같으므로 같은 시각도 포함한다.
+func (c *Collector) takeCheckpoint(tf Timeframe, before string) (oldest, cursor string, ok bool, err error) {
+	if err := c.ensureCheckpointTable(); err != nil {
+		return "", "", false, err
+	}
+	err = c.db.QueryRow(`
+		SELECT oldest, cursor FROM collect_checkpoints
+		WHERE market = ? AND timeframe = ? AND oldest <= ?
+		ORDER BY oldest DESC LIMIT 1
+	`, c.market, tf.Name, before).Scan(&oldest, &cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+	_, err = c.db.Exec("DELETE FROM collect_checkpoints WHERE market = ? AND timeframe = ? AND oldest = ?",
+		c.market, tf.Name, oldest)
+	return oldest, cursor, err == nil, err
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectAllRespectsRequestBudget(t *testing.T) {
+	c, f := newTestCollector(t)
+	c.MaxRequests = 10
+
+	results := c.CollectAll()
+	if n := f.calls.Load(); n > 10 {
+		t.Errorf("요청 %d회, 예산 10회를 넘음", n)
+	}
+	total := 0
+	for _, r := range results {
+		total += r.Requests
+	}
+	if total != int(f.calls.Load()) {
+		t.Errorf("시간단위별 사용량 합 %d, want 실제 요청 %d", total, f.calls.Load())
+	}
+
+	// 예산이 끊긴 시간단위는 다음 실행에서 이어서 수집할 체크포인트를 남김
+	var checkpoints int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM collect_checkpoints").Scan(&checkpoints); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoints == 0 {
+		t.Error("예산 소진 후 체크포인트가 없음")
+	}
+	if c.budget != nil {
+		t.Error("CollectAll 후에도 예산이 남아 있음")
+	}
+}
+
+func TestCollectTimeframeTimeoutSavesCheckpoint(t *testing.T) {
+	c, f := newTestCollector(t)
+	var out bytes.Buffer
+	c.Output = &out
+	// 가짜 시계 - 요청마다 1분씩 흐름
+	start := time.Now()
+	var minutes atomic.Int64
+	c.now = func() time.Time { return start.Add(time.Duration(minutes.Load()) * time.Minute) }
+	fake := f.handler(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		minutes.Add(1)
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c.apiURL = srv.URL
+	c.PerTimeframeTimeout = 150 * time.Second
+	tf := mustTimeframe(t, "minute1")
+
+	result := c.collectTimeframe(tf)
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if !result.TimedOut || result.Pages != 3 || result.Saved != 600 {
+		t.Errorf("timed_out = %v, pages = %d, saved = %d, want true, 3, 600", result.TimedOut, result.Pages, result.Saved)
+	}
+	if !strings.Contains(out.String(), "시간 제한(2m30s) 초과") {
+		t.Errorf("시간 제한 로그 없음:\n%s", out.String())
+	}
+	var oldest string
+	if err := c.db.QueryRow("SELECT oldest FROM collect_checkpoints WHERE timeframe = 'minute1'").Scan(&oldest); err != nil {
+		t.Fatalf("체크포인트 없음: %v", err)
+	}
+	if want := f.head.Add(9*time.Hour - 599*time.Minute).Format(timestampLayout); oldest != want {
+		t.Errorf("체크포인트 oldest = %s, want 마지막 저장 페이지 %s", oldest, want)
+	}
+
+	// 다음 실행은 이미 있는 구간을 만나면 체크포인트부터 이어서 수집
+	c.PerTimeframeTimeout = 0
+	c.MaxPages = 2
+	if result := c.collectTimeframe(tf); result.Err != nil || result.Saved != 200 {
+		t.Errorf("재개 saved = %d, err = %v, want 체크포인트 이후 200", result.Saved, result.Err)
+	}
+	if n := countRows(t, c, tf, ""); n != 800 {
+		t.Errorf("캔들 %d개, want 800 (빈틈 없이 이어짐)", n)
+	}
+}
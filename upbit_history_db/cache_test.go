@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetCandlesCache(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC").WithCache(2)
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, kstMinutes(t0, 3)...)
+	get := func(from time.Time) []Candle {
+		t.Helper()
+		candles, err := c.GetCandles(tf, from, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return candles
+	}
+	queries := func(want int64, context string) {
+		t.Helper()
+		if got := c.queries.Load(); got != want {
+			t.Errorf("%s: DB 조회 %d회, want %d", context, got, want)
+		}
+	}
+
+	// 같은 조건 두 번이면 DB 는 한 번만 읽음
+	first, second := get(time.Time{}), get(time.Time{})
+	if len(first) != 3 || len(second) != 3 {
+		t.Fatalf("조회 %d개, %d개, want 3", len(first), len(second))
+	}
+	queries(1, "같은 조회 두 번")
+	second[0].TradePrice = -1
+	if get(time.Time{})[0].TradePrice == -1 {
+		t.Error("캐시 슬라이스가 호출자와 공유됨")
+	}
+	queries(1, "같은 조회 세 번")
+
+	// 저장하면 해당 시간단위 캐시 무효화, 다음 조회는 DB 를 다시 읽고 새 캔들을 봄
+	seed(t, c, tf, t0.Add(3*time.Minute))
+	if n := len(get(time.Time{})); n != 4 {
+		t.Errorf("저장 후 %d개, want 4", n)
+	}
+	queries(2, "저장 후 조회")
+	get(time.Time{})
+	queries(2, "저장 후 같은 조회")
+
+	// 최대 2개: 세 번째 조건을 넣으면 가장 오래된 조건이 밀려나 다시 DB 를 읽음
+	get(t0)
+	get(t0.Add(time.Minute))
+	queries(4, "다른 조건 두 개")
+	get(t0.Add(time.Minute))
+	queries(4, "캐시에 남은 조건")
+	get(time.Time{})
+	queries(5, "밀려난 조건")
+}
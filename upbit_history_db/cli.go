@@ -0,0 +1,446 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// command - 서브커맨드 정의
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "collect", usage: "캔들 수집 (기본: 전체 시간단위)", run: runCollect},
+	{name: "update", usage: "모든 시간단위를 마지막 캔들부터 최신 마감 캔들까지 갱신 후 새 구간 보간 (cron 용)", run: runUpdate},
+	{name: "refetch-gaps", usage: "빈 구간을 업비트에서 다시 받아 채우고 남은 구간만 보간", run: runRefetchGaps},
+	{name: "backtest", usage: "저장된 캔들로 SMA 교차 전략 백테스트", run: runBacktest},
+	{name: "grid-search", usage: "SMA 교차 기간 조합별 백테스트 후 Sharpe/수익률/낙폭 순 정렬", run: runGridSearch},
+	{name: "benchmark", usage: "API 응답 지연/처리량 측정 (DB 저장 없음)", run: runBenchmark},
+	{name: "markets", usage: "업비트 마켓 코드 목록 (--quote KRW 로 거르기)", run: runMarkets},
+	{name: "ticks", usage: "최근 체결 내역을 ticks_<마켓> 테이블에 수집", run: runTicks},
+	{name: "export", usage: "저장된 캔들을 CSV/JSONL/바이너리 덤프로 내보내기", run: runExport},
+	{name: "import", usage: "export 형식 CSV 나 바이너리 덤프를 캔들로 가져오기 (경계 보정/거부)", run: runImport},
+	{name: "live", usage: "새 캔들을 실시간으로 받아 SMA 교차 신호 알림", run: runLive},
+	{name: "replay", usage: "저장된 캔들을 실시간처럼 재생하며 SMA 교차 신호 확인 (--speed 배속)", run: runReplay},
+	{name: "diff", usage: "CSV 백업과 DB 캔들 비교", run: runDiff},
+	{name: "serve", usage: "수집한 캔들을 제공하는 읽기 전용 REST API 서버", run: runServe},
+	{name: "spec", usage: "serve API 의 OpenAPI 3 스펙(JSON) 출력", run: runSpec},
+	{name: "coverage", usage: "시간단위별 실제/보간/누락 캔들 비율", run: runCoverage},
+	{name: "histogram", usage: "연/월별 실제/보간 캔들 수 분포", run: runHistogram},
+	{name: "volume-profile", usage: "분 단위 캔들의 KST 시각별 평균 거래량", run: runVolumeProfile},
+	{name: "stats", usage: "시간단위별 저장 현황 출력", run: runStats},
+	{name: "indicators", usage: "모든 시간단위 지표를 계산해 indicators 테이블에 저장", run: runIndicators},
+	{name: "export-indicator", usage: "지표 시리즈를 CSV/JSON 으로 내보내기 (저장된 값 또는 즉시 계산)", run: runExportIndicator},
+	{name: "returns", usage: "캔들별 로그 수익률을 candle_returns 테이블에 저장 (바뀐 행만 갱신)", run: runReturns},
+	{name: "range-pct", usage: "캔들별 (고가-저가)/종가 를 range_pct 컬럼에 저장 (새 캔들만 계산)", run: runRangePct},
+	{name: "anomalies", usage: "이동 중앙값 기준 가격 이상치(스파이크) 검사", run: runAnomalies},
+	{name: "reinterpolate", usage: "모든 시간단위의 보간 캔들을 현재 보간 방식으로 다시 생성", run: runReinterpolate},
+	{name: "recompute-flags", usage: "보간 캔들 삭제 후 보간 재실행으로 is_interpolated 플래그 재계산 (--force 필요)", run: runRecomputeFlags},
+	{name: "retention", usage: "오래된 캔들을 상위 시간단위로 합치기 (--delete 로 원본 삭제)", run: runRetention},
+	{name: "validate-aggregation", usage: "하위 시간단위를 합친 OHLCV 와 저장된 상위 캔들 비교 (예: minute1 → day)", run: runValidateAggregation},
+	{name: "migrate-epoch", usage: "기존 캔들 테이블에 timestamp_ms(정수) 컬럼 추가 및 변환", run: runMigrateEpoch},
+	{name: "fill-to-now", usage: "마지막 캔들 이후 현재 구간까지 직전 종가로 채우기 (--clear 로 삭제)", run: runFillToNow},
+	{name: "reset", usage: "한 시간단위 테이블 삭제 후 재생성 (--force 필요)", run: runReset},
+}
+
+// run - 서브커맨드 분기 (서브커맨드 없이 실행하면 collect 와 동일)
+func run(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runCollect(args)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	if args[0] == "help" {
+		printUsage()
+		return nil
+	}
+
+	printUsage()
+	return fmt.Errorf("알 수 없는 명령: %s", args[0])
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "사용법: upbit-collector <명령> [옵션]")
+	fmt.Fprintln(os.Stderr, "\n명령:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\n각 명령의 옵션은 'upbit-collector <명령> -h' 로 확인")
+}
+
+// commonFlags - 모든 서브커맨드 공통 옵션
+type commonFlags struct {
+	dbPath      string
+	market      string
+	plain       bool
+	shardByYear bool
+	epoch       bool
+	quiet       bool // 연결/종료 안내 출력 생략
+}
+
+func (f *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.dbPath, "db", "upbit_bitcoin.db", "SQLite 데이터베이스 경로")
+	fs.StringVar(&f.market, "market", defaultMarket, "마켓 코드 (예: KRW-BTC, KRW-ETH)")
+	fs.BoolVar(&f.plain, "plain", false, "이모지 없이 ASCII 접두어([INFO]/[WARN]/[OK])로 출력")
+	fs.BoolVar(&f.shardByYear, "shard-by-year", false, "캔들을 연도별 DB 파일(<db>_2021.db 등)로 나눠 저장")
+	fs.BoolVar(&f.epoch, "epoch-timestamps", false, "timestamp_ms(정수) 컬럼으로 저장/범위 조회 (없으면 변환 후 사용)")
+}
+
+// open - 공통 옵션으로 Collector 생성
+func (f *commonFlags) open() (*Collector, error) {
+	collector, err := NewCollector(f.dbPath, f.market)
+	if err != nil {
+		return nil, fmt.Errorf("데이터베이스 초기화 실패: %w", err)
+	}
+	collector.PlainOutput = f.plain
+	if f.shardByYear {
+		if err := collector.EnableYearSharding(); err != nil {
+			collector.Close()
+			return nil, fmt.Errorf("연도별 DB 초기화 실패: %w", err)
+		}
+	}
+	if f.epoch {
+		if err := collector.EnableEpochTimestamps(); err != nil {
+			collector.Close()
+			return nil, err
+		}
+	}
+	collector.quiet = f.quiet
+	if !f.quiet {
+		fmt.Println(collector.mark(markOK) + " 데이터베이스 초기화 완료")
+	}
+	return collector, nil
+}
+
+func runCollect(args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "해당 시간단위만 수집 (보간 생략, 예: minute1)")
+	pages := fs.Int("pages", 0, "시간단위별 최대 요청 페이지 수 (0 = 제한 없음)")
+	concurrency := fs.Int("concurrency", 0, "동시에 수집할 시간단위 수 (0 = 전체 동시)")
+	rate := fs.Int("rate", 9, "초당 최대 API 요청 수 (업비트 제한: 10)")
+	since := fs.String("since", "", "이 날짜(KST, YYYY-MM-DD)부터 현재까지 수집 (기본: 2019-01-01)")
+	sinceTimeframes := fs.String("since-timeframe", "", "시간단위별 수집 시작 날짜 (쉼표 구분, 예: minute1=2024-01-01,day=2017-09-25)")
+	dryRun := fs.Bool("dry-run", false, "API 호출 없이 예상 요청 수/소요 시간만 출력")
+	strategy := fs.String("interpolation", InterpolateLinear.String(), "보간 방식 (linear, zero-volume)")
+	minInterpolation := fs.Int("min-interpolation-candles", 100, "실제 캔들이 이 개수보다 적은 시간단위는 보간하지 않음 (0 = 제한 없음)")
+	maintenance := fs.Duration("maintenance-backoff", 5*time.Minute, "업비트 점검(503 지속) 시 대기 시간 (0 = 대기 없이 실패)")
+	stall := fs.Duration("stall-timeout", 3*time.Minute, "시간단위 수집이 이 시간 동안 진행이 없으면 요청 취소 후 재시작 (0 = 감시 안 함)")
+	timeframeTimeout := fs.Duration("timeframe-timeout", 0, "시간단위 하나의 최대 수집 시간 (넘으면 체크포인트 저장 후 그 시간단위만 중단, 0 = 제한 없음)")
+	breakerThreshold := fs.Int("breaker-threshold", 5, "캔들 요청이 연속 이만큼 실패하면 회로 차단기를 열어 요청 중단 (0 = 사용 안 함)")
+	breakerCooldown := fs.Duration("breaker-cooldown", time.Minute, "회로 차단기가 열린 뒤 복구 확인 요청까지 기다리는 시간")
+	retryBudget := fs.Int("retry-budget", 60, "모든 시간단위가 합쳐 분당 할 수 있는 최대 재시도 수 (넘으면 재시도 없이 실패, 0 = 제한 없음)")
+	failedRetries := fs.Int("failed-retries", 1, "전체 수집 후 오류로 끝난 시간단위만 다시 수집하는 횟수 (0 = 다시 시도 안 함)")
+	maxRequests := fs.Int("max-requests", 0, "이번 실행의 전체 API 요청 수 상한 (0 = 제한 없음, 소진 시 체크포인트 저장 후 중단)")
+	jitter := fs.Duration("jitter", 0, "캔들 요청마다 더하는 무작위 지연의 최대값 (예: 200ms, 시간단위 goroutine 요청이 한꺼번에 몰리지 않도록, 0 = 사용 안 함)")
+	summary := fs.String("summary", "", "전체 수집 후 실행 요약 JSON 경로 (예: last_run.json, 여러 마켓이면 마켓 코드가 붙음)")
+	completionWebhook := fs.String("completion-webhook", "", "전체 수집 후 실행 요약을 POST 할 URL (실패해도 수집 결과에 영향 없음)")
+	onConflict := fs.String("on-conflict", ConflictIgnore.String(), "이미 있는 캔들 처리 (ignore, replace, error)")
+	roundPrice := fs.Float64("round-price", 0, "저장 전 가격을 이 단위의 배수로 반올림 (예: 1 = 원 단위, 0 = 반올림 안 함)")
+	roundVolume := fs.Int("round-volume", -1, "저장 전 거래량 소수 자릿수 (예: 8, -1 = 반올림 안 함)")
+	roundValue := fs.Int("round-value", -1, "저장 전 거래대금 소수 자릿수 (예: 0, -1 = 반올림 안 함)")
+	markets := fs.String("markets", "", "여러 마켓을 하나의 요청 제한으로 동시 수집 (쉼표 구분, 예: KRW-BTC,KRW-ETH)")
+	storeReturns := fs.Bool("store-returns", false, "수집/보간 후 candle_returns 테이블의 로그 수익률 갱신 (returns 서브커맨드와 동일)")
+	progress := fs.Bool("progress", false, "시간단위별 진행 막대 표시 (터미널이 아니면 일반 로그)")
+	forward := fs.Bool("forward", false, "--timeframe 을 --since 날짜부터 현재 방향으로 수집 (중간에 멈춰도 앞부분이 연속)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	interpolation, err := parseInterpolationStrategy(*strategy)
+	if err != nil {
+		return err
+	}
+	conflict, err := parseConflictMode(*onConflict)
+	if err != nil {
+		return err
+	}
+	if *rate <= 0 {
+		return fmt.Errorf("--rate 는 1 이상이어야 합니다")
+	}
+	var rounding *RoundingPolicy
+	if *roundPrice != 0 || *roundVolume >= 0 || *roundValue >= 0 {
+		rounding = &RoundingPolicy{
+			PriceTick:      *roundPrice,
+			RoundVolume:    *roundVolume >= 0,
+			VolumeDecimals: *roundVolume,
+			RoundValue:     *roundValue >= 0,
+			ValueDecimals:  *roundValue,
+		}
+		if err := rounding.validate(); err != nil {
+			return fmt.Errorf("잘못된 반올림 설정: %w", err)
+		}
+	}
+
+	var sinceTime time.Time
+	if *since != "" {
+		if sinceTime, err = time.Parse("2006-01-02", *since); err != nil {
+			return fmt.Errorf("잘못된 --since 날짜: %w", err)
+		}
+	}
+
+	stopDates, err := parseTimeframeDates(*sinceTimeframes)
+	if err != nil {
+		return err
+	}
+
+	var tf Timeframe
+	if *timeframe != "" {
+		if tf, err = findTimeframe(*timeframe); err != nil {
+			return err
+		}
+	}
+	if *forward && (*timeframe == "" || *markets != "" || *dryRun) {
+		return fmt.Errorf("--forward 는 --timeframe 과 함께 써야 하며 --markets, --dry-run 과 함께 쓸 수 없습니다")
+	}
+
+	configure := func(collector *Collector) {
+		collector.MaxPages = *pages
+		collector.MaxConcurrency = *concurrency
+		collector.Interpolation = interpolation
+		collector.MinInterpolationCandles = *minInterpolation
+		collector.Conflict = conflict
+		collector.Rounding = rounding
+		collector.rateLimiter = NewRateLimiter(*rate)
+		collector.MaintenanceBackoff = *maintenance
+		collector.StallTimeout = *stall
+		collector.PerTimeframeTimeout = *timeframeTimeout
+		collector.MaxRequests = *maxRequests
+		collector.BreakerThreshold = *breakerThreshold
+		collector.BreakerCooldown = *breakerCooldown
+		collector.RetryBudget = *retryBudget
+		collector.FailedTimeframeRetries = *failedRetries
+		collector.RequestJitter = *jitter
+		collector.StoreReturns = *storeReturns
+		collector.SummaryPath = *summary
+		collector.CompletionWebhook = *completionWebhook
+		if *summary != "" && *markets != "" {
+			ext := filepath.Ext(*summary)
+			collector.SummaryPath = strings.TrimSuffix(*summary, ext) + "_" + collector.market + ext
+		}
+		if !sinceTime.IsZero() {
+			collector.StopBefore = sinceTime
+		}
+		collector.StopBeforeTimeframes = stopDates
+	}
+
+	if *markets != "" {
+		if *timeframe != "" || *dryRun || *progress {
+			return fmt.Errorf("--markets 는 --timeframe, --dry-run, --progress 와 함께 쓸 수 없습니다")
+		}
+		return collectMarkets(common, strings.Split(*markets, ","), configure, NewRateLimiter(*rate))
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	configure(collector)
+	defer closeOnSignal(collector)()
+
+	if *dryRun {
+		plan, err := collector.PlanCollection()
+		if err != nil {
+			return err
+		}
+		if *timeframe != "" {
+			plan = plan.only(tf)
+		}
+		collector.printPlan(plan)
+		return nil
+	}
+
+	collect := func() []CollectResult {
+		if *timeframe != "" {
+			var result CollectResult
+			if *forward {
+				result = collector.CollectForward(tf, collector.stopBefore(tf))
+			} else {
+				result = collector.collectTimeframe(tf)
+			}
+			if *storeReturns && result.Err == nil {
+				if _, err := collector.ComputeReturns(tf); err != nil {
+					result.Err = fmt.Errorf("수익률 계산 실패: %w", err)
+				}
+			}
+			return []CollectResult{result}
+		}
+		if !sinceTime.IsZero() {
+			return collector.CollectSince(sinceTime)
+		}
+		return collector.CollectAll()
+	}
+	if !*progress {
+		return failedResults(collect())
+	}
+
+	var results []CollectResult
+	collector.withProgressBar(func() { results = collect() })
+	return failedResults(results)
+}
+
+// parseTimeframeDates - "minute1=2024-01-01,day=2017-09-25" 형식 (빈 문자열이면 nil)
+func parseTimeframeDates(text string) (map[string]time.Time, error) {
+	if text == "" {
+		return nil, nil
+	}
+	dates := make(map[string]time.Time)
+	for _, part := range strings.Split(text, ",") {
+		name, date, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("시간단위별 날짜 형식은 시간단위=YYYY-MM-DD 입니다: %q", part)
+		}
+		tf, err := findTimeframe(name)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("잘못된 %s 날짜: %w", tf.Name, err)
+		}
+		dates[tf.Name] = t
+	}
+	return dates, nil
+}
+
+// collectMarkets - 마켓별 Collector 를 열어 CollectAllMarkets 로 동시 수집
+func collectMarkets(common commonFlags, markets []string, configure func(*Collector), limiter *RateLimiter) error {
+	var collectors []*Collector
+	defer func() {
+		for _, collector := range collectors {
+			collector.Close()
+		}
+	}()
+
+	for _, market := range markets {
+		common.market = strings.TrimSpace(market)
+		collector, err := common.open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", common.market, err)
+		}
+		configure(collector)
+		collectors = append(collectors, collector)
+	}
+
+	defer closeOnSignal(collectors...)()
+
+	var errs []error
+	for i, results := range CollectAllMarkets(collectors, limiter) {
+		if err := failedResults(results); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", collectors[i].market, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runReset(args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "초기화할 시간단위 (필수, 예: minute1)")
+	force := fs.Bool("force", false, "실제로 삭제 (지정하지 않으면 아무것도 하지 않음)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if !*force {
+		return fmt.Errorf("%s 데이터가 모두 삭제됩니다. 계속하려면 --force 를 지정하세요", tf.Name)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	if err := collector.ResetTimeframe(tf); err != nil {
+		return fmt.Errorf("%s 초기화 실패: %w", tf.Name, err)
+	}
+	fmt.Printf("[%s] %s 테이블 초기화 완료\n", tf.Name, collector.mark(markOK))
+	return nil
+}
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	asJSON := fs.Bool("json", false, "JSON 으로 출력")
+	recompute := fs.Bool("recompute", false, "timeframe_summary 요약을 전체 테이블 집계로 다시 계산한 뒤 출력")
+	quality := fs.Bool("quality", false, "JSON 출력에 시간단위별 데이터 품질 점수 포함 (전체 캔들을 읽으므로 큰 DB 에서는 느림)")
+	qualityTimeframe := fs.String("timeframe", "", "품질 점수를 계산할 시간단위 (--quality, 기본: 전체)")
+	qualityWeights := fs.String("quality-weights", "40,20,20,20", "품질 점수 가중치 (커버리지,보간,이상치,OHLC 유효성)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	weights, err := parseQualityWeights(*qualityWeights)
+	if err != nil {
+		return fmt.Errorf("잘못된 --quality-weights: %w", err)
+	}
+	if *qualityTimeframe != "" {
+		if _, err := findTimeframe(*qualityTimeframe); err != nil {
+			return err
+		}
+	}
+
+	common.quiet = *asJSON
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.QualityWeights = weights
+
+	if *recompute {
+		if err := collector.RecomputeStatistics(); err != nil {
+			return err
+		}
+	}
+
+	if !*asJSON {
+		collector.PrintStatistics()
+		return nil
+	}
+
+	stats, err := collector.Statistics()
+	if err != nil {
+		return err
+	}
+	if *quality {
+		for i, tf := range timeframes {
+			if *qualityTimeframe != "" && tf.Name != *qualityTimeframe {
+				continue
+			}
+			score, err := collector.QualityScore(tf)
+			if err != nil {
+				return fmt.Errorf("%s 품질 점수 계산 실패: %w", tf.Name, err)
+			}
+			stats[i].Quality = &score
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectTimeframeMaxPages(t *testing.T) {
+	c, f := newTestCollector(t)
+	c.MaxPages = 3
+
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if got := f.calls.Load(); got != 3 {
+		t.Errorf("요청 수 = %d, want 3", got)
+	}
+	if result.Pages != 3 || result.Saved != 600 {
+		t.Errorf("pages = %d, saved = %d, want 3, 600", result.Pages, result.Saved)
+	}
+}
+
+func TestCollectTimeframeStopsAtListing(t *testing.T) {
+	c, f := newTestCollector(t)
+	f.floor = f.head.Add(-449 * time.Minute)
+
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if result.Saved != 450 {
+		t.Errorf("saved = %d, want 450", result.Saved)
+	}
+}
+
+func TestCollectSince(t *testing.T) {
+	c, f := newTestCollector(t)
+	since := f.head.Add(9*time.Hour - 49*time.Hour).Truncate(time.Hour)
+	prev := c.StopBefore
+
+	for _, result := range c.CollectSince(since) {
+		if result.Err != nil {
+			t.Fatalf("%s: %v", result.Timeframe, result.Err)
+		}
+	}
+	if !c.StopBefore.Equal(prev) {
+		t.Errorf("CollectSince 후 StopBefore = %v, want %v", c.StopBefore, prev)
+	}
+	for _, tf := range timeframes {
+		if n := countRows(t, c, tf, "timestamp < ?", since.Format(timestampLayout)); n != 0 {
+			t.Errorf("%s: since 이전 캔들 %d개", tf.Name, n)
+		}
+	}
+	if n := countRows(t, c, mustTimeframe(t, "minute1"), ""); n < 48*60 {
+		t.Errorf("minute1 캔들 %d개, since 이후 전체가 있어야 함", n)
+	}
+}
+
+func TestCollectTimeframeMaintenanceBackoff(t *testing.T) {
+	if testing.Short() {
+		t.Skip("503 재시도 백오프를 기다림 (약 4초)")
+	}
+	c, f := newTestCollector(t)
+	var out bytes.Buffer
+	c.Output = &out
+	c.MaxPages = 2
+	c.MaintenanceBackoff = 50 * time.Millisecond
+
+	// 재시도 한 바퀴(4회)가 모두 503 이라 점검 대기, 재개 후 한 번 더 503 뒤 복구
+	fake := f.handler(t)
+	var calls atomic.Int64
+	var resumedTo atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := calls.Add(1); {
+		case n <= 5:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case n == 6:
+			resumedTo.Store(r.URL.Query().Get("to"))
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c.apiURL = srv.URL
+
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	if result.Err != nil {
+		t.Fatalf("점검 후 복구됐는데 실패: %v", result.Err)
+	}
+	if result.Pages != 2 || result.Saved != 400 {
+		t.Errorf("pages = %d, saved = %d, want 2, 400", result.Pages, result.Saved)
+	}
+	if to := resumedTo.Load(); to != "" {
+		t.Errorf("재개 요청 to = %q, want 점검 전과 같은 첫 페이지", to)
+	}
+	log := out.String()
+	if strings.Count(log, "업비트 점검 중 (503)") != 1 || !strings.Contains(log, "점검 종료, 수집 재개") {
+		t.Errorf("점검 대기 로그가 없음:\n%s", log)
+	}
+}
+
+func TestPageCursor(t *testing.T) {
+	if got := pageCursor("2024-01-01T00:00:00"); got != "2023-12-31T23:59:59Z" {
+		t.Errorf("pageCursor = %q, want ISO8601 UTC 1초 전", got)
+	}
+}
+
+// inclusiveUpbit - to 시각에 걸친 캔들까지 포함해 돌려주는 서버 (head 부터 500분 전까지 501개)
+//
+// roundUp 이면 to 를 분 단위로 올림해서, 1초 전 cursor 를 보내도 경계 캔들이 다시 오는 경우를 흉내낸다.
+func inclusiveUpbit(t *testing.T, head time.Time, roundUp bool, pages *[][2]string) http.Handler {
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		to := head
+		if s := r.URL.Query().Get("to"); s != "" {
+			var err error
+			if to, err = time.Parse(time.RFC3339, s); err != nil {
+				t.Errorf("to 가 ISO8601 형식이 아님: %q", s)
+			}
+			if roundUp && to.Truncate(time.Minute) != to {
+				to = to.Truncate(time.Minute).Add(time.Minute)
+			}
+		}
+		out := []map[string]any{}
+		for ts := to.Truncate(time.Minute); len(out) < 200 && !ts.Before(head.Add(-500*time.Minute)); ts = ts.Add(-time.Minute) {
+			out = append(out, fakeCandleJSON("KRW-BTC", ts))
+		}
+		if len(out) > 0 {
+			mu.Lock()
+			*pages = append(*pages, [2]string{out[0]["candle_date_time_kst"].(string), out[len(out)-1]["candle_date_time_kst"].(string)})
+			mu.Unlock()
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+}
+
+func TestCollectTimeframePageBoundary(t *testing.T) {
+	head := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, roundUp := range []bool{false, true} {
+		var pages [][2]string
+		srv := httptest.NewServer(inclusiveUpbit(t, head, roundUp, &pages))
+		c := openTestDB(t, "KRW-BTC")
+		c.apiURL = srv.URL
+		c.now = func() time.Time { return head.Add(time.Hour) }
+
+		result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+		srv.Close()
+		if result.Err != nil || result.Saved != 501 {
+			t.Errorf("roundUp=%v: saved = %d, err = %v, want 501 (경계 캔들 때문에 일찍 멈추면 안 됨)", roundUp, result.Saved, result.Err)
+		}
+		if n := countRows(t, c, mustTimeframe(t, "minute1"), ""); n != 501 {
+			t.Errorf("roundUp=%v: 저장 %d개, want 501", roundUp, n)
+		}
+		if roundUp {
+			continue
+		}
+		// 정확한 cursor 면 다음 페이지 첫 캔들이 이전 페이지 가장 오래된 캔들보다 엄격히 과거
+		for i := 1; i < len(pages); i++ {
+			if pages[i][0] >= pages[i-1][1] {
+				t.Errorf("페이지 %d 첫 캔들 %s 가 이전 페이지 마지막 %s 보다 과거가 아님", i, pages[i][0], pages[i-1][1])
+			}
+		}
+	}
+}
+
+func TestCollectTimeframePerTimeframeStop(t *testing.T) {
+	c, f := newTestCollector(t)
+	headKST := f.head.Add(9 * time.Hour)
+	c.StopBefore = headKST.Add(-10 * time.Hour)
+	c.StopBeforeTimeframes = map[string]time.Time{
+		"minute1": headKST.Add(-100 * time.Minute),
+		"minute5": headKST.Add(-3 * time.Hour),
+	}
+
+	for _, tc := range []struct {
+		name string
+		stop time.Time
+	}{
+		{"minute1", c.StopBeforeTimeframes["minute1"]},
+		{"minute5", c.StopBeforeTimeframes["minute5"]},
+		{"minute60", c.StopBefore}, // 맵에 없으면 전역 StopBefore
+	} {
+		tf := mustTimeframe(t, tc.name)
+		if result := c.collectTimeframe(tf); result.Err != nil {
+			t.Fatalf("%s: %v", tc.name, result.Err)
+		}
+		if got := c.stopBefore(tf); !got.Equal(tc.stop) {
+			t.Errorf("%s: stopBefore = %v, want %v", tc.name, got, tc.stop)
+		}
+		stop := tc.stop.Format(timestampLayout)
+		if n := countRows(t, c, tf, "timestamp < ?", stop); n != 0 {
+			t.Errorf("%s: 하한 %s 이전 캔들 %d개", tc.name, stop, n)
+		}
+		// 하한 바로 위 한 간격 안까지는 수집해야 함 (일찍 멈추지 않음)
+		near := tc.stop.Add(time.Duration(tf.Minutes) * time.Minute).Format(timestampLayout)
+		if n := countRows(t, c, tf, "timestamp < ?", near); n != 1 {
+			t.Errorf("%s: 하한 근처 캔들 %d개, want 1", tc.name, n)
+		}
+	}
+	if n := countRows(t, c, mustTimeframe(t, "minute1"), ""); n != 101 {
+		t.Errorf("minute1 캔들 %d개, want 101 (100분 전부터 최신까지)", n)
+	}
+}
+
+func TestParseTimeframeDates(t *testing.T) {
+	dates, err := parseTimeframeDates("minute1=2024-01-01, day=2017-09-25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 2 || !dates["minute1"].Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!dates["day"].Equal(time.Date(2017, 9, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("dates = %v", dates)
+	}
+	for _, bad := range []string{"minute1", "minute7=2024-01-01", "day=2024/01/01"} {
+		if _, err := parseTimeframeDates(bad); err == nil {
+			t.Errorf("%q: 오류 없음", bad)
+		}
+	}
+}
+
+func TestCollectAllRetriesFailedTimeframes(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		failures    int64 // 일봉 요청이 처음 몇 번 실패하는지
+		wantRetries int
+		recovered   bool
+	}{
+		{"recovers", 1, 1, 1, true},
+		{"no retry", 0, 1, 0, false},
+		{"still failing", 2, 100, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, f := newTestCollector(t)
+			var out bytes.Buffer
+			c.Output = &out
+			c.MaxPages = 1
+			c.BreakerThreshold = 0
+			c.FailedTimeframeRetries = tt.retries
+			fake := f.handler(t)
+			var dayCalls atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/days") && dayCalls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":{"name":"invalid_query_payload","message":"bad"}}`))
+					return
+				}
+				fake.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+			c.apiURL = srv.URL
+
+			for _, r := range c.CollectAll() {
+				if r.Timeframe != "day" {
+					if r.Err != nil || r.Retries != 0 {
+						t.Errorf("%s: err = %v, retries = %d, want 첫 수집에서 성공", r.Timeframe, r.Err, r.Retries)
+					}
+					continue
+				}
+				if r.Retries != tt.wantRetries || (r.Err == nil) != tt.recovered {
+					t.Errorf("day: retries = %d, err = %v, want %d, 복구 %v", r.Retries, r.Err, tt.wantRetries, tt.recovered)
+				}
+				if tt.recovered && r.Saved == 0 {
+					t.Error("day: 다시 수집했는데 저장한 캔들이 없음")
+				}
+			}
+			if got := dayCalls.Load(); got != int64(1+tt.wantRetries) {
+				t.Errorf("일봉 요청 %d회, want %d", got, 1+tt.wantRetries)
+			}
+			if strings.Contains(out.String(), "다시 수집해 복구한 시간단위: day") != tt.recovered {
+				t.Errorf("복구 로그 = %v, want %v\n%s", !tt.recovered, tt.recovered, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Coverage - 시간단위별 첫~마지막 캔들 구간의 실제/보간/누락 비율
+type Coverage struct {
+	Timeframe       string  `json:"timeframe"`
+	Market          string  `json:"market"`
+	First           string  `json:"first,omitempty"`
+	Last            string  `json:"last,omitempty"`
+	Expected        int     `json:"expected"`
+	Real            int     `json:"real"`
+	Interpolated    int     `json:"interpolated"`
+	Missing         int     `json:"missing"`
+	CoveragePct     float64 `json:"coverage_pct"` // (실제 + 보간) / 예상
+	RealPct         float64 `json:"real_pct"`
+	InterpolatedPct float64 `json:"interpolated_pct"`
+	MissingPct      float64 `json:"missing_pct"`
+}
+
+// CoverageReport - 모든 시간단위의 커버리지 (누락 수는 FindGaps 기준, 예상 수는 간격으로 계산)
+func (c *Collector) CoverageReport() ([]Coverage, error) {
+	report := make([]Coverage, 0, len(timeframes))
+	for _, tf := range timeframes {
+		cov, err := c.coverage(tf)
+		if err != nil {
+			return nil, fmt.Errorf("%s 커버리지 계산 실패: %w", tf.Name, err)
+		}
+		report = append(report, cov)
+	}
+	return report, nil
+}
+
+func (c *Collector) coverage(tf Timeframe) (Coverage, error) {
+	cov := Coverage{Timeframe: tf.Name, Market: c.market}
+
+	stats, err := c.timeframeStats(tf)
+	if err != nil {
+		return cov, err
+	}
+	if stats.Total == 0 {
+		return cov, nil
+	}
+
+	gaps, err := c.FindGaps(tf)
+	if err != nil {
+		return cov, err
+	}
+	for _, gap := range gaps {
+		cov.Missing += gap.Missing
+	}
+
+	first, err := time.Parse(timestampLayout, stats.Oldest)
+	if err != nil {
+		return cov, err
+	}
+	last, err := time.Parse(timestampLayout, stats.Newest)
+	if err != nil {
+		return cov, err
+	}
+
+	cov.First, cov.Last = stats.Oldest, stats.Newest
+	cov.Real = stats.Original
+	cov.Interpolated = stats.Total - stats.Original
+	cov.Expected = expectedCandles(tf, first, last)
+	if cov.Expected < stats.Total+cov.Missing {
+		// 경계에 맞지 않는 timestamp 가 섞여 간격으로 센 개수보다 많은 경우 실제 개수 기준으로 보정
+		cov.Expected = stats.Total + cov.Missing
+	}
+
+	pct := func(n int) float64 { return float64(n) / float64(cov.Expected) * 100 }
+	cov.CoveragePct = pct(stats.Total)
+	cov.RealPct = pct(cov.Real)
+	cov.InterpolatedPct = pct(cov.Interpolated)
+	cov.MissingPct = pct(cov.Missing)
+	return cov, nil
+}
+
+func (c *Collector) printCoverage(report []Coverage) {
+	fmt.Fprintln(c.Output, "\n"+c.mark(markStats)+" 커버리지 ("+c.market+"):")
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  %-10s %12s %8s %8s %8s %8s\n", "timeframe", "expected", "total%", "real%", "interp%", "missing%")
+	for _, cov := range report {
+		if cov.Expected == 0 {
+			fmt.Fprintf(c.Output, "  %-10s %12s\n", cov.Timeframe, "-")
+			continue
+		}
+		fmt.Fprintf(c.Output, "  %-10s %12s %7.2f%% %7.2f%% %7.2f%% %7.2f%%\n", cov.Timeframe, formatNumber(cov.Expected),
+			cov.CoveragePct, cov.RealPct, cov.InterpolatedPct, cov.MissingPct)
+	}
+}
+
+func runCoverage(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	asJSON := fs.Bool("json", false, "JSON 으로 출력")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	common.quiet = *asJSON
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	report, err := collector.CoverageReport()
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		collector.printCoverage(report)
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
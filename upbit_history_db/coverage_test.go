@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoverageReport(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	day := mustTimeframe(t, "day")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	// 1/1 ~ 1/5 중 1/3, 1/4 가 빠짐
+	seed(t, c, day, t0, t0.AddDate(0, 0, 1), t0.AddDate(0, 0, 4))
+
+	dayCoverage := func() Coverage {
+		t.Helper()
+		report, err := c.CoverageReport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report) != len(timeframes) {
+			t.Fatalf("%d개, want 시간단위 %d개", len(report), len(timeframes))
+		}
+		for _, cov := range report {
+			if cov.Timeframe == "day" {
+				return cov
+			}
+			if cov.Expected != 0 {
+				t.Errorf("%s: 빈 테이블인데 expected = %d", cov.Timeframe, cov.Expected)
+			}
+		}
+		t.Fatal("day 커버리지 없음")
+		return Coverage{}
+	}
+
+	got := dayCoverage()
+	want := Coverage{
+		Timeframe: "day", Market: "KRW-BTC",
+		First: t0.Format(timestampLayout), Last: t0.AddDate(0, 0, 4).Format(timestampLayout),
+		Expected: 5, Real: 3, Missing: 2,
+		CoveragePct: 60, RealPct: 60, MissingPct: 40,
+	}
+	if got != want {
+		t.Errorf("빈 구간 있는 커버리지 = %+v\nwant %+v", got, want)
+	}
+
+	// 보간으로 채우면 누락이 보간으로 바뀜
+	if _, err := c.interpolateMissingData(day); err != nil {
+		t.Fatal(err)
+	}
+	got = dayCoverage()
+	if got.Missing != 0 || got.Interpolated != 2 || got.CoveragePct != 100 || got.InterpolatedPct != 40 {
+		t.Errorf("보간 후 커버리지 = %+v", got)
+	}
+}
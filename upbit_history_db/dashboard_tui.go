@@ -0,0 +1,160 @@
+//go:build tui
+
+// 터미널 대시보드 - 기본 바이너리에는 포함되지 않음 (go build -tags tui -o upbit-collector .)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+func init() {
+	commands = append(commands, command{name: "dashboard", usage: "전체 수집을 실행하며 터미널 대시보드로 진행 상황 표시", run: runDashboard})
+}
+
+// timeframeRow - 대시보드 한 줄 (시간단위별 마지막 진행 이벤트)
+type timeframeRow struct {
+	event   ProgressEvent
+	started bool
+}
+
+// dashboard - ProgressEvent 로만 갱신되는 ANSI 화면
+type dashboard struct {
+	out   io.Writer
+	start time.Time
+	rows  map[string]*timeframeRow
+}
+
+func newDashboard(out io.Writer) *dashboard {
+	d := &dashboard{out: out, start: time.Now(), rows: make(map[string]*timeframeRow)}
+	for _, tf := range timeframes {
+		d.rows[tf.Name] = &timeframeRow{event: ProgressEvent{Timeframe: tf.Name}}
+	}
+	return d
+}
+
+func (d *dashboard) update(ev ProgressEvent) {
+	row, ok := d.rows[ev.Timeframe]
+	if !ok {
+		return
+	}
+	row.event = ev
+	row.started = true
+}
+
+func (d *dashboard) render() {
+	elapsed := time.Since(d.start)
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "업비트 캔들 수집 대시보드  (경과 %s, Ctrl+C 로 중단)\n", elapsed.Round(time.Second))
+	b.WriteString(strings.Repeat("-", 92) + "\n")
+	fmt.Fprintf(&b, "%-10s %-8s %7s %10s %10s  %-19s  %-19s\n", "시간단위", "상태", "페이지", "저장", "rows/s", "최고(KST)", "최신(KST)")
+	b.WriteString(strings.Repeat("-", 92) + "\n")
+
+	total := 0
+	for _, tf := range timeframes {
+		row := d.rows[tf.Name]
+		ev := row.event
+		total += ev.Saved
+		fmt.Fprintf(&b, "%-10s %-8s %7d %10s %10.1f  %-19s  %-19s\n",
+			tf.Name, d.status(row), ev.Pages, formatNumber(ev.Saved), rate(ev.Saved, elapsed), ev.Oldest, ev.Newest)
+	}
+
+	b.WriteString(strings.Repeat("-", 92) + "\n")
+	fmt.Fprintf(&b, "%-10s %-8s %7s %10s %10.1f\n", "합계", "", "", formatNumber(total), rate(total, elapsed))
+	for _, tf := range timeframes {
+		if err := d.rows[tf.Name].event.Err; err != nil {
+			fmt.Fprintf(&b, "  [%s] %v\n", tf.Name, err)
+		}
+	}
+	io.WriteString(d.out, b.String())
+}
+
+func (d *dashboard) status(row *timeframeRow) string {
+	switch {
+	case row.event.Err != nil:
+		return "오류"
+	case row.event.Done:
+		return "완료"
+	case row.started:
+		return "수집 중"
+	default:
+		return "대기"
+	}
+}
+
+func rate(saved int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(saved) / elapsed.Seconds()
+}
+
+func runDashboard(args []string) error {
+	fs := flag.NewFlagSet("dashboard", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	concurrency := fs.Int("concurrency", 0, "동시에 수집할 시간단위 수 (0 = 전체 동시)")
+	rateLimit := fs.Int("rate", 9, "초당 최대 API 요청 수 (업비트 제한: 10)")
+	refresh := fs.Duration("refresh", 500*time.Millisecond, "화면 갱신 주기")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *rateLimit <= 0 {
+		return fmt.Errorf("--rate 는 1 이상이어야 합니다")
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.MaxConcurrency = *concurrency
+	collector.rateLimiter = NewRateLimiter(*rateLimit)
+
+	// 터미널이 아니면 (파이프, nohup 등) 일반 로그로 수집
+	if !isTerminal(os.Stdout) {
+		return failedResults(collector.CollectAll())
+	}
+
+	events := make(chan ProgressEvent, len(timeframes)*4)
+	collector.Progress = events
+
+	// 수집 로그가 화면을 덮지 않도록 수집 동안 출력을 버림
+	screen := collector.Output
+	collector.Output = io.Discard
+
+	var results []CollectResult
+	go func() {
+		results = collector.CollectAll()
+		close(events)
+	}()
+
+	board := newDashboard(screen)
+	ticker := time.NewTicker(*refresh)
+	defer ticker.Stop()
+
+	board.render()
+	for done := false; !done; {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			board.update(ev)
+		case <-ticker.C:
+			board.render()
+		}
+	}
+
+	collector.Output = screen
+	board.render()
+	collector.PrintStatistics()
+	return failedResults(results)
+}
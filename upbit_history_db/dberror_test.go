@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDBErrorWrapsSaveAndInterpolate(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute5")
+	if _, err := c.db.Exec("DROP TABLE bitcoin_minute5"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, saveErr := c.saveCandles(tf, []Candle{testCandle(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 100)})
+	_, interpErr := c.interpolateMissingData(tf)
+	for _, tc := range []struct {
+		op  string
+		err error
+	}{
+		{"save", saveErr},
+		{"interpolate", interpErr},
+	} {
+		var dbErr *DBError
+		if !errors.As(tc.err, &dbErr) {
+			t.Fatalf("%s: err = %v, want DBError", tc.op, tc.err)
+		}
+		if dbErr.Op != tc.op || dbErr.Timeframe != "minute5" {
+			t.Errorf("%s: Op = %q, Timeframe = %q", tc.op, dbErr.Op, dbErr.Timeframe)
+		}
+		if prefix := tc.op + " failed for minute5: "; !strings.HasPrefix(tc.err.Error(), prefix) {
+			t.Errorf("%s: 메시지 = %q, want %q 로 시작", tc.op, tc.err.Error(), prefix)
+		}
+		// 원래 sql 오류는 Unwrap 으로 그대로 접근
+		if errors.Unwrap(dbErr) == nil || !strings.Contains(errors.Unwrap(dbErr).Error(), "no such table") {
+			t.Errorf("%s: Unwrap = %v", tc.op, errors.Unwrap(dbErr))
+		}
+	}
+}
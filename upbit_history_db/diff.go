@@ -0,0 +1,220 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// diffSamples - DiffReport 에 남기는 종류별 예시 timestamp 수
+const diffSamples = 5
+
+// DiffReport - CSV 와 DB 캔들 비교 결과
+type DiffReport struct {
+	Matched             int      `json:"matched"`
+	MissingInDB         int      `json:"missing_in_db"`
+	MissingInCSV        int      `json:"missing_in_csv"`
+	Mismatched          int      `json:"mismatched"`
+	MissingInDBSamples  []string `json:"missing_in_db_samples,omitempty"`
+	MissingInCSVSamples []string `json:"missing_in_csv_samples,omitempty"`
+	MismatchedSamples   []string `json:"mismatched_samples,omitempty"`
+}
+
+// Identical - 차이가 하나도 없는지
+func (r DiffReport) Identical() bool {
+	return r.MissingInDB == 0 && r.MissingInCSV == 0 && r.Mismatched == 0
+}
+
+// Diff - ExportCSV 형식 CSV 와 저장된 캔들을 timestamp 순으로 맞춰가며 비교
+//
+// CSV 와 DB 를 모두 시간 오름차순으로 한 줄씩 읽으므로 메모리 사용량은 데이터 크기와 무관하다.
+// CSV 가 정렬되어 있지 않으면 오류를 반환한다.
+func (c *Collector) Diff(tf Timeframe, r io.Reader) (DiffReport, error) {
+	var report DiffReport
+
+	csvReader, err := newCSVCandleReader(r)
+	if err != nil {
+		return report, err
+	}
+	stored := c.newCandleCursor(tf)
+	defer stored.close()
+
+	fromCSV, csvErr := csvReader.Read()
+	fromDB, dbOK, err := stored.next()
+	if err != nil {
+		return report, err
+	}
+	prevCSV := ""
+
+	for csvErr == nil || dbOK {
+		if csvErr != nil && csvErr != io.EOF {
+			break
+		}
+		if csvErr == nil {
+			if fromCSV.CandleDateTimeKST < prevCSV {
+				return report, fmt.Errorf("CSV 가 시간순으로 정렬되어 있지 않습니다: %s", fromCSV.CandleDateTimeKST)
+			}
+			prevCSV = fromCSV.CandleDateTimeKST
+		}
+
+		switch {
+		case csvErr == nil && (!dbOK || fromCSV.CandleDateTimeKST < fromDB.CandleDateTimeKST):
+			report.MissingInDB++
+			report.MissingInDBSamples = appendSample(report.MissingInDBSamples, fromCSV.CandleDateTimeKST)
+			fromCSV, csvErr = csvReader.Read()
+		case csvErr != nil || fromDB.CandleDateTimeKST < fromCSV.CandleDateTimeKST:
+			report.MissingInCSV++
+			report.MissingInCSVSamples = appendSample(report.MissingInCSVSamples, fromDB.CandleDateTimeKST)
+			if fromDB, dbOK, err = stored.next(); err != nil {
+				return report, err
+			}
+		default:
+			if sameCandle(fromCSV, fromDB) {
+				report.Matched++
+			} else {
+				report.Mismatched++
+				report.MismatchedSamples = appendSample(report.MismatchedSamples, fromDB.CandleDateTimeKST)
+			}
+			fromCSV, csvErr = csvReader.Read()
+			if fromDB, dbOK, err = stored.next(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if csvErr != io.EOF {
+		return report, csvErr
+	}
+	return report, nil
+}
+
+func appendSample(samples []string, timestamp string) []string {
+	if len(samples) < diffSamples {
+		samples = append(samples, timestamp)
+	}
+	return samples
+}
+
+// sameCandle - 가격/거래량(부동소수 오차 허용)과 보간 여부가 같은지
+func sameCandle(a, b Candle) bool {
+	pairs := [][2]float64{
+		{a.OpeningPrice, b.OpeningPrice},
+		{a.HighPrice, b.HighPrice},
+		{a.LowPrice, b.LowPrice},
+		{a.TradePrice, b.TradePrice},
+		{a.CandleAccTradeVolume, b.CandleAccTradeVolume},
+		{a.CandleAccTradePrice, b.CandleAccTradePrice},
+	}
+	for _, p := range pairs {
+		if math.Abs(p[0]-p[1]) > 1e-9*math.Max(1, math.Max(math.Abs(p[0]), math.Abs(p[1]))) {
+			return false
+		}
+	}
+	return a.IsInterpolated == b.IsInterpolated
+}
+
+// candleCursor - 캔들 DB 전체(연도 분할 포함)를 timestamp 오름차순으로 한 줄씩 읽음
+type candleCursor struct {
+	c    *Collector
+	tf   Timeframe
+	dbs  []*sql.DB
+	rows *sql.Rows
+}
+
+func (c *Collector) newCandleCursor(tf Timeframe) *candleCursor {
+	return &candleCursor{c: c, tf: tf, dbs: c.candleDBs()}
+}
+
+func (cur *candleCursor) next() (Candle, bool, error) {
+	for {
+		if cur.rows == nil {
+			if len(cur.dbs) == 0 {
+				return Candle{}, false, nil
+			}
+			rows, err := cur.dbs[0].Query(candleSchema.selectSQL(cur.c.table(cur.tf)) + " ORDER BY timestamp ASC")
+			if err != nil {
+				return Candle{}, false, err
+			}
+			cur.rows = rows
+			cur.dbs = cur.dbs[1:]
+		}
+
+		if cur.rows.Next() {
+			candle := Candle{Market: cur.c.market}
+			err := cur.rows.Scan(candleFields(&candle)...)
+			return candle, err == nil, err
+		}
+		err := cur.rows.Err()
+		cur.rows.Close()
+		cur.rows = nil
+		if err != nil {
+			return Candle{}, false, err
+		}
+	}
+}
+
+func (cur *candleCursor) close() {
+	if cur.rows != nil {
+		cur.rows.Close()
+	}
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "비교할 시간단위 (필수, 예: minute1)")
+	csvPath := fs.String("csv", "", "비교할 CSV 파일 (export 형식, 필수)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" || *csvPath == "" {
+		return fmt.Errorf("--timeframe 과 --csv 가 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(*csvPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	report, err := collector.Diff(tf, file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("[%s] 일치 %s개, DB 에 없음 %s개, CSV 에 없음 %s개, 값 다름 %s개\n", tf.Name,
+		formatNumber(report.Matched), formatNumber(report.MissingInDB),
+		formatNumber(report.MissingInCSV), formatNumber(report.Mismatched))
+	for _, sample := range []struct {
+		label string
+		list  []string
+	}{
+		{"DB 에 없음", report.MissingInDBSamples},
+		{"CSV 에 없음", report.MissingInCSVSamples},
+		{"값 다름", report.MismatchedSamples},
+	} {
+		if len(sample.list) > 0 {
+			fmt.Printf("  %s 예: %v\n", sample.label, sample.list)
+		}
+	}
+
+	if !report.Identical() {
+		return fmt.Errorf("%s 데이터가 CSV 와 다릅니다", tf.Name)
+	}
+	fmt.Printf("[%s] %s CSV 와 DB 가 일치합니다\n", tf.Name, collector.mark(markOK))
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffReportsMismatches(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, kstMinutes(t0, 5)...)
+	var backup bytes.Buffer
+	if _, err := c.ExportCSV(&backup, tf, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 백업 이후 한 캔들 값이 바뀌고, 한 캔들이 지워지고, 새 캔들이 추가됨
+	ts := func(minute int) string { return t0.Add(time.Duration(minute) * time.Minute).Format(timestampLayout) }
+	if _, err := c.db.Exec("UPDATE bitcoin_minute1 SET trade_price = trade_price + 0.5 WHERE timestamp = ?", ts(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.db.Exec("DELETE FROM bitcoin_minute1 WHERE timestamp = ?", ts(3)); err != nil {
+		t.Fatal(err)
+	}
+	seed(t, c, tf, t0.Add(5*time.Minute))
+	c.invalidateCache(tf)
+
+	report, err := c.Diff(tf, &backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Matched != 3 || report.MissingInDB != 1 || report.MissingInCSV != 1 || report.Mismatched != 1 {
+		t.Errorf("report = %+v, want matched 3, missing_in_db 1, missing_in_csv 1, mismatched 1", report)
+	}
+	if got := strings.Join(report.MissingInDBSamples, ","); got != ts(3) {
+		t.Errorf("missing_in_db_samples = %s, want %s", got, ts(3))
+	}
+	if got := strings.Join(report.MissingInCSVSamples, ","); got != ts(5) {
+		t.Errorf("missing_in_csv_samples = %s, want %s", got, ts(5))
+	}
+	if got := strings.Join(report.MismatchedSamples, ","); got != ts(1) {
+		t.Errorf("mismatched_samples = %s, want %s", got, ts(1))
+	}
+	if report.Identical() {
+		t.Error("차이가 있는데 Identical")
+	}
+}
+
+func TestDiffMalformedCSVWithEmptyDB(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	csv := strings.Join(csvHeader, ",") + "\n" +
+		"2024-01-01T09:00:00,2024-01-01T00:00:00,100,101,99,100,1,100,0\n" +
+		"2024-01-01T09:01:00,\"unterminated\n"
+
+	report, err := c.Diff(mustTimeframe(t, "minute1"), strings.NewReader(csv))
+	if err == nil {
+		t.Fatalf("잘못된 CSV 인데 오류 없음: %+v", report)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 중복 종류 (DupGroup.Kind)
+const (
+	dupUTCShift = "utc_shift" // 같은 캔들이 KST 문자열과 UTC 문자열(9시간 이른 시각)로 두 번 저장됨
+	dupFormat   = "format"    // 같은 시각이 다른 형식의 문자열(공백 구분, 시간대 표기 등)로 저장됨
+)
+
+// DupGroup - 문자열은 다르지만 같은 UTC 시각의 캔들로 보이는 행들
+type DupGroup struct {
+	Kind       string   `json:"kind"`
+	Instant    string   `json:"instant"`    // KST 로 해석한 기준 시각 (timestampLayout)
+	Timestamps []string `json:"timestamps"` // 저장된 timestamp 문자열 (오름차순)
+}
+
+// legacyTimestampLayouts - timestampLayout 이 아닌 timestamp 를 해석할 때 시도하는 형식 (시간대 없으면 KST)
+var legacyTimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// parseLegacyTimestamp - 잘못된 형식으로 저장된 timestamp 를 KST 시각으로 해석
+func parseLegacyTimestamp(ts string) (time.Time, bool) {
+	for _, layout := range legacyTimestampLayouts {
+		at, err := time.Parse(layout, ts)
+		if err != nil {
+			continue
+		}
+		if layout == time.RFC3339 {
+			at = at.UTC().Add(9 * time.Hour)
+		}
+		return time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, time.UTC), true
+	}
+	return time.Time{}, false
+}
+
+// candleFingerprint - UTC/KST 중복 판별에 쓰는 캔들 내용
+type candleFingerprint struct {
+	open, high, low, close, volume float64
+}
+
+// FindTimestampDuplicates - timestamp 문자열은 다르지만 같은 UTC 시각의 캔들로 보이는 행 조회 (읽기 전용)
+//
+// 두 가지를 찾는다. 정확히 9시간 차이 나는 실제 캔들 두 개의 시가/고가/저가/종가/거래량이 모두 같으면
+// UTC 시각이 KST 문자열로 잘못 저장된 것으로 본다 (보간 캔들은 값이 반복되므로 제외). timestampLayout 이
+// 아닌 형식으로 저장된 행은 KST 시각으로 해석해 같은 시각의 다른 행과 묶는다. 어떤 형식으로도
+// 해석할 수 없는 timestamp 는 건너뛴다.
+func (c *Collector) FindTimestampDuplicates(tf Timeframe) ([]DupGroup, error) {
+	query := fmt.Sprintf(`
+		SELECT timestamp, opening_price, high_price, low_price, trade_price, candle_acc_trade_volume, is_interpolated
+		FROM %s ORDER BY timestamp ASC
+	`, c.table(tf))
+
+	var groups []DupGroup
+	window := make(map[time.Time]candleFingerprint) // 최근 9시간의 실제 캔들
+	var windowOrder []time.Time
+	legacy := make(map[time.Time][]string) // 형식이 다른 행의 KST 시각 → 저장된 문자열
+	var legacyOrder []time.Time
+
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var ts string
+			var fp candleFingerprint
+			var interpolated bool
+			if err := rows.Scan(&ts, &fp.open, &fp.high, &fp.low, &fp.close, &fp.volume, &interpolated); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			at, err := time.Parse(timestampLayout, ts)
+			if err != nil {
+				if at, ok := parseLegacyTimestamp(ts); ok {
+					if _, seen := legacy[at]; !seen {
+						legacyOrder = append(legacyOrder, at)
+					}
+					legacy[at] = append(legacy[at], ts)
+				}
+				continue
+			}
+			if interpolated {
+				continue
+			}
+
+			utc := at.Add(-9 * time.Hour)
+			for len(windowOrder) > 0 && windowOrder[0].Before(utc) {
+				delete(window, windowOrder[0])
+				windowOrder = windowOrder[1:]
+			}
+			if earlier, ok := window[utc]; ok && earlier == fp {
+				groups = append(groups, DupGroup{
+					Kind:       dupUTCShift,
+					Instant:    ts,
+					Timestamps: []string{utc.Format(timestampLayout), ts},
+				})
+			}
+			window[at] = fp
+			windowOrder = append(windowOrder, at)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, at := range legacyOrder {
+		stamps := legacy[at]
+		instant := at.Format(timestampLayout)
+		exists, err := c.hasTimestamp(tf, instant)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			stamps = append(stamps, instant)
+		}
+		if len(stamps) < 2 {
+			continue
+		}
+		sort.Strings(stamps)
+		groups = append(groups, DupGroup{Kind: dupFormat, Instant: instant, Timestamps: stamps})
+	}
+	return groups, nil
+}
+
+// hasTimestamp - tf 테이블에 ts 행이 있는지 (연도별 분할 시 모든 DB 파일 확인)
+func (c *Collector) hasTimestamp(tf Timeframe, ts string) (bool, error) {
+	for _, db := range c.candleDBs() {
+		var count int
+		if err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE timestamp = ?", c.table(tf)), ts).Scan(&count); err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
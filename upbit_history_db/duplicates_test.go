@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFindTimestampDuplicates(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute60")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	hours := make([]time.Time, 12)
+	for i := range hours {
+		hours[i] = t0.Add(time.Duration(i+1) * time.Hour)
+	}
+	seed(t, c, tf, hours...) // KST 10:00 ~ 21:00, 가격이 모두 달라 9시간 차이 캔들끼리 우연히 같지 않음
+
+	copyRow := func(from, as string) {
+		t.Helper()
+		if _, err := c.db.Exec(`INSERT INTO bitcoin_minute60 (timestamp, opening_price, high_price, low_price, trade_price,
+			candle_acc_trade_volume, candle_acc_trade_price, is_interpolated)
+			SELECT ?, opening_price, high_price, low_price, trade_price, candle_acc_trade_volume, candle_acc_trade_price, is_interpolated
+			FROM bitcoin_minute60 WHERE timestamp = ?`, as, from); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// KST 19:00 캔들이 UTC 시각 문자열(10:00 자리)로, KST 15:00 캔들이 다른 형식으로 한 번 더 저장됨
+	kst19 := t0.Add(10 * time.Hour).Format(timestampLayout)
+	kst15 := t0.Add(6 * time.Hour).Format(timestampLayout)
+	if _, err := c.db.Exec("DELETE FROM bitcoin_minute60 WHERE timestamp = ?", t0.Add(time.Hour).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	copyRow(kst19, t0.Add(time.Hour).Format(timestampLayout))
+	copyRow(kst15, "2024-01-01 15:00:00")
+	copyRow(kst15, "garbage") // 해석할 수 없는 형식은 건너뜀
+
+	groups, err := c.FindTimestampDuplicates(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DupGroup{
+		{Kind: dupUTCShift, Instant: kst19, Timestamps: []string{t0.Add(time.Hour).Format(timestampLayout), kst19}},
+		{Kind: dupFormat, Instant: kst15, Timestamps: []string{"2024-01-01 15:00:00", kst15}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %+v, want %+v", groups, want)
+	}
+	if n := countRows(t, c, tf, ""); n != 14 {
+		t.Errorf("행 %d개, want 14 (읽기 전용)", n)
+	}
+}
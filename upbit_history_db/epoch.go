@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// candleEpochSchema - timestamp_ms(UTC Unix ms) 컬럼을 추가한 캔들 테이블
+//
+// KST 문자열 timestamp 는 기본 키이자 표시용으로 그대로 두고, 범위 조회는 정수 컬럼 인덱스를 쓴다.
+var candleEpochSchema = tableSchema{columns: append(append([]column(nil), candleSchema.columns...),
+	column{"timestamp_ms", "INTEGER"})}
+
+// candleEpochFields - candleEpochSchema 컬럼 순서의 Candle 필드 포인터
+func candleEpochFields(c *Candle) []any {
+	return append(candleFields(c), (*kstMillis)(&c.CandleDateTimeKST))
+}
+
+// kstMillis - KST timestamp 문자열을 timestamp_ms 컬럼 값(UTC Unix ms)으로 저장/조회
+type kstMillis string
+
+func (k kstMillis) Value() (driver.Value, error) {
+	return timestampMillis(string(k))
+}
+
+func (k *kstMillis) Scan(src any) error {
+	ms, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("timestamp_ms 값 해석 실패: %v", src)
+	}
+	*k = kstMillis(millisTimestamp(ms))
+	return nil
+}
+
+// timestampMillis - KST timestamp 문자열의 UTC Unix ms
+func timestampMillis(timestamp string) (int64, error) {
+	t, err := time.Parse(timestampLayout, timestamp)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 timestamp %q: %w", timestamp, err)
+	}
+	return t.Add(-9 * time.Hour).UnixMilli(), nil
+}
+
+// millisTimestamp - UTC Unix ms 의 KST timestamp 문자열 (timestampMillis 의 역)
+func millisTimestamp(ms int64) string {
+	return time.UnixMilli(ms).UTC().Add(9 * time.Hour).Format(timestampLayout)
+}
+
+// queryMillis - GetCandles 범위 인자(KST 벽시계)를 timestamp 문자열 비교와 같은 기준의 ms 로 변환
+func queryMillis(t time.Time) int64 {
+	ms, _ := timestampMillis(t.Format(timestampLayout))
+	return ms
+}
+
+// candleTableSchema - 저장에 쓰는 캔들 스키마 (EnableEpochTimestamps 이후에는 timestamp_ms 포함)
+func (c *Collector) candleTableSchema() tableSchema {
+	if c.epoch {
+		return candleEpochSchema
+	}
+	return candleSchema
+}
+
+// candleRow - candleTableSchema 컬럼 순서의 INSERT 인자
+func (c *Collector) candleRow(candle *Candle) []any {
+	if c.epoch {
+		return candleEpochFields(candle)
+	}
+	return candleFields(candle)
+}
+
+// EnableEpochTimestamps - 모든 캔들 테이블에 timestamp_ms 컬럼을 추가/채우고 이후 저장과 범위 조회에 사용
+//
+// 이미 변환된 DB 에서도 안전하게 다시 실행할 수 있다 (비어 있는 timestamp_ms 만 채움).
+func (c *Collector) EnableEpochTimestamps() error {
+	for _, db := range c.candleDBs() {
+		for _, tf := range timeframes {
+			if err := c.migrateEpoch(db, tf); err != nil {
+				return fmt.Errorf("%s timestamp_ms 변환 실패: %w", c.table(tf), err)
+			}
+		}
+	}
+	c.epoch = true
+	for _, tf := range timeframes {
+		c.invalidateCache(tf)
+	}
+	return nil
+}
+
+// migrateEpoch - 한 테이블에 timestamp_ms 컬럼/인덱스를 만들고 기존 KST 문자열을 변환
+func (c *Collector) migrateEpoch(db *sql.DB, tf Timeframe) error {
+	table := c.table(tf)
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN timestamp_ms INTEGER", table))
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return err
+	}
+	// KST 벽시계 → UTC epoch (9시간 = 32400초)
+	if _, err := db.Exec(fmt.Sprintf(`
+		UPDATE %s SET timestamp_ms = (CAST(strftime('%%s', timestamp) AS INTEGER) - 32400) * 1000
+		WHERE timestamp_ms IS NULL
+	`, table)); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s_timestamp_ms ON %s (timestamp_ms)", table, table))
+	return err
+}
+
+func runMigrateEpoch(args []string) error {
+	fs := flag.NewFlagSet("migrate-epoch", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	if err := collector.EnableEpochTimestamps(); err != nil {
+		return err
+	}
+	fmt.Printf("%s 모든 캔들 테이블에 timestamp_ms 컬럼 변환 완료\n", collector.mark(markOK))
+	return nil
+}
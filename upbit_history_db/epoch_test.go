@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEpochRangeQueriesMatchText(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, kstMinutes(t0, 300)...)
+
+	ranges := []struct{ from, to time.Time }{
+		{},
+		{t0.Add(50 * time.Minute), t0.Add(120 * time.Minute)},
+		{t0.Add(50*time.Minute + 30*time.Second), t0.Add(120*time.Minute - time.Second)},
+		{t0.Add(200 * time.Minute), time.Time{}},
+		{time.Time{}, t0.Add(-time.Minute)},
+	}
+	text := make([][]Candle, len(ranges))
+	for i, r := range ranges {
+		var err error
+		if text[i], err = c.queryCandles(tf, r.from, r.to, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 두 번 실행해도 안전
+	for i := 0; i < 2; i++ {
+		if err := c.EnableEpochTimestamps(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, r := range ranges {
+		epoch, err := c.queryCandles(tf, r.from, r.to, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(epoch, text[i]) {
+			t.Errorf("[%d] %v ~ %v: 정수 조회 %d개, 문자열 조회 %d개", i, r.from, r.to, len(epoch), len(text[i]))
+		}
+	}
+	if len(text[2]) != 69 {
+		t.Errorf("초 단위 경계 범위 %d개, want 69", len(text[2]))
+	}
+
+	// 변환 값과 이후 저장/보간 행의 timestamp_ms
+	var ms int64
+	if err := c.db.QueryRow("SELECT timestamp_ms FROM bitcoin_minute1 WHERE timestamp = ?", t0.Format(timestampLayout)).Scan(&ms); err != nil {
+		t.Fatal(err)
+	}
+	if want := t0.Add(-9 * time.Hour).UnixMilli(); ms != want {
+		t.Errorf("timestamp_ms = %d, want %d", ms, want)
+	}
+	seed(t, c, tf, t0.Add(303*time.Minute))
+	if _, err := c.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, c, tf, "timestamp_ms IS NULL"); n != 0 {
+		t.Errorf("timestamp_ms 없는 행 %d개", n)
+	}
+	if n := countRows(t, c, tf, "timestamp_ms IS NOT NULL"); n != 304 {
+		t.Errorf("행 %d개, want 304 (기존 300 + 보간 3 + 새 캔들 1)", n)
+	}
+}
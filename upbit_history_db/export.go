@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader - ExportCSV 컬럼 (timestamp 는 KST, Location 설정 시 그 시간대 RFC3339)
+var csvHeader = []string{
+	"timestamp", "timestamp_utc", "open", "high", "low", "close",
+	"volume", "value", "is_interpolated",
+}
+
+// ExportCSV - 저장된 캔들을 CSV 로 출력 (from/to 는 GetCandles 와 동일, ExcludeInterpolated 적용)
+func (c *Collector) ExportCSV(w io.Writer, tf Timeframe, from, to time.Time) (int, error) {
+	candles, err := c.queryCandles(tf, from, to, 0)
+	if err != nil {
+		return 0, err
+	}
+	candles = c.localize(candles)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return 0, err
+	}
+	for _, candle := range candles {
+		interpolated := "0"
+		if candle.IsInterpolated {
+			interpolated = "1"
+		}
+		record := []string{
+			candle.CandleDateTimeKST,
+			candle.CandleDateTimeUTC,
+			formatFloat(candle.OpeningPrice),
+			formatFloat(candle.HighPrice),
+			formatFloat(candle.LowPrice),
+			formatFloat(candle.TradePrice),
+			formatFloat(candle.CandleAccTradeVolume),
+			formatFloat(candle.CandleAccTradePrice),
+			interpolated,
+		}
+		if err := writer.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return len(candles), writer.Error()
+}
+
+// ExportJSONL - 저장된 캔들을 한 줄에 하나씩 JSON 으로 출력 (필드는 Candle JSON 태그)
+func (c *Collector) ExportJSONL(w io.Writer, tf Timeframe, from, to time.Time) (int, error) {
+	candles, err := c.queryCandles(tf, from, to, 0)
+	if err != nil {
+		return 0, err
+	}
+	candles = c.localize(candles)
+
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	for _, candle := range candles {
+		if err := encoder.Encode(candle); err != nil {
+			return 0, err
+		}
+	}
+	return len(candles), buffered.Flush()
+}
+
+// csvCandleReader - ExportCSV 형식 CSV 를 한 줄씩 Candle 로 읽음 (헤더 이름으로 컬럼을 찾음)
+type csvCandleReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+func newCSVCandleReader(r io.Reader) (*csvCandleReader, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("CSV 헤더 읽기 실패: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok && name != "timestamp_utc" && name != "is_interpolated" {
+			return nil, fmt.Errorf("CSV 에 %s 컬럼이 없습니다", name)
+		}
+	}
+	return &csvCandleReader{reader: reader, columns: columns, line: 1}, nil
+}
+
+// Read - 다음 캔들 (끝이면 io.EOF)
+func (r *csvCandleReader) Read() (Candle, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return Candle{}, err
+	}
+	r.line++
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(name string) (float64, error) {
+		v, err := strconv.ParseFloat(field(name), 64)
+		if err != nil {
+			return 0, fmt.Errorf("CSV %d행 %s 값 오류: %w", r.line, name, err)
+		}
+		return v, nil
+	}
+
+	candle := Candle{
+		CandleDateTimeKST: field("timestamp"),
+		CandleDateTimeUTC: field("timestamp_utc"),
+		IsInterpolated:    field("is_interpolated") != "" && field("is_interpolated") != "0",
+	}
+	targets := []struct {
+		name string
+		dst  *float64
+	}{
+		{"open", &candle.OpeningPrice},
+		{"high", &candle.HighPrice},
+		{"low", &candle.LowPrice},
+		{"close", &candle.TradePrice},
+		{"volume", &candle.CandleAccTradeVolume},
+		{"value", &candle.CandleAccTradePrice},
+	}
+	for _, t := range targets {
+		if *t.dst, err = number(t.name); err != nil {
+			return Candle{}, err
+		}
+	}
+	return candle, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "내보낼 시간단위 (필수, 예: minute1)")
+	format := fs.String("format", "csv", "출력 형식 (csv, jsonl, binary)")
+	out := fs.String("out", "-", "출력 파일 경로 (- 이면 표준출력)")
+	from := fs.String("from", "", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "종료 시각 (KST, 포함)")
+	includeInterpolated := fs.Bool("include-interpolated", true, "보간 캔들 포함 (false 면 실제 캔들만, 시간 간격이 빌 수 있음)")
+	tz := fs.String("tz", "", "timestamp 를 이 시간대 시각으로 출력 (IANA 이름, 예: UTC, America/New_York, 비우면 KST 그대로)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	var location *time.Location
+	if *tz != "" {
+		if location, err = time.LoadLocation(*tz); err != nil {
+			return fmt.Errorf("잘못된 --tz: %w", err)
+		}
+	}
+
+	switch *format {
+	case "csv", "jsonl":
+	case "binary":
+		if *from != "" || *to != "" || *tz != "" {
+			return fmt.Errorf("binary 형식은 테이블 전체를 KST 그대로 내보내므로 --from, --to, --tz 를 쓸 수 없습니다")
+		}
+	case "parquet":
+		return fmt.Errorf("parquet 형식은 아직 지원하지 않습니다 (csv 로 내보낸 뒤 변환, HOW_TO_RUN.md 참고)")
+	default:
+		return fmt.Errorf("알 수 없는 형식: %s (csv, jsonl, binary)", *format)
+	}
+
+	// 표준출력으로 내보낼 때는 안내 메시지가 섞이지 않도록 생략
+	common.quiet = *out == "-"
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.ExcludeInterpolated = !*includeInterpolated
+	collector.Location = location
+
+	export := collector.ExportCSV
+	switch *format {
+	case "jsonl":
+		export = collector.ExportJSONL
+	case "binary":
+		export = func(w io.Writer, tf Timeframe, _, _ time.Time) (int, error) {
+			return 0, collector.ExportBinary(tf, w)
+		}
+	}
+
+	if *out == "-" {
+		_, err := export(os.Stdout, tf, fromTime, toTime)
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	n, err := export(file, tf, fromTime, toTime)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s 내보내기 실패: %w", *out, err)
+	}
+	if *format == "binary" {
+		fmt.Printf("[%s] %s 테이블 전체를 %s 로 내보냄\n", tf.Name, collector.mark(markOK), *out)
+		return nil
+	}
+	fmt.Printf("[%s] %s %d개 캔들을 %s 로 내보냄\n", tf.Name, collector.mark(markOK), n, *out)
+	return nil
+}
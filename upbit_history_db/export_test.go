@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportIncludeInterpolated(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.AddDate(0, 0, 3))
+	if _, err := c.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		exclude bool
+		want    int
+	}{
+		{exclude: false, want: 4},
+		{exclude: true, want: 2},
+	} {
+		c.ExcludeInterpolated = tc.exclude
+
+		var csv bytes.Buffer
+		n, err := c.ExportCSV(&csv, tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(csv.String(), "\n") - 1; n != tc.want || lines != tc.want {
+			t.Errorf("보간 제외 %v: CSV %d개 (%d줄), want %d", tc.exclude, n, lines, tc.want)
+		}
+
+		var jsonl bytes.Buffer
+		n, err = c.ExportJSONL(&jsonl, tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(jsonl.String(), "\n"); n != tc.want || lines != tc.want {
+			t.Errorf("보간 제외 %v: JSONL %d개 (%d줄), want %d", tc.exclude, n, lines, tc.want)
+		}
+
+		candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candles) != tc.want {
+			t.Errorf("보간 제외 %v: GetCandles %d개, want %d", tc.exclude, len(candles), tc.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeUpbit - 업비트 캔들 API 흉내 (요청 경로의 시간단위 간격으로 head 부터 과거 방향 캔들을 돌려줌)
+//
+// to 가 없으면 head 가 속한 캔들부터, 있으면 to 이전 캔들부터 count(기본 200)개를 최신순으로 준다. floor
+// 보다 이전 캔들은 없다 (상장 시점 흉내). 가격은 시각으로 정해지므로 같은 캔들은 항상 같은 값이다.
+type fakeUpbit struct {
+	head  time.Time // 가장 최신 캔들 시작 시각 (UTC)
+	floor time.Time // 가장 오래된 캔들 시작 시각 (UTC, 0 이면 제한 없음)
+	calls atomic.Int64
+}
+
+func (f *fakeUpbit) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.calls.Add(1)
+		q := r.URL.Query()
+		count, _ := strconv.Atoi(q.Get("count"))
+		if count == 0 {
+			count = 200
+		}
+		series := fakeSeriesFor(r.URL.Path)
+
+		latest := series.start(f.head)
+		ts := latest
+		if s := q.Get("to"); s != "" {
+			to, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				t.Errorf("잘못된 to 파라미터 %q: %v", s, err)
+			}
+			if ts = series.start(to); !ts.Before(to) {
+				ts = series.prev(ts)
+			}
+			if ts.After(latest) {
+				ts = latest
+			}
+		}
+
+		out := []map[string]any{}
+		for i := 0; i < count; i++ {
+			if !f.floor.IsZero() && ts.Before(f.floor) {
+				break
+			}
+			out = append(out, fakeCandleJSON(q.Get("market"), ts))
+			ts = series.prev(ts)
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+}
+
+// fakeSeries - 시간단위 캔들 시작 시각 계산 (UTC, 일봉 이상은 UTC 00:00 = KST 09:00 경계)
+type fakeSeries struct {
+	start func(time.Time) time.Time // t 가 속한 캔들 시작
+	prev  func(time.Time) time.Time // 직전 캔들 시작
+}
+
+// fakeSeriesFor - 요청 경로(.../minutes/5, .../days, .../weeks, .../months)의 캔들 간격
+func fakeSeriesFor(path string) fakeSeries {
+	fixed := func(d time.Duration) fakeSeries {
+		return fakeSeries{
+			start: func(t time.Time) time.Time { return t.Truncate(d) },
+			prev:  func(t time.Time) time.Time { return t.Add(-d) },
+		}
+	}
+	switch {
+	case strings.HasSuffix(path, "/days"):
+		return fixed(24 * time.Hour)
+	case strings.HasSuffix(path, "/weeks"):
+		return fakeSeries{
+			start: func(t time.Time) time.Time {
+				day := t.Truncate(24 * time.Hour)
+				return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+			},
+			prev: func(t time.Time) time.Time { return t.AddDate(0, 0, -7) },
+		}
+	case strings.HasSuffix(path, "/months"):
+		return fakeSeries{
+			start: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) },
+			prev:  func(t time.Time) time.Time { return t.AddDate(0, -1, 0) },
+		}
+	}
+	minutes, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	if err != nil || minutes <= 0 {
+		minutes = 1
+	}
+	return fixed(time.Duration(minutes) * time.Minute)
+}
+
+// fakeCandleJSON - ts(UTC) 시작 캔들의 업비트 응답 객체
+func fakeCandleJSON(market string, ts time.Time) map[string]any {
+	p := fakePrice(ts)
+	return map[string]any{
+		"market":                  market,
+		"candle_date_time_utc":    ts.UTC().Format(timestampLayout),
+		"candle_date_time_kst":    ts.UTC().Add(9 * time.Hour).Format(timestampLayout),
+		"opening_price":           p,
+		"high_price":              p + 5,
+		"low_price":               p - 5,
+		"trade_price":             p + 1,
+		"candle_acc_trade_price":  p * 2,
+		"candle_acc_trade_volume": 2.0,
+	}
+}
+
+// fakePrice - fakeUpbit 캔들의 시가
+func fakePrice(ts time.Time) float64 {
+	return float64(1000 + ts.Unix()/60%97)
+}
+
+// newTestCollector - 임시 DB 와 fakeUpbit 서버를 쓰는 Collector (head 는 마감된 최신 1분봉)
+func newTestCollector(t *testing.T) (*Collector, *fakeUpbit) {
+	t.Helper()
+	f := &fakeUpbit{head: time.Now().UTC().Truncate(time.Minute).Add(-time.Minute)}
+	srv := httptest.NewServer(f.handler(t))
+	t.Cleanup(srv.Close)
+
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	return c, f
+}
+
+// openTestDB - 임시 디렉터리의 새 DB (출력은 버림, 보간 최소 캔들 수 제한 없음)
+func openTestDB(t testing.TB, market string) *Collector {
+	t.Helper()
+	c, err := NewCollector(filepath.Join(t.TempDir(), "candles.db"), market)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	c.Output = io.Discard
+	c.rateLimiter = NewRateLimiter(1000)
+	c.MinInterpolationCandles = 0
+	return c
+}
+
+// testCandle - KST 시각 kst 의 실제 캔들 (시가=종가=price, 고가/저가 ±1)
+func testCandle(kst time.Time, price float64) Candle {
+	return Candle{
+		Market:               "KRW-BTC",
+		CandleDateTimeKST:    kst.Format(timestampLayout),
+		OpeningPrice:         price,
+		HighPrice:            price + 1,
+		LowPrice:             price - 1,
+		TradePrice:           price,
+		CandleAccTradeVolume: 1,
+		CandleAccTradePrice:  price,
+	}
+}
+
+// seed - times(KST) 마다 실제 캔들 저장 (i 번째 캔들 가격은 100+i)
+func seed(t *testing.T, c *Collector, tf Timeframe, times ...time.Time) {
+	t.Helper()
+	candles := make([]Candle, len(times))
+	for i, ts := range times {
+		candles[i] = testCandle(ts, 100+float64(i))
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// kstMinutes - start(KST) 부터 1분 간격 n 개 시각
+func kstMinutes(start time.Time, n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Minute)
+	}
+	return times
+}
+
+// mustTimeframe - 이름으로 시간단위 조회 (없으면 테스트 실패)
+func mustTimeframe(t *testing.T, name string) Timeframe {
+	t.Helper()
+	tf, err := findTimeframe(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tf
+}
+
+// countRows - tf 테이블의 where 조건 행 수 (연도 분할 포함)
+func countRows(t *testing.T, c *Collector, tf Timeframe, where string, args ...any) int {
+	t.Helper()
+	total := 0
+	for _, db := range c.candleDBs() {
+		var n int
+		query := "SELECT COUNT(*) FROM " + c.table(tf)
+		if where != "" {
+			query += " WHERE " + where
+		}
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFetchCandlesEncodesParams(t *testing.T) {
+	queries := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.RawQuery
+		io.WriteString(w, `[]`)
+	}))
+	defer srv.Close()
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+
+	day := mustTimeframe(t, "day")
+	params := map[string]string{"convertingPriceUnit": "K RW&x=1"}
+	if _, err := c.fetchCandles(context.Background(), day, "2024-01-01T00:00:00", params); err != nil {
+		t.Fatal(err)
+	}
+	raw := <-queries
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := query.Get("convertingPriceUnit"); v != params["convertingPriceUnit"] {
+		t.Errorf("convertingPriceUnit = %q, want %q", v, params["convertingPriceUnit"])
+	}
+	if query.Has("x") || query.Get("market") != "KRW-BTC" || query.Get("to") != "2024-01-01T00:00:00" {
+		t.Errorf("잘못 인코딩된 쿼리: %s", raw)
+	}
+	if !strings.Contains(raw, "convertingPriceUnit=K+RW%26x%3D1") {
+		t.Errorf("RawQuery = %s", raw)
+	}
+
+	// 기본값(nil)은 추가 파라미터 없음
+	if _, err := c.fetchCandles(context.Background(), day, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if raw := <-queries; strings.Contains(raw, "convertingPriceUnit") {
+		t.Errorf("params 없이 요청했는데 RawQuery = %s", raw)
+	}
+}
+
+func TestFetchCandlesRejectsUnsupportedParam(t *testing.T) {
+	c, f := newTestCollector(t)
+	_, err := c.fetchCandles(context.Background(), mustTimeframe(t, "minute1"), "", map[string]string{"convertingPriceUnit": "KRW"})
+	if err == nil || !strings.Contains(err.Error(), "convertingPriceUnit") {
+		t.Errorf("err = %v, want 지원하지 않는 파라미터 오류", err)
+	}
+	if n := f.calls.Load(); n != 0 {
+		t.Errorf("요청 %d번, 검증 실패 시 요청하지 않아야 함", n)
+	}
+}
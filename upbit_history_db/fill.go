@@ -0,0 +1,147 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// FillToNow - 마지막 실제 캔들 이후 현재 진행 중인 구간까지 직전 종가로 채운 합성 캔들 추가
+//
+// 합성 캔들은 시가=고가=저가=종가=마지막 종가, 거래량 0, is_interpolated = 1 로 저장된다.
+// 호출할 때마다 기존 채움 캔들을 지우고 다시 만들며, 수집이 실제 캔들을 받기 전에도 지운다.
+// 채울 개수가 MaxInterpolationGap 을 넘으면 가짜 데이터가 길게 이어지지 않도록 실패한다.
+func (c *Collector) FillToNow(tf Timeframe) (int, error) {
+	if _, err := c.ClearFillToNow(tf); err != nil {
+		return 0, err
+	}
+	last, ok, err := c.lastRealCandle(tf)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	start, err := time.Parse(timestampLayout, last.CandleDateTimeKST)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 timestamp %q: %w", last.CandleDateTimeKST, err)
+	}
+	nowKST := c.now().UTC().Add(9 * time.Hour)
+
+	var fills []Candle
+	for t := candleEnd(tf, start); !t.After(nowKST); t = candleEnd(tf, t) {
+		fills = append(fills, Candle{
+			CandleDateTimeKST: t.Format(timestampLayout),
+			OpeningPrice:      last.TradePrice,
+			HighPrice:         last.TradePrice,
+			LowPrice:          last.TradePrice,
+			TradePrice:        last.TradePrice,
+			IsInterpolated:    true,
+		})
+		if c.MaxInterpolationGap > 0 && len(fills) > c.MaxInterpolationGap {
+			return 0, fmt.Errorf("%s 마지막 캔들(%s) 이후 채울 캔들이 보간 한도(%d개)를 넘습니다",
+				tf.Name, last.CandleDateTimeKST, c.MaxInterpolationGap)
+		}
+	}
+	if len(fills) == 0 {
+		return 0, nil
+	}
+
+	defer c.invalidateCache(tf)
+	for i := range fills {
+		db, err := c.candleDBFor(fills[i].CandleDateTimeKST)
+		if err != nil {
+			return i, err
+		}
+		if _, err := db.Exec(c.candleTableSchema().insertSQL("INSERT OR REPLACE", c.table(tf)), c.candleRow(&fills[i])...); err != nil {
+			return i, err
+		}
+	}
+	return len(fills), nil
+}
+
+// ClearFillToNow - 마지막 실제 캔들 이후의 보간 캔들(FillToNow 결과) 삭제
+//
+// 구간 사이 보간은 항상 실제 캔들 사이에만 생기므로 지워지지 않는다.
+func (c *Collector) ClearFillToNow(tf Timeframe) (int, error) {
+	last, ok, err := c.lastRealCandle(tf)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, db := range c.candleDBs() {
+		res, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE is_interpolated = 1 AND timestamp > ?", c.table(tf)),
+			last.CandleDateTimeKST)
+		if err != nil {
+			return deleted, err
+		}
+		n, _ := res.RowsAffected()
+		deleted += int(n)
+	}
+	if deleted > 0 {
+		c.invalidateCache(tf)
+	}
+	return deleted, nil
+}
+
+// lastRealCandle - 가장 최근 실제(보간 아닌) 캔들
+func (c *Collector) lastRealCandle(tf Timeframe) (Candle, bool, error) {
+	dbs := c.candleDBs()
+	for i := len(dbs) - 1; i >= 0; i-- {
+		candle := Candle{Market: c.market}
+		err := dbs[i].QueryRow(candleSchema.selectSQL(c.table(tf)) +
+			" WHERE is_interpolated = 0 ORDER BY timestamp DESC LIMIT 1").Scan(candleFields(&candle)...)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return Candle{}, false, err
+		}
+		return candle, true, nil
+	}
+	return Candle{}, false, nil
+}
+
+func runFillToNow(args []string) error {
+	fs := flag.NewFlagSet("fill-to-now", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "채울 시간단위 (필수, 예: minute5)")
+	clearOnly := fs.Bool("clear", false, "채움 캔들만 삭제")
+	maxGap := fs.Int("max-gap", 12, "채울 수 있는 최대 캔들 수 (0 = 제한 없음)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.MaxInterpolationGap = *maxGap
+
+	if *clearOnly {
+		n, err := collector.ClearFillToNow(tf)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[%s] %s 채움 캔들 %d개 삭제\n", tf.Name, collector.mark(markOK), n)
+		return nil
+	}
+
+	n, err := collector.FillToNow(tf)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[%s] %s 현재 구간까지 %d개 채움\n", tf.Name, collector.mark(markOK), n)
+	return nil
+}
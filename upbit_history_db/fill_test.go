@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFillToNow(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute5")
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 1, 17, 0, 0, time.UTC) } // KST 10:17
+	t0 := time.Date(2024, 1, 1, 9, 50, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.Add(5*time.Minute)) // 09:50, 09:55 (종가 100, 101)
+
+	// 10:00, 10:05, 10:10 과 진행 중인 10:15 구간
+	for run := 1; run <= 2; run++ {
+		n, err := c.FillToNow(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 4 {
+			t.Errorf("%d 번째 채움 %d개, want 4 (다시 실행해도 같음)", run, n)
+		}
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 6 {
+		t.Fatalf("캔들 %d개, want 6", len(candles))
+	}
+	for i, candle := range candles[2:] {
+		want := t0.Add(time.Duration(10+5*i) * time.Minute).Format(timestampLayout)
+		if candle.CandleDateTimeKST != want || !candle.IsInterpolated || candle.CandleAccTradeVolume != 0 ||
+			candle.OpeningPrice != 101 || candle.HighPrice != 101 || candle.LowPrice != 101 || candle.TradePrice != 101 {
+			t.Errorf("채움 캔들 %+v, want %s 종가 101 거래량 0 보간", candle, want)
+		}
+	}
+
+	deleted, err := c.ClearFillToNow(tf)
+	if err != nil || deleted != 4 {
+		t.Errorf("ClearFillToNow = %d, %v, want 4", deleted, err)
+	}
+	if n := countRows(t, c, tf, ""); n != 2 {
+		t.Errorf("삭제 후 %d개, want 실제 캔들 2개", n)
+	}
+
+	c.MaxInterpolationGap = 3
+	if _, err := c.FillToNow(tf); err == nil {
+		t.Error("보간 한도를 넘는 채움이 허용됨")
+	}
+}
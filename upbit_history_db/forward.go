@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// forwardPageCandles - CollectForward 가 한 번에 요청하는 구간 길이 (캔들 수, API count 와 같음)
+const forwardPageCandles = 200
+
+// CollectForward - start(KST)부터 현재까지 과거 → 현재 순서로 tf 수집
+//
+// 업비트는 to 이전 최신 캔들만 돌려주므로 start 부터 200개 간격 뒤를 to 로 두고 구간을 앞으로 옮긴다.
+// 도중에 멈춰도 저장된 데이터는 start 부터 빈틈없이 이어진다 (알려진 상장 시점과 함께 쓰면 빈 구간 요청이 없다).
+// 거래가 없는 구간은 빈 페이지로 넘어가고, 구간 끝이 현재 시각을 넘으면 to 없이 최신 페이지를 받고 끝낸다.
+func (c *Collector) CollectForward(tf Timeframe, start time.Time) CollectResult {
+	fmt.Fprintf(c.Output, "\n%s\n", "============================================================")
+	fmt.Fprintf(c.Output, "%s %s 데이터 수집 시작 (%s 부터 현재 방향)\n", c.mark(markStart), tf.Name, start.Format(timestampLayout))
+	fmt.Fprintf(c.Output, "%s\n", "============================================================")
+
+	result := CollectResult{Timeframe: tf.Name}
+	if _, err := c.ClearFillToNow(tf); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 채움 캔들 삭제 실패: %v\n", tf.Name, c.mark(markWarn), err)
+	}
+
+	ctx := context.Background()
+	from := start
+	for {
+		nowKST := c.now().UTC().Add(9 * time.Hour)
+		if from.After(nowKST) {
+			break
+		}
+		end := from
+		for i := 0; i < forwardPageCandles; i++ {
+			end = candleEnd(tf, end)
+		}
+		last := end.After(nowKST)
+		to := ""
+		if !last {
+			to = end.Add(-9 * time.Hour).Format(time.RFC3339)
+		}
+
+		result.Pages++
+		candles, err := c.fetchCandles(ctx, tf, to, nil)
+		if errors.Is(err, errRequestBudget) {
+			result.Pages--
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 예산(%d회) 소진 - %s 까지 저장 후 중단\n",
+				tf.Name, c.mark(markPause), c.MaxRequests, from.Format(timestampLayout))
+			break
+		}
+		if err != nil && c.MaintenanceBackoff > 0 && responseStatus(err) == http.StatusServiceUnavailable {
+			result.Pages--
+			fmt.Fprintf(c.Output, "[%s] %s 업비트 점검 중 (503) - %v 후 같은 위치부터 재개\n",
+				tf.Name, c.mark(markPause), c.MaintenanceBackoff)
+			sleepCtx(ctx, c.MaintenanceBackoff)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Err = fmt.Errorf("API 요청 실패: %w", err)
+			break
+		}
+		result.Fetched += len(candles)
+
+		// 구간 시작 이전 캔들 제외 (거래가 드문 구간이면 to 이전 200개가 더 과거까지 이어짐)
+		lower := from.Format(timestampLayout)
+		kept := candles[:0]
+		for _, candle := range candles {
+			if candle.CandleDateTimeKST >= lower {
+				kept = append(kept, candle)
+			}
+		}
+
+		saved, failed, err := c.saveCandles(tf, kept)
+		result.Saved += saved
+		result.Rejected += len(failed)
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 저장 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Err = err
+			break
+		}
+
+		if result.Pages%10 == 0 {
+			fmt.Fprintf(c.Output, "[%s] 반복 %d: %s ~ %s 구간 %d개 저장 (총 %d개)\n",
+				tf.Name, result.Pages, lower, end.Format(timestampLayout), saved, result.Saved)
+		}
+		if last {
+			fmt.Fprintf(c.Output, "[%s] %s 현재 시각 도달. 수집 완료.\n", tf.Name, c.mark(markOK))
+			break
+		}
+		if c.MaxPages > 0 && result.Pages >= c.MaxPages {
+			fmt.Fprintf(c.Output, "[%s] %s 페이지 제한(%d) 도달. %s 까지 수집 후 중단.\n",
+				tf.Name, c.mark(markOK), c.MaxPages, end.Format(timestampLayout))
+			break
+		}
+		from = end
+	}
+
+	fmt.Fprintf(c.Output, "[%s] %s 총 %d개 캔들 수집 및 저장 완료\n", tf.Name, c.mark(markOK), result.Saved)
+	if result.Rejected > 0 {
+		fmt.Fprintf(c.Output, "[%s] %s 미래 시각, 저장 오류 등으로 %d개 캔들 저장하지 못함\n", tf.Name, c.mark(markWarn), result.Rejected)
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCollectForward(t *testing.T) {
+	head := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	headKST := head.Add(9 * time.Hour)
+	for _, tc := range []struct {
+		name       string
+		floor      time.Time
+		wantSaved  int
+		wantOldest time.Time // KST
+	}{
+		{"연속 구간", time.Time{}, 451, headKST.Add(-450 * time.Minute)},
+		// 상장 전 구간은 빈 페이지로 넘어감
+		{"상장 전 시작", head.Add(-100 * time.Minute), 101, headKST.Add(-100 * time.Minute)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeUpbit{head: head, floor: tc.floor}
+			var mu sync.Mutex
+			var tos []string
+			fake := f.handler(t)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				tos = append(tos, r.URL.Query().Get("to"))
+				mu.Unlock()
+				fake.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			c := openTestDB(t, "KRW-BTC")
+			c.apiURL = srv.URL
+			c.now = func() time.Time { return head.Add(30 * time.Second) }
+			tf := mustTimeframe(t, "minute1")
+
+			result := c.CollectForward(tf, headKST.Add(-450*time.Minute))
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+			if result.Saved != tc.wantSaved || result.Pages != 3 {
+				t.Errorf("saved = %d, pages = %d, want %d, 3", result.Saved, result.Pages, tc.wantSaved)
+			}
+			// 구간 끝이 앞으로 이동하다가 현재를 넘으면 to 없이 최신 페이지로 끝남
+			if len(tos) != 3 || tos[2] != "" || !(tos[0] < tos[1]) {
+				t.Errorf("to = %q, want 증가하는 2개 후 빈 값", tos)
+			}
+
+			// start(또는 상장 시점)부터 최신까지 빈틈없이 저장
+			candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(candles) != tc.wantSaved {
+				t.Fatalf("%d개 저장, want %d", len(candles), tc.wantSaved)
+			}
+			for i, candle := range candles {
+				if want := tc.wantOldest.Add(time.Duration(i) * time.Minute).Format(timestampLayout); candle.CandleDateTimeKST != want {
+					t.Fatalf("[%d] = %s, want %s", i, candle.CandleDateTimeKST, want)
+				}
+			}
+		})
+	}
+}
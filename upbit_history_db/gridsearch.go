@@ -0,0 +1,246 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GridObjective - GridSearch 결과 정렬 기준 (좋은 조합이 앞)
+type GridObjective int
+
+const (
+	// ObjectiveSharpe - 연환산 Sharpe 비율 높은 순
+	ObjectiveSharpe GridObjective = iota
+	// ObjectiveReturn - 총 수익률 높은 순
+	ObjectiveReturn
+	// ObjectiveDrawdown - 최대 낙폭 작은 순
+	ObjectiveDrawdown
+)
+
+func (o GridObjective) String() string {
+	switch o {
+	case ObjectiveSharpe:
+		return "sharpe"
+	case ObjectiveReturn:
+		return "return"
+	case ObjectiveDrawdown:
+		return "drawdown"
+	default:
+		return fmt.Sprintf("GridObjective(%d)", int(o))
+	}
+}
+
+// parseGridObjective - CLI 이름으로 정렬 기준 조회
+func parseGridObjective(name string) (GridObjective, error) {
+	for _, o := range []GridObjective{ObjectiveSharpe, ObjectiveReturn, ObjectiveDrawdown} {
+		if o.String() == name {
+			return o, nil
+		}
+	}
+	return 0, fmt.Errorf("알 수 없는 정렬 기준: %s (sharpe, return, drawdown)", name)
+}
+
+// better - a 가 b 보다 o 기준으로 좋은지
+func (o GridObjective) better(a, b GridResult) bool {
+	switch o {
+	case ObjectiveReturn:
+		return a.TotalReturn > b.TotalReturn
+	case ObjectiveDrawdown:
+		return a.MaxDrawdown < b.MaxDrawdown
+	default:
+		return a.Sharpe > b.Sharpe
+	}
+}
+
+// GridResult - 파라미터 조합 하나의 백테스트 지표
+type GridResult struct {
+	Params      map[string]float64 `json:"params"`
+	Trades      int                `json:"trades"`
+	FinalEquity float64            `json:"final_equity"`
+	TotalReturn float64            `json:"total_return"`
+	MaxDrawdown float64            `json:"max_drawdown"`
+	Sharpe      float64            `json:"sharpe"` // 캔들별 평가금액 수익률의 평균/표준편차 × √(연간 캔들 수)
+}
+
+// GridSearch - grid 의 파라미터 조합마다 factory 로 만든 전략을 백테스트하고 GridObjective 순으로 정렬
+//
+// 구간은 CommonRange 로 구한 tf 데이터 구간과 from ~ to 의 교집합이며, 캔들은 한 번만 읽어 모든 조합이
+// 함께 쓴다 (Backtest 는 캔들을 바꾸지 않음). 조합은 MaxConcurrency 개(0 이면 CPU 수)씩 병렬로 실행하고
+// 설정은 DefaultBacktestConfig 를 쓴다. 기준 값이 같으면 grid 순서를 유지한다.
+func (c *Collector) GridSearch(tf Timeframe, from, to time.Time, factory func(params map[string]float64) Strategy, grid []map[string]float64) ([]GridResult, error) {
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("파라미터 조합이 없습니다")
+	}
+	start, end, err := c.CommonRange([]Timeframe{tf})
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() && from.After(start) {
+		start = from
+	}
+	if !to.IsZero() && to.Before(end) {
+		end = to
+	}
+	candles, err := c.GetCandles(tf, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("%s 구간에 캔들이 없습니다", tf.Name)
+	}
+
+	workers := c.MaxConcurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	cfg := DefaultBacktestConfig()
+	results := make([]GridResult, len(grid))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, params := range grid {
+		wg.Add(1)
+		go func(i int, params map[string]float64) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := Backtest(candles, factory(params), cfg)
+			results[i] = GridResult{
+				Params:      params,
+				Trades:      len(result.Trades),
+				FinalEquity: result.FinalEquity,
+				TotalReturn: result.TotalReturn,
+				MaxDrawdown: result.MaxDrawdown,
+				Sharpe:      equitySharpe(tf, result.Equity),
+			}
+		}(i, params)
+	}
+	wg.Wait()
+
+	objective := c.GridObjective
+	sort.SliceStable(results, func(a, b int) bool { return objective.better(results[a], results[b]) })
+	return results, nil
+}
+
+// equitySharpe - 평가금액 곡선의 캔들별 수익률로 구한 연환산 Sharpe 비율 (무위험 수익률 0, 변동이 없으면 0)
+func equitySharpe(tf Timeframe, equity []IndicatorPoint) float64 {
+	returns := make([]float64, 0, len(equity))
+	for i := 1; i < len(equity); i++ {
+		if prev := equity[i-1].Value; prev > 0 {
+			returns = append(returns, equity[i].Value/prev-1)
+		}
+	}
+	sigma := stdDev(returns)
+	if sigma == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	return mean / sigma * math.Sqrt(periodsPerYear(tf))
+}
+
+// sweepSMACrossover - short × long 조합 (long 이 short 보다 큰 조합만)
+func sweepSMACrossover(shorts, longs []int) []map[string]float64 {
+	var grid []map[string]float64
+	for _, short := range shorts {
+		for _, long := range longs {
+			if short >= 1 && long > short {
+				grid = append(grid, map[string]float64{"short": float64(short), "long": float64(long)})
+			}
+		}
+	}
+	return grid
+}
+
+// parseIntList - "5,10,20" 형식
+func parseIntList(text string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(text, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("잘못된 숫자 %q: %w", part, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func runGridSearch(args []string) error {
+	fs := flag.NewFlagSet("grid-search", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "day", "백테스트 시간단위")
+	from := fs.String("from", "", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "종료 시각 (KST, 포함)")
+	shorts := fs.String("short", "5,10,20", "단기 SMA 기간 목록 (쉼표 구분)")
+	longs := fs.String("long", "20,50,100", "장기 SMA 기간 목록 (쉼표 구분)")
+	objective := fs.String("objective", ObjectiveSharpe.String(), "정렬 기준 (sharpe, return, drawdown)")
+	concurrency := fs.Int("concurrency", 0, "동시에 실행할 백테스트 수 (0 = CPU 수)")
+	top := fs.Int("top", 10, "출력할 상위 조합 수 (0 = 전부)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	shortList, err := parseIntList(*shorts)
+	if err != nil {
+		return fmt.Errorf("잘못된 --short: %w", err)
+	}
+	longList, err := parseIntList(*longs)
+	if err != nil {
+		return fmt.Errorf("잘못된 --long: %w", err)
+	}
+	order, err := parseGridObjective(*objective)
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.GridObjective = order
+	collector.MaxConcurrency = *concurrency
+
+	factory := func(params map[string]float64) Strategy {
+		return SMACrossover{Short: int(params["short"]), Long: int(params["long"])}
+	}
+	results, err := collector.GridSearch(tf, fromTime, toTime, factory, sweepSMACrossover(shortList, longList))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s SMA 교차 파라미터 탐색 (%s, %s 순, %d개 조합):\n", collector.mark(markStats), tf.Name, order, len(results))
+	fmt.Println("------------------------------------------------------------")
+	fmt.Printf("  %6s %6s %8s %10s %10s %8s\n", "short", "long", "거래 수", "수익률", "최대 낙폭", "Sharpe")
+	for i, r := range results {
+		if *top > 0 && i >= *top {
+			break
+		}
+		fmt.Printf("  %6d %6d %8d %9.2f%% %9.2f%% %8.2f\n", int(r.Params["short"]), int(r.Params["long"]),
+			r.Trades, r.TotalReturn*100, r.MaxDrawdown*100, r.Sharpe)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGridSearchSMACrossover(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.MaxConcurrency = 2
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	candles := make([]Candle, 120)
+	for i := range candles {
+		candles[i] = testCandle(t0.Add(time.Duration(i)*time.Minute), 100+10*math.Sin(float64(i)/6)+float64(i)/10)
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+	factory := func(params map[string]float64) Strategy {
+		return SMACrossover{Short: int(params["short"]), Long: int(params["long"])}
+	}
+	grid := sweepSMACrossover([]int{2, 3, 5}, []int{4, 10})
+	if len(grid) != 5 {
+		t.Fatalf("조합 %d개, want 5 (short < long 만)", len(grid))
+	}
+
+	for _, objective := range []GridObjective{ObjectiveSharpe, ObjectiveReturn, ObjectiveDrawdown} {
+		c.GridObjective = objective
+		results, err := c.GridSearch(tf, time.Time{}, time.Time{}, factory, grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != len(grid) {
+			t.Fatalf("%s: 결과 %d개, want %d", objective, len(results), len(grid))
+		}
+		seen := map[[2]float64]bool{}
+		for i, r := range results {
+			seen[[2]float64{r.Params["short"], r.Params["long"]}] = true
+			// 각 조합은 같은 캔들로 직접 돌린 백테스트와 같음
+			direct := Backtest(candles, factory(r.Params), DefaultBacktestConfig())
+			if r.Trades != len(direct.Trades) || r.FinalEquity != direct.FinalEquity || r.TotalReturn != direct.TotalReturn || r.MaxDrawdown != direct.MaxDrawdown {
+				t.Errorf("%s %v: %+v, want 직접 백테스트 %d 체결 %v", objective, r.Params, r, len(direct.Trades), direct.FinalEquity)
+			}
+			if i > 0 && objective.better(r, results[i-1]) {
+				t.Errorf("%s: [%d] %v 가 [%d] %v 보다 좋은데 뒤에 있음", objective, i, r.Params, i-1, results[i-1].Params)
+			}
+		}
+		if len(seen) != len(grid) {
+			t.Errorf("%s: 서로 다른 조합 %d개, want %d", objective, len(seen), len(grid))
+		}
+	}
+
+	// 범위를 좁히면 그 구간 캔들만 백테스트
+	c.GridObjective = ObjectiveSharpe
+	to := t0.Add(59 * time.Minute)
+	results, err := c.GridSearch(tf, time.Time{}, to, factory, grid[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := Backtest(candles[:60], factory(grid[0]), DefaultBacktestConfig())
+	want := GridResult{Params: grid[0], Trades: len(direct.Trades), FinalEquity: direct.FinalEquity,
+		TotalReturn: direct.TotalReturn, MaxDrawdown: direct.MaxDrawdown, Sharpe: equitySharpe(tf, direct.Equity)}
+	if want.Trades == 0 {
+		t.Fatal("09:00~09:59 구간에 체결이 없음 (테스트 데이터 확인)")
+	}
+	if !reflect.DeepEqual(results, []GridResult{want}) {
+		t.Errorf("09:00~09:59 결과 = %+v, want %+v", results, want)
+	}
+
+	if _, err := c.GridSearch(tf, time.Time{}, time.Time{}, factory, nil); err == nil {
+		t.Error("빈 grid 오류 없음")
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// histogramFormats - CandleHistogram bucket 별 strftime 형식
+var histogramFormats = map[string]string{
+	"year":  "%Y",
+	"month": "%Y-%m",
+}
+
+// HistogramBin - 연/월 구간별 캔들 수
+type HistogramBin struct {
+	Label        string `json:"label"` // 2024 또는 2024-01 (KST 기준)
+	Real         int    `json:"real"`
+	Interpolated int    `json:"interpolated"`
+}
+
+// Total - 실제 + 보간 캔들 수
+func (b HistogramBin) Total() int {
+	return b.Real + b.Interpolated
+}
+
+// CandleHistogram - 저장된 tf 캔들을 연(bucket "year") 또는 월("month")별로 세기 (label 오름차순)
+//
+// 보간 비율이 높은 기간이나 비어 있는 기간을 빠르게 찾는 용도다. 캔들이 없는 기간은 bin 이 없다.
+func (c *Collector) CandleHistogram(tf Timeframe, bucket string) ([]HistogramBin, error) {
+	format, ok := histogramFormats[bucket]
+	if !ok {
+		return nil, fmt.Errorf("알 수 없는 구간 단위: %s (year, month)", bucket)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT strftime('%s', timestamp) AS label,
+		       SUM(CASE WHEN is_interpolated = 0 THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN is_interpolated = 0 THEN 0 ELSE 1 END)
+		FROM %s GROUP BY label
+	`, format, c.table(tf))
+
+	// 연도 분할 시 같은 label 이 여러 파일에 나뉘지는 않지만 안전하게 합친다
+	byLabel := make(map[string]*HistogramBin)
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var bin HistogramBin
+			if err := rows.Scan(&bin.Label, &bin.Real, &bin.Interpolated); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if existing, ok := byLabel[bin.Label]; ok {
+				existing.Real += bin.Real
+				existing.Interpolated += bin.Interpolated
+				continue
+			}
+			byLabel[bin.Label] = &bin
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bins := make([]HistogramBin, 0, len(byLabel))
+	for _, bin := range byLabel {
+		bins = append(bins, *bin)
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Label < bins[j].Label })
+	return bins, nil
+}
+
+func (c *Collector) printHistogram(tf Timeframe, bins []HistogramBin) {
+	fmt.Fprintf(c.Output, "\n%s %s 캔들 분포 (%s):\n", c.mark(markStats), tf.Name, c.market)
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  %-8s %12s %12s %12s %8s\n", "기간", "total", "real", "interp", "interp%")
+	for _, bin := range bins {
+		mark := ""
+		if bin.Real == 0 {
+			mark = " " + c.mark(markWarn) + "전부 보간"
+		}
+		fmt.Fprintf(c.Output, "  %-8s %12s %12s %12s %7.2f%%%s\n", bin.Label, formatNumber(bin.Total()),
+			formatNumber(bin.Real), formatNumber(bin.Interpolated),
+			float64(bin.Interpolated)/float64(bin.Total())*100, mark)
+	}
+}
+
+func runHistogram(args []string) error {
+	fs := flag.NewFlagSet("histogram", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "day", "집계할 시간단위")
+	bucket := fs.String("bucket", "month", "구간 단위 (year, month)")
+	asJSON := fs.Bool("json", false, "JSON 으로 출력")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	common.quiet = *asJSON
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	bins, err := collector.CandleHistogram(tf, *bucket)
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		collector.printHistogram(tf, bins)
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bins)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCandleHistogram(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	at := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 0, 0, 0, time.UTC) }
+	seed(t, c, tf, at(2023, 6, 1), at(2023, 12, 30), at(2023, 12, 31), at(2024, 1, 1), at(2024, 1, 2), at(2024, 2, 10))
+	if _, err := c.db.Exec("UPDATE bitcoin_day SET is_interpolated = 1 WHERE timestamp IN (?, ?)",
+		at(2023, 12, 31).Format(timestampLayout), at(2024, 1, 1).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		bucket string
+		want   []HistogramBin
+	}{
+		{"year", []HistogramBin{{"2023", 2, 1}, {"2024", 2, 1}}},
+		{"month", []HistogramBin{{"2023-06", 1, 0}, {"2023-12", 1, 1}, {"2024-01", 1, 1}, {"2024-02", 1, 0}}},
+	} {
+		bins, err := c.CandleHistogram(tf, tc.bucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bins, tc.want) {
+			t.Errorf("%s: bins = %+v, want %+v", tc.bucket, bins, tc.want)
+		}
+	}
+	if _, err := c.CandleHistogram(tf, "week"); err == nil {
+		t.Error("알 수 없는 구간 단위에 오류 없음")
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// importBatch - ImportCSV 가 한 번에 저장하는 캔들 수
+const importBatch = 500
+
+// ImportAlignment - 시간단위 경계에 맞지 않는 timestamp 처리 방식
+type ImportAlignment int
+
+const (
+	// AlignReject - 경계에 맞지 않는 행은 저장하지 않음
+	AlignReject ImportAlignment = iota
+	// AlignSnap - 가장 가까운 경계로 옮겨 저장
+	AlignSnap
+)
+
+func (a ImportAlignment) String() string {
+	switch a {
+	case AlignReject:
+		return "reject"
+	case AlignSnap:
+		return "snap"
+	default:
+		return fmt.Sprintf("ImportAlignment(%d)", int(a))
+	}
+}
+
+// parseImportAlignment - CLI 이름으로 정렬 방식 조회
+func parseImportAlignment(name string) (ImportAlignment, error) {
+	for _, a := range []ImportAlignment{AlignReject, AlignSnap} {
+		if a.String() == name {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("알 수 없는 정렬 방식: %s (reject, snap)", name)
+}
+
+// ImportResult - ImportCSV 결과
+type ImportResult struct {
+	Read     int // CSV 데이터 행 수
+	Saved    int // 새로 저장한 캔들 수
+	Adjusted int // 경계로 옮긴 행 수 (AlignSnap)
+	Rejected int // 경계 불일치(AlignReject), 잘못된 timestamp, 미래 캔들, 행 저장 오류로 저장하지 않은 행 수
+	Skipped  int // 보간 캔들로 표시되어 건너뛴 행 수 (보간은 저장 후 다시 생성)
+}
+
+// ImportCSV - ExportCSV 형식 CSV 를 tf 실제 캔들로 저장 (중복 처리는 Conflict 설정을 따름)
+//
+// timestamp 가 시간단위 경계(업비트와 같은 UTC 기준, 월봉은 매월 1일 09:00 KST)에 맞지 않으면
+// ImportAlignment 에 따라 버리거나 가장 가까운 경계로 옮긴다. 어긋난 timestamp 가 섞이면
+// 보간의 구간 계산이 틀어지므로 그대로 저장하지 않는다.
+func (c *Collector) ImportCSV(tf Timeframe, r io.Reader) (ImportResult, error) {
+	var result ImportResult
+	reader, err := newCSVCandleReader(r)
+	if err != nil {
+		return result, err
+	}
+
+	batch := make([]Candle, 0, importBatch)
+	flush := func() error {
+		saved, failed, err := c.saveCandles(tf, batch)
+		result.Saved += saved
+		result.Rejected += len(failed)
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		candle, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Read++
+
+		if candle.IsInterpolated {
+			result.Skipped++
+			continue
+		}
+		adjusted, ok := c.alignImported(tf, &candle)
+		if !ok {
+			result.Rejected++
+			continue
+		}
+		if adjusted {
+			result.Adjusted++
+		}
+
+		batch = append(batch, candle)
+		if len(batch) == importBatch {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// alignImported - 가져온 캔들 timestamp 를 ImportAlignment 에 따라 검사/보정 (ok 가 false 면 저장하지 않음)
+func (c *Collector) alignImported(tf Timeframe, candle *Candle) (adjusted, ok bool) {
+	t, err := time.Parse(timestampLayout, candle.CandleDateTimeKST)
+	if err != nil {
+		// export --tz 로 내보낸 시간대 포함 timestamp
+		zoned, zerr := time.Parse(time.RFC3339, candle.CandleDateTimeKST)
+		if zerr != nil {
+			return false, false
+		}
+		t = zoned.UTC().Add(9 * time.Hour)
+		candle.CandleDateTimeKST = t.Format(timestampLayout)
+	}
+	if aligned := alignTimestamp(tf, t); !aligned.Equal(t) {
+		if c.ImportAlignment != AlignSnap {
+			return false, false
+		}
+		candle.CandleDateTimeKST = aligned.Format(timestampLayout)
+		adjusted = true
+	}
+	candle.CandleDateTimeUTC = ""
+	return adjusted, true
+}
+
+// MergeSourceResult - ImportMerge 의 원본 하나에 대한 결과
+type MergeSourceResult struct {
+	Read     int // CSV 데이터 행 수
+	Saved    int // 충돌에서 이겨 새로 저장한 캔들 수
+	Adjusted int // 경계로 옮긴 행 수 (AlignSnap)
+	Rejected int // 경계 불일치, 잘못된 timestamp, 미래 캔들, 행 저장 오류로 저장하지 않은 행 수
+	Skipped  int // 다른 원본(또는 같은 원본의 뒤 행)에 밀린 행과 보간으로 남은 행 수
+}
+
+// MergeReport - ImportMerge 결과 (Sources 는 readers 순서)
+type MergeReport struct {
+	Sources   []MergeSourceResult
+	Conflicts int // 둘 이상의 행이 같은 timestamp 를 가진 경우의 수
+}
+
+// mergeEntry - 병합 중인 timestamp 의 현재 선택 행
+type mergeEntry struct {
+	candle Candle
+	source int
+}
+
+// ImportMerge - ExportCSV 형식 CSV 여러 개를 timestamp 별로 하나만 골라 tf 실제 캔들로 저장
+//
+// 같은 timestamp 가 여러 행에 있으면 실제 캔들이 보간 캔들보다 우선하고, 같은 종류끼리는 나중에
+// 쓰인 행(readers 의 뒤 원본, 같은 원본이면 뒤 행)이 이긴다. 모든 원본을 읽고 고른 뒤 저장하므로
+// ImportCSV 를 여러 번 부를 때처럼 먼저 읽은 원본이 남지 않는다. 끝까지 보간 캔들만 남은 timestamp 는
+// 저장하지 않는다 (보간은 저장 후 다시 생성). DB 에 이미 있는 캔들 처리는 Conflict 설정을 따른다.
+func (c *Collector) ImportMerge(tf Timeframe, readers []io.Reader) (MergeReport, error) {
+	report := MergeReport{Sources: make([]MergeSourceResult, len(readers))}
+	merged := make(map[string]mergeEntry)
+
+	for i, r := range readers {
+		result := &report.Sources[i]
+		reader, err := newCSVCandleReader(r)
+		if err != nil {
+			return report, fmt.Errorf("원본 %d: %w", i+1, err)
+		}
+		for {
+			candle, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return report, fmt.Errorf("원본 %d: %w", i+1, err)
+			}
+			result.Read++
+
+			adjusted, ok := c.alignImported(tf, &candle)
+			if !ok {
+				result.Rejected++
+				continue
+			}
+			if adjusted {
+				result.Adjusted++
+			}
+
+			ts := candle.CandleDateTimeKST
+			current, exists := merged[ts]
+			if !exists {
+				merged[ts] = mergeEntry{candle: candle, source: i}
+				continue
+			}
+			report.Conflicts++
+			if current.candle.IsInterpolated || !candle.IsInterpolated {
+				report.Sources[current.source].Skipped++
+				merged[ts] = mergeEntry{candle: candle, source: i}
+			} else {
+				result.Skipped++
+			}
+		}
+	}
+
+	perSource := make([][]Candle, len(readers))
+	for _, entry := range merged {
+		if entry.candle.IsInterpolated {
+			report.Sources[entry.source].Skipped++
+			continue
+		}
+		perSource[entry.source] = append(perSource[entry.source], entry.candle)
+	}
+	for i, candles := range perSource {
+		sort.Slice(candles, func(a, b int) bool { return candles[a].CandleDateTimeKST < candles[b].CandleDateTimeKST })
+		for start := 0; start < len(candles); start += importBatch {
+			saved, failed, err := c.saveCandles(tf, candles[start:min(start+importBatch, len(candles))])
+			report.Sources[i].Saved += saved
+			report.Sources[i].Rejected += len(failed)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// alignTimestamp - kst 에 가장 가까운 tf 캔들 시작 시각 (KST)
+func alignTimestamp(tf Timeframe, kst time.Time) time.Time {
+	if tf.Name == "month" {
+		start := time.Date(kst.Year(), kst.Month(), 1, 9, 0, 0, 0, time.UTC)
+		if kst.Before(start) {
+			start = start.AddDate(0, -1, 0)
+		}
+		if next := start.AddDate(0, 1, 0); next.Sub(kst) < kst.Sub(start) {
+			return next
+		}
+		return start
+	}
+	// time.Round 는 서기 1년 1월 1일(월요일) 기준이므로 주봉도 월요일 00:00 UTC(09:00 KST)에 맞는다
+	utc := kst.Add(-9 * time.Hour)
+	return utc.Round(time.Duration(tf.Minutes) * time.Minute).Add(9 * time.Hour)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "가져올 시간단위 (필수, 예: minute5)")
+	in := fs.String("in", "", "CSV 파일 경로 (필수, export 형식, 쉼표로 여러 개면 병합해 가져옴)")
+	align := fs.String("align", AlignReject.String(), "경계에 맞지 않는 timestamp 처리 (reject, snap)")
+	onConflict := fs.String("on-conflict", ConflictIgnore.String(), "이미 있는 캔들 처리 (ignore, replace, error)")
+	format := fs.String("format", "csv", "입력 형식 (csv, binary = export --format binary 덤프, 보간 캔들도 복원)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "binary" {
+		return fmt.Errorf("알 수 없는 형식: %s (csv, binary)", *format)
+	}
+
+	if *timeframe == "" || *in == "" {
+		return fmt.Errorf("--timeframe 과 --in 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	alignment, err := parseImportAlignment(*align)
+	if err != nil {
+		return err
+	}
+	conflict, err := parseConflictMode(*onConflict)
+	if err != nil {
+		return err
+	}
+
+	paths := strings.Split(*in, ",")
+	if len(paths) > 1 && *format == "binary" {
+		return fmt.Errorf("binary 형식은 파일 하나만 가져올 수 있습니다")
+	}
+	readers := make([]io.Reader, len(paths))
+	for i, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		readers[i] = file
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.ImportAlignment = alignment
+	collector.Conflict = conflict
+
+	if len(paths) > 1 {
+		report, err := collector.ImportMerge(tf, readers)
+		if err != nil {
+			return fmt.Errorf("%s 병합 가져오기 실패: %w", *in, err)
+		}
+		for i, result := range report.Sources {
+			fmt.Printf("[%s] %s %s: %s행 중 %s개 저장 (경계 보정 %s, 거부 %s, 건너뜀 %s)\n", tf.Name, collector.mark(markOK),
+				paths[i], formatNumber(result.Read), formatNumber(result.Saved), formatNumber(result.Adjusted),
+				formatNumber(result.Rejected), formatNumber(result.Skipped))
+		}
+		fmt.Printf("[%s] 중복 timestamp %s건 정리\n", tf.Name, formatNumber(report.Conflicts))
+		return nil
+	}
+
+	if *format == "binary" {
+		result, err := collector.ImportBinary(tf, readers[0])
+		if err != nil {
+			return fmt.Errorf("%s 가져오기 실패: %w", *in, err)
+		}
+		fmt.Printf("[%s] %s %s개 레코드 중 %s개 저장 (경계 보정 %s, 거부 %s)\n", tf.Name, collector.mark(markOK),
+			formatNumber(result.Read), formatNumber(result.Saved), formatNumber(result.Adjusted), formatNumber(result.Rejected))
+		return nil
+	}
+
+	result, err := collector.ImportCSV(tf, readers[0])
+	if err != nil {
+		return fmt.Errorf("%s 가져오기 실패: %w", *in, err)
+	}
+	fmt.Printf("[%s] %s %s행 중 %s개 저장 (경계 보정 %s, 거부 %s, 보간 행 건너뜀 %s)\n", tf.Name, collector.mark(markOK),
+		formatNumber(result.Read), formatNumber(result.Saved), formatNumber(result.Adjusted),
+		formatNumber(result.Rejected), formatNumber(result.Skipped))
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// offBoundaryCSV - minute5 경계 09:00 과 경계에서 벗어난 09:07, 09:12 행
+const offBoundaryCSV = "timestamp,open,high,low,close,volume,value\n" +
+	"2024-01-01T09:00:00,1,1,1,1,1,1\n" +
+	"2024-01-01T09:07:00,2,2,2,2,1,1\n" +
+	"2024-01-01T09:12:00,3,3,3,3,1,1\n"
+
+func TestImportCSVAlignment(t *testing.T) {
+	tests := []struct {
+		align    ImportAlignment
+		want     ImportResult
+		wantKept []string
+	}{
+		{AlignReject, ImportResult{Read: 3, Saved: 1, Rejected: 2}, []string{"2024-01-01T09:00:00"}},
+		{AlignSnap, ImportResult{Read: 3, Saved: 3, Adjusted: 2}, []string{"2024-01-01T09:00:00", "2024-01-01T09:05:00", "2024-01-01T09:10:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.align.String(), func(t *testing.T) {
+			c := openTestDB(t, "KRW-BTC")
+			c.ImportAlignment = tt.align
+			tf := mustTimeframe(t, "minute5")
+
+			result, err := c.ImportCSV(tf, strings.NewReader(offBoundaryCSV))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.want {
+				t.Errorf("결과 = %+v, want %+v", result, tt.want)
+			}
+			candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kept []string
+			for _, candle := range candles {
+				kept = append(kept, candle.CandleDateTimeKST)
+			}
+			if strings.Join(kept, ",") != strings.Join(tt.wantKept, ",") {
+				t.Errorf("저장된 timestamp = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestAlignTimestamp(t *testing.T) {
+	tests := []struct{ tf, in, want string }{
+		{"minute5", "2024-01-01T09:02:00", "2024-01-01T09:00:00"},
+		{"minute5", "2024-01-01T09:03:00", "2024-01-01T09:05:00"},
+		{"day", "2024-01-01T20:00:00", "2024-01-01T09:00:00"},
+		{"week", "2024-01-03T09:00:00", "2024-01-01T09:00:00"}, // 2024-01-01 은 월요일
+		{"month", "2024-01-20T00:00:00", "2024-02-01T09:00:00"},
+		{"month", "2024-01-01T08:00:00", "2024-01-01T09:00:00"},
+	}
+	for _, tt := range tests {
+		in, err := time.Parse(timestampLayout, tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := alignTimestamp(mustTimeframe(t, tt.tf), in).Format(timestampLayout); got != tt.want {
+			t.Errorf("%s %s → %s, want %s", tt.tf, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestImportMergeResolvesConflicts(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute5")
+	header := "timestamp,open,high,low,close,volume,value,is_interpolated\n"
+	// 오래된 백업: 09:00 이 두 번 (뒤 행이 이김), 09:07 은 경계 불일치, 09:10 은 보간
+	older := header +
+		"2024-01-01T09:00:00,1,1,1,1,1,1,0\n" +
+		"2024-01-01T09:05:00,2,2,2,2,1,1,0\n" +
+		"2024-01-01T09:07:00,7,7,7,7,1,1,0\n" +
+		"2024-01-01T09:10:00,3,3,3,3,0,0,1\n" +
+		"2024-01-01T09:15:00,4,4,4,4,1,1,0\n" +
+		"2024-01-01T09:00:00,11,11,11,11,1,1,0\n"
+	// 새 백업: 09:05 는 실제끼리 충돌 (나중 원본이 이김), 09:15 는 보간이라 밀림, 09:25 는 보간만 있음
+	newer := header +
+		"2024-01-01T09:05:00,20,20,20,20,1,1,0\n" +
+		"2024-01-01T09:10:00,30,30,30,30,1,1,0\n" +
+		"2024-01-01T09:15:00,40,40,40,40,0,0,1\n" +
+		"2024-01-01T09:20:00,50,50,50,50,1,1,0\n" +
+		"2024-01-01T09:25:00,60,60,60,60,0,0,1\n"
+
+	report, err := c.ImportMerge(tf, []io.Reader{strings.NewReader(older), strings.NewReader(newer)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MergeReport{
+		Sources: []MergeSourceResult{
+			{Read: 6, Saved: 2, Rejected: 1, Skipped: 3},
+			{Read: 5, Saved: 3, Skipped: 2},
+		},
+		Conflicts: 4,
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantClose := map[string]float64{
+		"2024-01-01T09:00:00": 11,
+		"2024-01-01T09:05:00": 20,
+		"2024-01-01T09:10:00": 30,
+		"2024-01-01T09:15:00": 4,
+		"2024-01-01T09:20:00": 50,
+	}
+	if len(candles) != len(wantClose) {
+		t.Fatalf("캔들 %d개, want %d", len(candles), len(wantClose))
+	}
+	for _, candle := range candles {
+		if candle.IsInterpolated || candle.TradePrice != wantClose[candle.CandleDateTimeKST] {
+			t.Errorf("%s 종가 %v (보간 %v), want %v 실제 캔들", candle.CandleDateTimeKST, candle.TradePrice, candle.IsInterpolated, wantClose[candle.CandleDateTimeKST])
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// indicatorFuncs - ComputeAll 이 지원하는 지표 (IndicatorSpec.Name)
+//
+// CCI/Williams %R/MFI 는 정의상 고가/저가/종가(MFI 는 거래량까지)를 함께 쓰고 z-score 는 종가만 쓰므로 PriceField 를 받지 않는다 (fieldIndicators 참고).
+var indicatorFuncs = map[string]func(candles []Candle, period int, field PriceField) []IndicatorPoint{
+	"cci":        func(candles []Candle, period int, _ PriceField) []IndicatorPoint { return CCI(candles, period) },
+	"williams_r": func(candles []Candle, period int, _ PriceField) []IndicatorPoint { return WilliamsR(candles, period) },
+	"mfi":        func(candles []Candle, period int, _ PriceField) []IndicatorPoint { return MFI(candles, period) },
+	"sma":        SMA,
+	"zscore":     func(candles []Candle, period int, _ PriceField) []IndicatorPoint { return ZScore(candles, period) },
+}
+
+// fieldIndicators - IndicatorSpec.Field 로 원본 가격을 고를 수 있는 지표
+var fieldIndicators = map[string]bool{"sma": true}
+
+// IndicatorSpec - 계산해 저장할 지표와 파라미터
+type IndicatorSpec struct {
+	Name   string // indicatorFuncs 키 (cci, williams_r ...)
+	Period int
+	Field  PriceField // 원본 가격 (fieldIndicators 만, 기본: 종가)
+}
+
+// Key - indicators 테이블에 저장되는 지표 이름 (예: cci_20, 종가가 아니면 sma_20_typical)
+func (s IndicatorSpec) Key() string {
+	if s.Field != PriceClose {
+		return fmt.Sprintf("%s_%d_%s", s.Name, s.Period, s.Field)
+	}
+	return fmt.Sprintf("%s_%d", s.Name, s.Period)
+}
+
+func (s IndicatorSpec) validate() error {
+	if _, ok := indicatorFuncs[s.Name]; !ok {
+		return fmt.Errorf("알 수 없는 지표: %s", s.Name)
+	}
+	if s.Field != PriceClose && !fieldIndicators[s.Name] {
+		return fmt.Errorf("%s 는 가격 필드를 지정할 수 없습니다: %s", s.Name, s.Field)
+	}
+	if s.Period < 1 {
+		return fmt.Errorf("%s period 는 1 이상이어야 합니다: %d", s.Name, s.Period)
+	}
+	return nil
+}
+
+// parseIndicatorSpecs - "cci:20,williams_r:14,sma:20:typical" 형식 (가격 필드는 생략 가능)
+func parseIndicatorSpecs(text string) ([]IndicatorSpec, error) {
+	var specs []IndicatorSpec
+	for _, part := range strings.Split(text, ",") {
+		name, rest, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("지표 형식은 이름:기간[:가격] 입니다: %q", part)
+		}
+		period, fieldName, hasField := strings.Cut(rest, ":")
+		n, err := strconv.Atoi(period)
+		if err != nil {
+			return nil, fmt.Errorf("잘못된 기간 %q: %w", part, err)
+		}
+		spec := IndicatorSpec{Name: name, Period: n}
+		if hasField {
+			if spec.Field, err = parsePriceField(fieldName); err != nil {
+				return nil, err
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// ensureIndicatorTable - 지표 저장 테이블 (기본 DB 파일에 생성)
+func (c *Collector) ensureIndicatorTable() error {
+	_, err := c.db.Exec(`
+		CREATE TABLE IF NOT EXISTS indicators (
+			market TEXT NOT NULL,
+			timeframe TEXT NOT NULL,
+			name TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			value REAL NOT NULL,
+			PRIMARY KEY (market, timeframe, name, timestamp)
+		)
+	`)
+	return err
+}
+
+// ComputeAll - 모든 시간단위에 대해 지정한 지표를 계산해 indicators 테이블에 저장 (기존 값은 교체)
+//
+// 시간단위는 MaxConcurrency 제한으로 병렬 처리하고, 쓰기는 한 번에 하나씩 직렬화한다.
+// 실패한 시간단위/지표 오류를 모두 모아 반환한다.
+func (c *Collector) ComputeAll(indicators []IndicatorSpec) error {
+	for _, spec := range indicators {
+		if err := spec.validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.ensureIndicatorTable(); err != nil {
+		return fmt.Errorf("indicators 테이블 생성 실패: %w", err)
+	}
+
+	errs := make([][]error, len(timeframes))
+	c.forEachTimeframe(func(i int, tf Timeframe) {
+		candles, err := c.indicatorCandles(tf, 1)
+		if err != nil {
+			errs[i] = append(errs[i], fmt.Errorf("%s: %w", tf.Name, err))
+			return
+		}
+		for _, spec := range indicators {
+			points := indicatorFuncs[spec.Name](candles, spec.Period, spec.Field)
+			if err := c.storeIndicator(tf, spec, points); err != nil {
+				errs[i] = append(errs[i], fmt.Errorf("%s %s 저장 실패: %w", tf.Name, spec.Key(), err))
+				continue
+			}
+			fmt.Fprintf(c.Output, "[%s] %s %s %d개 저장\n", tf.Name, c.mark(markOK), spec.Key(), len(points))
+		}
+	})
+
+	var all []error
+	for _, tfErrs := range errs {
+		all = append(all, tfErrs...)
+	}
+	return errors.Join(all...)
+}
+
+// storeIndicator - 한 지표 시리즈를 트랜잭션으로 교체
+func (c *Collector) storeIndicator(tf Timeframe, spec IndicatorSpec, points []IndicatorPoint) error {
+	c.indicatorMu.Lock()
+	defer c.indicatorMu.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM indicators WHERE market = ? AND timeframe = ? AND name = ?",
+		c.market, tf.Name, spec.Key()); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO indicators (market, timeframe, name, timestamp, value) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range points {
+		if _, err := stmt.Exec(c.market, tf.Name, spec.Key(), p.Timestamp, p.Value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// StoredIndicator - ComputeAll 로 저장한 지표 시리즈 조회 (시간 오름차순)
+func (c *Collector) StoredIndicator(tf Timeframe, spec IndicatorSpec) ([]IndicatorPoint, error) {
+	if err := c.ensureIndicatorTable(); err != nil {
+		return nil, err
+	}
+	rows, err := c.db.Query(`
+		SELECT timestamp, value FROM indicators
+		WHERE market = ? AND timeframe = ? AND name = ?
+		ORDER BY timestamp ASC
+	`, c.market, tf.Name, spec.Key())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []IndicatorPoint
+	for rows.Next() {
+		var p IndicatorPoint
+		if err := rows.Scan(&p.Timestamp, &p.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// ExportIndicator - 지표 시리즈를 timestamp/value 컬럼의 CSV 또는 JSON 배열로 출력 (내보낸 개수 반환)
+//
+// indicators 테이블에 저장된 시리즈를 쓰고, 저장된 값이 없으면 그 자리에서 계산한다.
+// from/to 는 GetCandles 와 같다 (from 이상 to 이하 KST, zero 값이면 해당 방향 제한 없음).
+func (c *Collector) ExportIndicator(w io.Writer, tf Timeframe, spec IndicatorSpec, from, to time.Time, format string) (int, error) {
+	if err := spec.validate(); err != nil {
+		return 0, err
+	}
+	if format != "csv" && format != "json" {
+		return 0, fmt.Errorf("알 수 없는 형식: %s (csv, json)", format)
+	}
+
+	points, err := c.StoredIndicator(tf, spec)
+	if err != nil {
+		return 0, err
+	}
+	if len(points) == 0 {
+		candles, err := c.indicatorCandles(tf, spec.Period)
+		if err != nil {
+			return 0, err
+		}
+		points = indicatorFuncs[spec.Name](candles, spec.Period, spec.Field)
+	}
+
+	filtered := make([]IndicatorPoint, 0, len(points))
+	for _, p := range points {
+		if !from.IsZero() && p.Timestamp < from.Format(timestampLayout) {
+			continue
+		}
+		if !to.IsZero() && p.Timestamp > to.Format(timestampLayout) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return len(filtered), encoder.Encode(filtered)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"timestamp", spec.Key()}); err != nil {
+		return 0, err
+	}
+	for _, p := range filtered {
+		if err := writer.Write([]string{p.Timestamp, formatFloat(p.Value)}); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return len(filtered), writer.Error()
+}
+
+func runIndicators(args []string) error {
+	fs := flag.NewFlagSet("indicators", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	names := make([]string, 0, len(indicatorFuncs))
+	for name := range indicatorFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	specText := fs.String("spec", "cci:20,williams_r:14", "계산할 지표 이름:기간[:가격] 목록 (가격: close, open, high, low, typical / 지원: "+strings.Join(names, ", ")+")")
+	concurrency := fs.Int("concurrency", 0, "동시에 계산할 시간단위 수 (0 = 전체 동시)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	specs, err := parseIndicatorSpecs(*specText)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.MaxConcurrency = *concurrency
+
+	return collector.ComputeAll(specs)
+}
+
+func runExportIndicator(args []string) error {
+	fs := flag.NewFlagSet("export-indicator", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "day", "지표 시간단위")
+	specText := fs.String("spec", "", "내보낼 지표 이름:기간[:가격] (필수, 예: cci:20, sma:20:typical)")
+	format := fs.String("format", "csv", "출력 형식 (csv, json)")
+	out := fs.String("out", "-", "출력 파일 경로 (- 이면 표준출력)")
+	from := fs.String("from", "", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "종료 시각 (KST, 포함)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *specText == "" {
+		return fmt.Errorf("--spec 이 필요합니다")
+	}
+	specs, err := parseIndicatorSpecs(*specText)
+	if err != nil {
+		return err
+	}
+	if len(specs) != 1 {
+		return fmt.Errorf("--spec 에는 지표를 하나만 지정합니다: %s", *specText)
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	// 표준출력으로 내보낼 때는 안내 메시지가 섞이지 않도록 생략
+	common.quiet = *out == "-"
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	if *out == "-" {
+		_, err := collector.ExportIndicator(os.Stdout, tf, specs[0], fromTime, toTime, *format)
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	n, err := collector.ExportIndicator(file, tf, specs[0], fromTime, toTime, *format)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s 내보내기 실패: %w", *out, err)
+	}
+	fmt.Printf("[%s] %s %s %d개를 %s 로 내보냄\n", tf.Name, collector.mark(markOK), specs[0].Key(), n, *out)
+	return nil
+}
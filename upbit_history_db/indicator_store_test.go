@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestComputeAllStoresEverySeries(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.MaxConcurrency = 3
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+	for _, tf := range timeframes {
+		times := make([]time.Time, 30)
+		for i := range times {
+			times[i] = t0.Add(time.Duration(i*tf.Minutes) * time.Minute)
+		}
+		seed(t, c, tf, times...)
+	}
+
+	specs := []IndicatorSpec{{Name: "cci", Period: 20}, {Name: "williams_r", Period: 14}, {Name: "sma", Period: 5, Field: PriceTypical}}
+	if err := c.ComputeAll(specs); err != nil {
+		t.Fatal(err)
+	}
+	for _, tf := range timeframes {
+		candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, spec := range specs {
+			stored, err := c.StoredIndicator(tf, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(stored) != 30-spec.Period+1 {
+				t.Errorf("%s %s: %d개, want %d", tf.Name, spec.Key(), len(stored), 30-spec.Period+1)
+			}
+			assertPoints(t, stored, indicatorFuncs[spec.Name](candles, spec.Period, spec.Field), 1e-9)
+		}
+	}
+
+	var keys int
+	if err := c.db.QueryRow("SELECT COUNT(DISTINCT timeframe || '/' || name) FROM indicators").Scan(&keys); err != nil {
+		t.Fatal(err)
+	}
+	if want := len(timeframes) * len(specs); keys != want {
+		t.Errorf("저장된 시리즈 %d개, want %d", keys, want)
+	}
+}
+
+func TestExportIndicatorRoundTrip(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	times := kstMinutes(t0, 6)
+	seed(t, c, tf, times...)
+
+	// 저장된 시리즈를 쓰는지 확인하려고 계산값과 다른 값을 저장
+	spec := IndicatorSpec{Name: "cci", Period: 2}
+	stored := make([]IndicatorPoint, 5)
+	for i := range stored {
+		stored[i] = IndicatorPoint{Timestamp: times[i+1].Format(timestampLayout), Value: float64(i)/3 - 1}
+	}
+	if err := c.ensureIndicatorTable(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.storeIndicator(tf, spec, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := c.ExportIndicator(&buf, tf, spec, times[2], times[4], "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []IndicatorPoint
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || !reflect.DeepEqual(decoded, stored[1:4]) {
+		t.Errorf("json %d개 = %+v, want %+v", n, decoded, stored[1:4])
+	}
+
+	buf.Reset()
+	if _, err := c.ExportIndicator(&buf, tf, spec, time.Time{}, time.Time{}, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(stored)+1 || records[0][0] != "timestamp" || records[0][1] != spec.Key() {
+		t.Fatalf("csv = %v", records)
+	}
+	for i, p := range stored {
+		if v, err := strconv.ParseFloat(records[i+1][1], 64); records[i+1][0] != p.Timestamp || err != nil || v != p.Value {
+			t.Errorf("csv [%d] = %v, want %+v", i, records[i+1], p)
+		}
+	}
+
+	// 저장되지 않은 시리즈는 그 자리에서 계산
+	buf.Reset()
+	sma := IndicatorSpec{Name: "sma", Period: 3}
+	if _, err := c.ExportIndicator(&buf, tf, sma, time.Time{}, time.Time{}, "json"); err != nil {
+		t.Fatal(err)
+	}
+	decoded = nil
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, decoded, SMA(candles, 3, PriceClose), 0)
+
+	if _, err := c.ExportIndicator(&buf, tf, spec, time.Time{}, time.Time{}, "xml"); err == nil {
+		t.Error("알 수 없는 형식에 오류 없음")
+	}
+}
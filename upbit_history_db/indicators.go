@@ -0,0 +1,483 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// IndicatorPoint - 지표 값 하나 (timestamp 는 캔들과 같은 KST 문자열)
+type IndicatorPoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// PriceField - 단일 가격 시계열 지표(SMA 등)가 사용할 캔들 값
+type PriceField int
+
+const (
+	// PriceClose - 종가 (trade_price, 기본값)
+	PriceClose PriceField = iota
+	// PriceOpen - 시가
+	PriceOpen
+	// PriceHigh - 고가
+	PriceHigh
+	// PriceLow - 저가
+	PriceLow
+	// PriceTypical - (고가 + 저가 + 종가) / 3
+	PriceTypical
+)
+
+func (f PriceField) String() string {
+	switch f {
+	case PriceClose:
+		return "close"
+	case PriceOpen:
+		return "open"
+	case PriceHigh:
+		return "high"
+	case PriceLow:
+		return "low"
+	case PriceTypical:
+		return "typical"
+	default:
+		return fmt.Sprintf("PriceField(%d)", int(f))
+	}
+}
+
+// parsePriceField - CLI 이름으로 가격 필드 조회
+func parsePriceField(name string) (PriceField, error) {
+	for _, f := range []PriceField{PriceClose, PriceOpen, PriceHigh, PriceLow, PriceTypical} {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("알 수 없는 가격 필드: %s (close, open, high, low, typical)", name)
+}
+
+// Of - candle 의 해당 가격
+func (f PriceField) Of(candle Candle) float64 {
+	switch f {
+	case PriceOpen:
+		return candle.OpeningPrice
+	case PriceHigh:
+		return candle.HighPrice
+	case PriceLow:
+		return candle.LowPrice
+	case PriceTypical:
+		return candle.TypicalPrice()
+	default:
+		return candle.TradePrice
+	}
+}
+
+// indicatorCandles - 지표 계산용 전체 캔들 (시간 오름차순)
+//
+// IncludeProvisional 이 false 면 아직 마감되지 않은 마지막 캔들을 빼서 지표 값이 흔들리지 않게 한다.
+func (c *Collector) indicatorCandles(tf Timeframe, period int) ([]Candle, error) {
+	if period < 1 {
+		return nil, fmt.Errorf("period 는 1 이상이어야 합니다: %d", period)
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if n := len(candles); n > 0 && !c.IncludeProvisional && c.isProvisional(tf, candles[n-1].CandleDateTimeKST) {
+		candles = candles[:n-1]
+	}
+	return candles, nil
+}
+
+// ComputeCCI - Commodity Channel Index
+//
+//	CCI = (TP - SMA(TP)) / (0.015 * 평균 절대 편차)
+//
+// 첫 값은 period 번째 캔들(인덱스 period-1)부터 나온다. 편차가 0 인 평탄 구간은 0 으로 둔다.
+func (c *Collector) ComputeCCI(tf Timeframe, period int) ([]IndicatorPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return CCI(candles, period), nil
+}
+
+// CCI - 캔들 목록으로 CCI 계산 (ComputeCCI 참고)
+func CCI(candles []Candle, period int) []IndicatorPoint {
+	if period < 1 || len(candles) < period {
+		return nil
+	}
+
+	tp := make([]float64, len(candles))
+	for i, candle := range candles {
+		tp[i] = candle.TypicalPrice()
+	}
+
+	points := make([]IndicatorPoint, 0, len(candles)-period+1)
+	for i := period - 1; i < len(candles); i++ {
+		window := tp[i-period+1 : i+1]
+		mean := 0.0
+		for _, v := range window {
+			mean += v
+		}
+		mean /= float64(period)
+
+		deviation := 0.0
+		for _, v := range window {
+			deviation += math.Abs(v - mean)
+		}
+		deviation /= float64(period)
+
+		value := 0.0
+		if deviation > 0 {
+			value = (tp[i] - mean) / (0.015 * deviation)
+		}
+		points = append(points, IndicatorPoint{Timestamp: candles[i].CandleDateTimeKST, Value: value})
+	}
+	return points
+}
+
+// ComputeSMA - field 가격의 단순 이동평균
+//
+// 첫 값은 period 번째 캔들(인덱스 period-1)부터 나온다.
+func (c *Collector) ComputeSMA(tf Timeframe, period int, field PriceField) ([]IndicatorPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return SMA(candles, period, field), nil
+}
+
+// SMA - 캔들 목록으로 field 가격의 단순 이동평균 계산 (ComputeSMA 참고)
+func SMA(candles []Candle, period int, field PriceField) []IndicatorPoint {
+	if period < 1 || len(candles) < period {
+		return nil
+	}
+
+	points := make([]IndicatorPoint, 0, len(candles)-period+1)
+	sum := 0.0
+	for i, candle := range candles {
+		sum += field.Of(candle)
+		if i >= period {
+			sum -= field.Of(candles[i-period])
+		}
+		if i >= period-1 {
+			points = append(points, IndicatorPoint{Timestamp: candle.CandleDateTimeKST, Value: sum / float64(period)})
+		}
+	}
+	return points
+}
+
+// ComputeZScore - 종가의 이동 z-score
+//
+//	Z = (종가 - window 개 종가 평균) / window 개 종가 표본 표준편차
+//
+// 평균/표준편차 구간에 현재 캔들을 포함하므로 첫 값은 window 번째 캔들(인덱스 window-1)부터 나온다.
+// 표준편차가 0 인 평탄 구간은 0 으로 둔다.
+func (c *Collector) ComputeZScore(tf Timeframe, window int) ([]IndicatorPoint, error) {
+	candles, err := c.indicatorCandles(tf, window)
+	if err != nil {
+		return nil, err
+	}
+	return ZScore(candles, window), nil
+}
+
+// ZScore - 캔들 목록으로 이동 z-score 계산 (ComputeZScore 참고)
+func ZScore(candles []Candle, window int) []IndicatorPoint {
+	if window < 1 || len(candles) < window {
+		return nil
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.TradePrice
+	}
+
+	points := make([]IndicatorPoint, 0, len(candles)-window+1)
+	for i := window - 1; i < len(candles); i++ {
+		values := closes[i-window+1 : i+1]
+		mean := 0.0
+		for _, v := range values {
+			mean += v
+		}
+		mean /= float64(window)
+
+		value := 0.0
+		if sd := stdDev(values); sd > 0 {
+			value = (closes[i] - mean) / sd
+		}
+		points = append(points, IndicatorPoint{Timestamp: candles[i].CandleDateTimeKST, Value: value})
+	}
+	return points
+}
+
+// ComputeWilliamsR - Williams %R
+//
+//	%R = -100 * (최고 고가 - 종가) / (최고 고가 - 최저 저가)
+//
+// 첫 값은 period 번째 캔들(인덱스 period-1)부터 나온다. 고가와 저가가 같은 평탄 구간은 -50 으로 둔다.
+func (c *Collector) ComputeWilliamsR(tf Timeframe, period int) ([]IndicatorPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return WilliamsR(candles, period), nil
+}
+
+// WilliamsR - 캔들 목록으로 Williams %R 계산 (ComputeWilliamsR 참고)
+func WilliamsR(candles []Candle, period int) []IndicatorPoint {
+	if period < 1 || len(candles) < period {
+		return nil
+	}
+
+	points := make([]IndicatorPoint, 0, len(candles)-period+1)
+	for i := period - 1; i < len(candles); i++ {
+		highest, lowest := candles[i].HighPrice, candles[i].LowPrice
+		for _, candle := range candles[i-period+1 : i] {
+			highest = math.Max(highest, candle.HighPrice)
+			lowest = math.Min(lowest, candle.LowPrice)
+		}
+
+		value := -50.0
+		if highest > lowest {
+			value = -100 * (highest - candles[i].TradePrice) / (highest - lowest)
+		}
+		points = append(points, IndicatorPoint{Timestamp: candles[i].CandleDateTimeKST, Value: value})
+	}
+	return points
+}
+
+// ComputeMFI - Money Flow Index (거래량 가중 RSI)
+//
+//	자금 흐름 = TP * 거래량 (TP 가 직전보다 오르면 양, 내리면 음, 같으면 제외)
+//	MFI = 100 - 100 / (1 + period 개 양의 흐름 합 / 음의 흐름 합)
+//
+// 흐름은 두 번째 캔들부터 생기므로 첫 값은 인덱스 period 부터 나온다. 음의 흐름이 없으면 100,
+// 양/음 흐름이 모두 없는 평탄 구간은 50 으로 둔다.
+func (c *Collector) ComputeMFI(tf Timeframe, period int) ([]IndicatorPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return MFI(candles, period), nil
+}
+
+// MFI - 캔들 목록으로 Money Flow Index 계산 (ComputeMFI 참고)
+func MFI(candles []Candle, period int) []IndicatorPoint {
+	if period < 1 || len(candles) <= period {
+		return nil
+	}
+
+	positive := make([]float64, len(candles))
+	negative := make([]float64, len(candles))
+	for i := 1; i < len(candles); i++ {
+		tp, prev := candles[i].TypicalPrice(), candles[i-1].TypicalPrice()
+		flow := tp * candles[i].CandleAccTradeVolume
+		switch {
+		case tp > prev:
+			positive[i] = flow
+		case tp < prev:
+			negative[i] = flow
+		}
+	}
+
+	points := make([]IndicatorPoint, 0, len(candles)-period)
+	for i := period; i < len(candles); i++ {
+		pos, neg := 0.0, 0.0
+		for j := i - period + 1; j <= i; j++ {
+			pos += positive[j]
+			neg += negative[j]
+		}
+
+		value := 50.0
+		switch {
+		case neg == 0 && pos > 0:
+			value = 100
+		case neg > 0:
+			value = 100 - 100/(1+pos/neg)
+		}
+		points = append(points, IndicatorPoint{Timestamp: candles[i].CandleDateTimeKST, Value: value})
+	}
+	return points
+}
+
+// DonchianPoint - Donchian 채널 (period 개 캔들의 최고 고가, 최저 저가, 중간선)
+type DonchianPoint struct {
+	Timestamp string  `json:"timestamp"`
+	Upper     float64 `json:"upper"`
+	Lower     float64 `json:"lower"`
+	Middle    float64 `json:"middle"`
+}
+
+// ComputeDonchian - Donchian 채널 (터틀식 돌파 전략의 기준선)
+//
+// 채널에 현재 캔들을 포함하므로 첫 값은 period 번째 캔들(인덱스 period-1)부터 나온다.
+// 돌파를 판단할 때는 현재 종가를 직전 캔들의 채널과 비교한다.
+func (c *Collector) ComputeDonchian(tf Timeframe, period int) ([]DonchianPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return Donchian(candles, period), nil
+}
+
+// Donchian - 캔들 목록으로 Donchian 채널 계산 (ComputeDonchian 참고)
+func Donchian(candles []Candle, period int) []DonchianPoint {
+	if period < 1 || len(candles) < period {
+		return nil
+	}
+
+	points := make([]DonchianPoint, 0, len(candles)-period+1)
+	for i := period - 1; i < len(candles); i++ {
+		highest, lowest := candles[i].HighPrice, candles[i].LowPrice
+		for _, candle := range candles[i-period+1 : i] {
+			highest = math.Max(highest, candle.HighPrice)
+			lowest = math.Min(lowest, candle.LowPrice)
+		}
+		points = append(points, DonchianPoint{
+			Timestamp: candles[i].CandleDateTimeKST,
+			Upper:     highest,
+			Lower:     lowest,
+			Middle:    (highest + lowest) / 2,
+		})
+	}
+	return points
+}
+
+// PivotPoint - 한 캔들의 고가/저가/종가로 계산한 다음 기간 지지/저항선
+type PivotPoint struct {
+	Timestamp string  `json:"timestamp"` // 기준 캔들 timestamp (레벨은 그 다음 기간에 적용)
+	P         float64 `json:"p"`
+	R1        float64 `json:"r1"`
+	S1        float64 `json:"s1"`
+	R2        float64 `json:"r2"`
+	S2        float64 `json:"s2"`
+	R3        float64 `json:"r3"`
+	S3        float64 `json:"s3"`
+}
+
+// ComputePivots - 클래식 피벗 포인트 (캔들마다 한 세트)
+//
+// 보통 day/week 캔들로 다음 날/주의 레벨을 구할 때 쓴다. 다른 시간단위도 계산은 하되 경고를 출력한다.
+func (c *Collector) ComputePivots(tf Timeframe) ([]PivotPoint, error) {
+	if tf.Name != "day" && tf.Name != "week" {
+		fmt.Fprintf(c.Output, "[%s] %s 피벗 포인트는 보통 day/week 캔들에 사용합니다\n", tf.Name, c.mark(markWarn))
+	}
+	candles, err := c.indicatorCandles(tf, 1)
+	if err != nil {
+		return nil, err
+	}
+	return Pivots(candles), nil
+}
+
+// Pivots - 캔들 목록으로 클래식 피벗 포인트 계산
+//
+//	P  = (H + L + C) / 3
+//	R1 = 2P - L,       S1 = 2P - H
+//	R2 = P + (H - L),  S2 = P - (H - L)
+//	R3 = H + 2(P - L), S3 = L - 2(H - P)
+func Pivots(candles []Candle) []PivotPoint {
+	points := make([]PivotPoint, len(candles))
+	for i, candle := range candles {
+		h, l := candle.HighPrice, candle.LowPrice
+		p := candle.TypicalPrice()
+		points[i] = PivotPoint{
+			Timestamp: candle.CandleDateTimeKST,
+			P:         p,
+			R1:        2*p - l,
+			S1:        2*p - h,
+			R2:        p + (h - l),
+			S2:        p - (h - l),
+			R3:        h + 2*(p-l),
+			S3:        l - 2*(h-p),
+		}
+	}
+	return points
+}
+
+// ADXPoint - 방향성 지표 값 (+DI, -DI, ADX)
+type ADXPoint struct {
+	Timestamp string  `json:"timestamp"`
+	PlusDI    float64 `json:"plus_di"`
+	MinusDI   float64 `json:"minus_di"`
+	ADX       float64 `json:"adx"`
+}
+
+// ComputeADX - Wilder 의 Average Directional Index
+//
+//	+DM = 고가 상승폭 (하락폭보다 크고 양수일 때), -DM = 저가 하락폭 (상승폭보다 크고 양수일 때)
+//	TR  = max(고가 - 저가, |고가 - 직전 종가|, |저가 - 직전 종가|)
+//	+DI = 100 * Wilder(+DM) / Wilder(TR), -DI 도 같은 방식
+//	DX  = 100 * |+DI - -DI| / (+DI + -DI), ADX = DX 의 Wilder 평활
+//
+// DM/TR 은 두 번째 캔들부터 생기므로 첫 DI 는 인덱스 period, 첫 ADX 는 그 뒤 DX period 개를
+// 평균한 인덱스 2*period-1 (2*period 번째 캔들)에서 나온다. 결과는 이 캔들부터 시작한다.
+// TR 이 0 인 평탄 구간은 DI 를 0 으로, +DI 와 -DI 가 모두 0 이면 DX 를 0 으로 둔다.
+func (c *Collector) ComputeADX(tf Timeframe, period int) ([]ADXPoint, error) {
+	candles, err := c.indicatorCandles(tf, period)
+	if err != nil {
+		return nil, err
+	}
+	return ADX(candles, period), nil
+}
+
+// ADX - 캔들 목록으로 +DI, -DI, ADX 계산 (ComputeADX 참고)
+func ADX(candles []Candle, period int) []ADXPoint {
+	if period < 1 || len(candles) < 2*period {
+		return nil
+	}
+
+	n := float64(period)
+	var tr, plusDM, minusDM, adx float64
+	dxSum := 0.0
+	points := make([]ADXPoint, 0, len(candles)-2*period+1)
+	for i := 1; i < len(candles); i++ {
+		cur, prev := candles[i], candles[i-1]
+		up := cur.HighPrice - prev.HighPrice
+		down := prev.LowPrice - cur.LowPrice
+		pdm, mdm := 0.0, 0.0
+		if up > down && up > 0 {
+			pdm = up
+		}
+		if down > up && down > 0 {
+			mdm = down
+		}
+		trueRange := max(cur.HighPrice-cur.LowPrice,
+			math.Abs(cur.HighPrice-prev.TradePrice), math.Abs(cur.LowPrice-prev.TradePrice))
+
+		// 첫 period 개는 합계, 이후는 Wilder 평활 (S = S - S/n + x)
+		if i <= period {
+			tr += trueRange
+			plusDM += pdm
+			minusDM += mdm
+			if i < period {
+				continue
+			}
+		} else {
+			tr = tr - tr/n + trueRange
+			plusDM = plusDM - plusDM/n + pdm
+			minusDM = minusDM - minusDM/n + mdm
+		}
+
+		plusDI, minusDI := 0.0, 0.0
+		if tr > 0 {
+			plusDI = 100 * plusDM / tr
+			minusDI = 100 * minusDM / tr
+		}
+		dx := 0.0
+		if sum := plusDI + minusDI; sum > 0 {
+			dx = 100 * math.Abs(plusDI-minusDI) / sum
+		}
+
+		switch {
+		case i < 2*period-1:
+			dxSum += dx
+			continue
+		case i == 2*period-1:
+			adx = (dxSum + dx) / n
+		default:
+			adx = (adx*(n-1) + dx) / n
+		}
+		points = append(points, ADXPoint{Timestamp: cur.CandleDateTimeKST, PlusDI: plusDI, MinusDI: minusDI, ADX: adx})
+	}
+	return points
+}
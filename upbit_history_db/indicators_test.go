@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// typicalCandles - 고가=저가=종가=v 라서 typical price 가 v 인 캔들 (timestamp 는 인덱스)
+func typicalCandles(values ...float64) []Candle {
+	candles := make([]Candle, len(values))
+	for i, v := range values {
+		candles[i] = Candle{
+			CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i),
+			OpeningPrice:      v,
+			HighPrice:         v,
+			LowPrice:          v,
+			TradePrice:        v,
+		}
+	}
+	return candles
+}
+
+// assertPoints - 지표 값과 timestamp 가 want 와 eps 이내로 같은지
+func assertPoints(t *testing.T, got []IndicatorPoint, want []IndicatorPoint, eps float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d개, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Timestamp != want[i].Timestamp || !closeTo(got[i].Value, want[i].Value, eps) || math.IsNaN(got[i].Value) {
+			t.Errorf("[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCCI(t *testing.T) {
+	candles := typicalCandles(10, 11, 12, 14, 13)
+	// 손으로 계산: 창 [10 11 12] 평균 11, 평균편차 2/3 → (12-11)/(0.015×2/3) = 100
+	assertPoints(t, CCI(candles, 3), []IndicatorPoint{
+		{Timestamp: candles[2].CandleDateTimeKST, Value: 100},
+		{Timestamp: candles[3].CandleDateTimeKST, Value: 100},
+		{Timestamp: candles[4].CandleDateTimeKST, Value: 0},
+	}, 1e-9)
+
+	// 가격이 변하지 않으면 편차가 0 이므로 NaN 대신 0
+	flat := CCI(typicalCandles(5, 5, 5, 5), 2)
+	for _, p := range flat {
+		if p.Value != 0 {
+			t.Errorf("평평한 구간 CCI = %v, want 0", p.Value)
+		}
+	}
+	if CCI(candles, 6) != nil || CCI(candles, 0) != nil {
+		t.Error("캔들이 period 보다 적거나 period 가 0 이면 nil")
+	}
+}
+
+func TestWilliamsR(t *testing.T) {
+	hlc := [][3]float64{{10, 5, 8}, {12, 7, 9}, {11, 6, 10}, {9, 6, 6}}
+	candles := make([]Candle, len(hlc))
+	for i, v := range hlc {
+		candles[i] = Candle{CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i), HighPrice: v[0], LowPrice: v[1], TradePrice: v[2]}
+	}
+	// 손으로 계산: 창 0~2 최고 12, 최저 5, 종가 10 → -100×2/7, 창 1~3 최고 12, 최저 6, 종가 6 → -100
+	assertPoints(t, WilliamsR(candles, 3), []IndicatorPoint{
+		{Timestamp: candles[2].CandleDateTimeKST, Value: -100 * 2.0 / 7},
+		{Timestamp: candles[3].CandleDateTimeKST, Value: -100},
+	}, 1e-9)
+
+	// 고가와 저가가 같은 평탄 구간은 -50
+	for _, p := range WilliamsR(typicalCandles(5, 5, 5), 2) {
+		if p.Value != -50 {
+			t.Errorf("평탄 구간 %%R = %v, want -50", p.Value)
+		}
+	}
+	if WilliamsR(candles, 5) != nil {
+		t.Error("캔들이 period 보다 적으면 nil")
+	}
+}
+
+func TestIndicatorsDropProvisionalCandle(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute60")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.Add(time.Hour), t0.Add(2*time.Hour))
+	last := t0.Add(2 * time.Hour).Format(timestampLayout)
+
+	// KST 11:30 - 11:00 캔들은 아직 진행 중
+	c.now = func() time.Time { return t0.Add(-9*time.Hour + 2*time.Hour + 30*time.Minute) }
+	points, err := c.ComputeWilliamsR(tf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[len(points)-1].Timestamp == last {
+		t.Errorf("진행 중 캔들 제외 = %v, want 마감된 2개", points)
+	}
+
+	c.IncludeProvisional = true
+	if points, err = c.ComputeWilliamsR(tf, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || points[2].Timestamp != last {
+		t.Errorf("IncludeProvisional = %v, want 마지막 %s 포함 3개", points, last)
+	}
+
+	// 마감된 뒤에는 기본값으로도 포함
+	c.IncludeProvisional = false
+	c.now = func() time.Time { return t0.Add(-9*time.Hour + 3*time.Hour) }
+	if points, err = c.ComputeWilliamsR(tf, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 {
+		t.Errorf("마감 후 %d개, want 3", len(points))
+	}
+}
+
+func TestPivots(t *testing.T) {
+	// 손으로 계산: H 120, L 90, C 111 → P 107, R1 124, S1 94, R2 137, S2 77, R3 154, S3 64
+	got := Pivots([]Candle{{CandleDateTimeKST: "2024-01-01T09:00:00", HighPrice: 120, LowPrice: 90, TradePrice: 111}})
+	want := PivotPoint{Timestamp: "2024-01-01T09:00:00", P: 107, R1: 124, S1: 94, R2: 137, S2: 77, R3: 154, S3: 64}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Pivots = %+v, want %+v", got, want)
+	}
+}
+
+func TestComputePivotsWarnsOutsideDayWeek(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, name := range []string{"day", "minute60"} {
+		tf := mustTimeframe(t, name)
+		seed(t, c, tf, t0)
+		out.Reset()
+		points, err := c.ComputePivots(tf)
+		if err != nil || len(points) != 1 {
+			t.Fatalf("%s: %d개, %v, want 1개 (제한하지 않음)", name, len(points), err)
+		}
+		if warned := strings.Contains(out.String(), "보통 day/week"); warned != (name != "day") {
+			t.Errorf("%s 경고 출력 = %v", name, warned)
+		}
+	}
+}
+
+func TestADX(t *testing.T) {
+	hlc := [][3]float64{
+		{30.2, 29.4, 29.9}, {30.3, 29.6, 30.2}, {30.5, 29.8, 30.0}, {30.1, 29.2, 29.5}, {29.8, 28.9, 29.0},
+		{29.6, 28.7, 29.4}, {30.4, 29.3, 30.2}, {30.9, 30.0, 30.7}, {31.2, 30.5, 31.0}, {31.0, 30.2, 30.4},
+	}
+	candles := make([]Candle, len(hlc))
+	for i, v := range hlc {
+		candles[i] = Candle{CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i), HighPrice: v[0], LowPrice: v[1], TradePrice: v[2]}
+	}
+	// 참조값: 별도 Wilder 구현으로 계산 (period 3, 첫 값은 인덱스 2*period-1 = 5)
+	want := []ADXPoint{
+		{Timestamp: candles[5].CandleDateTimeKST, PlusDI: 5.286344, MinusDI: 26.431718, ADX: 51.851852},
+		{Timestamp: candles[6].CandleDateTimeKST, PlusDI: 31.95739, MinusDI: 15.978695, ADX: 45.679012},
+		{Timestamp: candles[7].CandleDateTimeKST, PlusDI: 39.66831, MinusDI: 10.757508, ADX: 49.563786},
+		{Timestamp: candles[8].CandleDateTimeKST, PlusDI: 40.548434, MinusDI: 7.788415, ADX: 55.633998},
+		{Timestamp: candles[9].CandleDateTimeKST, PlusDI: 27.525058, MinusDI: 17.331204, ADX: 44.664531},
+	}
+	got := ADX(candles, 3)
+	if len(got) != len(want) {
+		t.Fatalf("%d개, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Timestamp != w.Timestamp || !closeTo(g.PlusDI, w.PlusDI, 1e-5) || !closeTo(g.MinusDI, w.MinusDI, 1e-5) || !closeTo(g.ADX, w.ADX, 1e-5) {
+			t.Errorf("[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+
+	// 평탄 구간은 TR 이 0 이라 DI, ADX 모두 0
+	for _, p := range ADX(typicalCandles(5, 5, 5, 5), 2) {
+		if p.PlusDI != 0 || p.MinusDI != 0 || p.ADX != 0 {
+			t.Errorf("평탄 구간 = %+v, want 0", p)
+		}
+	}
+	if ADX(candles[:5], 3) != nil {
+		t.Error("캔들이 2*period 보다 적으면 nil")
+	}
+}
+
+func TestSMAPriceField(t *testing.T) {
+	hlc := [][3]float64{{12, 6, 9}, {15, 9, 9}, {18, 9, 12}}
+	candles := make([]Candle, len(hlc))
+	for i, v := range hlc {
+		candles[i] = Candle{CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i), OpeningPrice: v[2] - 1, HighPrice: v[0], LowPrice: v[1], TradePrice: v[2]}
+	}
+	// 손으로 계산: 종가 9 9 12, 대표가 9 11 13, 시가 8 8 11
+	assertPoints(t, SMA(candles, 2, PriceClose), []IndicatorPoint{
+		{Timestamp: candles[1].CandleDateTimeKST, Value: 9},
+		{Timestamp: candles[2].CandleDateTimeKST, Value: 10.5},
+	}, 1e-9)
+	assertPoints(t, SMA(candles, 2, PriceTypical), []IndicatorPoint{
+		{Timestamp: candles[1].CandleDateTimeKST, Value: 10},
+		{Timestamp: candles[2].CandleDateTimeKST, Value: 12},
+	}, 1e-9)
+	assertPoints(t, SMA(candles, 2, PriceOpen), []IndicatorPoint{
+		{Timestamp: candles[1].CandleDateTimeKST, Value: 8},
+		{Timestamp: candles[2].CandleDateTimeKST, Value: 9.5},
+	}, 1e-9)
+
+	// 기본값(0)은 종가
+	var field PriceField
+	if field != PriceClose {
+		t.Errorf("PriceField 기본값 = %v, want close", field)
+	}
+	if f, err := parsePriceField("typical"); err != nil || f != PriceTypical {
+		t.Errorf("parsePriceField(typical) = %v, %v", f, err)
+	}
+	if _, err := parsePriceField("vwap"); err == nil {
+		t.Error("알 수 없는 필드에 오류 없음")
+	}
+}
+
+func TestZScoreSpike(t *testing.T) {
+	candles := typicalCandles(100, 100, 100, 100, 100, 100, 100, 100, 200, 100, 100)
+	points := ZScore(candles, 5)
+	if len(points) != len(candles)-4 || points[0].Timestamp != candles[4].CandleDateTimeKST {
+		t.Fatalf("points = %v, want window 번째 캔들부터 %d개", points, len(candles)-4)
+	}
+
+	// 평탄 구간은 표준편차 0 이라 0, 급등 캔들은 창 [100 100 100 100 200] 평균 120, 표본 표준편차 √2000 → 4/√5
+	peak := points[0]
+	for _, p := range points {
+		if p.Value > peak.Value {
+			peak = p
+		}
+	}
+	if peak.Timestamp != candles[8].CandleDateTimeKST || !closeTo(peak.Value, 4/math.Sqrt(5), 1e-9) {
+		t.Errorf("최대 z-score = %+v, want %s 에서 %v", peak, candles[8].CandleDateTimeKST, 4/math.Sqrt(5))
+	}
+	for _, p := range points[:4] {
+		if p.Value != 0 {
+			t.Errorf("평탄 구간 z-score = %+v, want 0", p)
+		}
+	}
+	if v := points[5].Value; !closeTo(v, -1/math.Sqrt(5), 1e-9) {
+		t.Errorf("급등 다음 캔들 z-score = %v, want %v", v, -1/math.Sqrt(5))
+	}
+}
+
+func TestMFI(t *testing.T) {
+	hlcv := [][4]float64{{10, 8, 9, 100}, {11, 9, 10, 120}, {12, 10, 11, 150}, {11, 9, 9.5, 130}, {10, 8, 8.5, 170}, {11, 9, 10.5, 110}, {12, 10, 11.5, 140}, {13, 11, 12, 90}}
+	candles := make([]Candle, len(hlcv))
+	for i, v := range hlcv {
+		candles[i] = Candle{CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i), HighPrice: v[0], LowPrice: v[1], TradePrice: v[2], CandleAccTradeVolume: v[3]}
+	}
+	// 참조값: 별도 구현으로 계산 (period 3, 첫 값은 인덱스 period), 마지막 창은 양의 흐름만 있어 100
+	assertPoints(t, MFI(candles, 3), []IndicatorPoint{
+		{Timestamp: candles[3].CandleDateTimeKST, Value: 69.035123},
+		{Timestamp: candles[4].CandleDateTimeKST, Value: 37.24605},
+		{Timestamp: candles[5].CandleDateTimeKST, Value: 28.687473},
+		{Timestamp: candles[6].CandleDateTimeKST, Value: 64.103586},
+		{Timestamp: candles[7].CandleDateTimeKST, Value: 100},
+	}, 1e-5)
+
+	// 흐름이 없는 평탄 구간은 50
+	for _, p := range MFI(typicalCandles(5, 5, 5), 2) {
+		if p.Value != 50 {
+			t.Errorf("평탄 구간 MFI = %v, want 50", p.Value)
+		}
+	}
+	if MFI(candles[:3], 3) != nil {
+		t.Error("캔들이 period 이하면 nil")
+	}
+}
+
+func TestDonchian(t *testing.T) {
+	hl := [][2]float64{{10, 8}, {12, 9}, {11, 7}, {11, 9}, {15, 10}, {13, 11}, {12, 11}}
+	candles := make([]Candle, len(hl))
+	for i, v := range hl {
+		candles[i] = Candle{CandleDateTimeKST: fmt.Sprintf("2024-01-01T09:%02d:00", i), HighPrice: v[0], LowPrice: v[1], TradePrice: (v[0] + v[1]) / 2}
+	}
+	// 새 고점(15)은 그 캔들에서 바로 반영되고, 창에서 빠지면 이전 고/저점도 빠짐
+	want := []DonchianPoint{
+		{Timestamp: candles[2].CandleDateTimeKST, Upper: 12, Lower: 7, Middle: 9.5},
+		{Timestamp: candles[3].CandleDateTimeKST, Upper: 12, Lower: 7, Middle: 9.5},
+		{Timestamp: candles[4].CandleDateTimeKST, Upper: 15, Lower: 7, Middle: 11},
+		{Timestamp: candles[5].CandleDateTimeKST, Upper: 15, Lower: 9, Middle: 12},
+		{Timestamp: candles[6].CandleDateTimeKST, Upper: 15, Lower: 10, Middle: 12.5},
+	}
+	got := Donchian(candles, 3)
+	if len(got) != len(want) {
+		t.Fatalf("%d개, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if Donchian(candles[:2], 3) != nil {
+		t.Error("캔들이 period 보다 적으면 nil")
+	}
+}
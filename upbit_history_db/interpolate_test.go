@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterpolationGapCap(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	c.MaxInterpolationGap = 12
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	minute := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+	seed(t, c, tf, minute(0), minute(1), minute(4), minute(30))
+
+	n, err := c.interpolateMissingData(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("보간 %d개, want 2 (작은 구간만)", n)
+	}
+	gaps, err := c.FindGaps(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Gap{Start: minute(5).Format(timestampLayout), End: minute(29).Format(timestampLayout), Missing: 25}
+	if len(gaps) != 1 || gaps[0] != want {
+		t.Errorf("gaps = %+v, want [%+v]", gaps, want)
+	}
+}
+
+func TestInterpolationSkipsProvisionalAnchor(t *testing.T) {
+	for _, tc := range []struct {
+		useProvisional bool
+		want           int
+	}{
+		{useProvisional: false, want: 2},
+		{useProvisional: true, want: 8},
+	} {
+		c := openTestDB(t, "KRW-BTC")
+		c.InterpolateProvisional = tc.useProvisional
+		now := time.Date(2024, 1, 1, 0, 10, 30, 0, time.UTC) // KST 09:10:30
+		c.now = func() time.Time { return now }
+		tf := mustTimeframe(t, "minute1")
+		kst := now.Add(9 * time.Hour).Truncate(time.Minute)
+		// 09:10 캔들은 아직 진행 중
+		seed(t, c, tf, kst.Add(-10*time.Minute), kst.Add(-7*time.Minute), kst)
+
+		n, err := c.interpolateMissingData(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tc.want {
+			t.Errorf("InterpolateProvisional=%v: 보간 %d개, want %d", tc.useProvisional, n, tc.want)
+		}
+		if !tc.useProvisional {
+			if got := countRows(t, c, tf, "timestamp > ?", kst.Add(-7*time.Minute).Format(timestampLayout)); got != 1 {
+				t.Errorf("진행 중 캔들 앞 구간에 %d개 (진행 중 캔들만 있어야 함)", got)
+			}
+		}
+	}
+}
+
+func TestInterpolationAlignsToKSTDayStart(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	day, month := mustTimeframe(t, "day"), mustTimeframe(t, "month")
+	// 업비트 일/월 캔들은 KST 09:00 시작 - 월 경계와 윤년 2월을 넘는 구간
+	at := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 0, 0, 0, time.UTC) }
+	seed(t, c, day, at(2024, 2, 27), at(2024, 3, 2))
+	seed(t, c, month, at(2024, 1, 1), at(2024, 4, 1))
+
+	for _, tc := range []struct {
+		tf   Timeframe
+		want []time.Time
+	}{
+		{day, []time.Time{at(2024, 2, 27), at(2024, 2, 28), at(2024, 2, 29), at(2024, 3, 1), at(2024, 3, 2)}},
+		{month, []time.Time{at(2024, 1, 1), at(2024, 2, 1), at(2024, 3, 1), at(2024, 4, 1)}},
+	} {
+		if _, err := c.interpolateMissingData(tc.tf); err != nil {
+			t.Fatal(err)
+		}
+		candles, err := c.GetCandles(tc.tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candles) != len(tc.want) {
+			t.Fatalf("%s: %d개, want %d", tc.tf.Name, len(candles), len(tc.want))
+		}
+		for i, candle := range candles {
+			if want := tc.want[i].Format(timestampLayout); candle.CandleDateTimeKST != want {
+				t.Errorf("%s [%d] = %s, want KST %s", tc.tf.Name, i, candle.CandleDateTimeKST, want)
+			}
+			if want := tc.want[i].Add(-9 * time.Hour).Format(timestampLayout); candle.CandleDateTimeUTC != want {
+				t.Errorf("%s [%d] UTC = %s, want %s", tc.tf.Name, i, candle.CandleDateTimeUTC, want)
+			}
+		}
+	}
+}
+
+func TestInterpolationMinCandles(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	c.MinInterpolationCandles = 5
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	times := kstMinutes(t0, 6)
+	// 실제 캔들 4개 (마지막 앞 1분 빈 구간)
+	seed(t, c, tf, times[0], times[1], times[2], times[4])
+
+	if n, err := c.interpolateMissingData(tf); err != nil || n != 0 {
+		t.Errorf("최소 개수 미만 보간 %d개 (err %v), want 0", n, err)
+	}
+	if !strings.Contains(out.String(), "실제 캔들 4개로 최소 5개 미만 - 보간 생략") {
+		t.Errorf("보간 생략 로그 없음:\n%s", out.String())
+	}
+
+	seed(t, c, tf, times[5])
+	if n, err := c.interpolateMissingData(tf); err != nil || n != 1 {
+		t.Errorf("최소 개수 도달 후 보간 %d개 (err %v), want 1", n, err)
+	}
+	if n := countRows(t, c, tf, "is_interpolated = 1 AND timestamp = ?", times[3].Format(timestampLayout)); n != 1 {
+		t.Error("빈 구간이 보간되지 않음")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// requestJitter - 요청 전 무작위 지연 (0 ~ max, 여러 goroutine 이 같은 간격으로 몰려 요청하지 않도록)
+type requestJitter struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+	max time.Duration
+}
+
+func newRequestJitter(max time.Duration, seed int64) *requestJitter {
+	return &requestJitter{rng: rand.New(rand.NewSource(seed)), max: max}
+}
+
+// next - 다음 요청 전 지연 (0 이상 max 이하)
+func (j *requestJitter) next() time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return time.Duration(j.rng.Int63n(int64(j.max) + 1))
+}
+
+// jitterDelay - RequestJitter 범위의 다음 지연 (0 이면 지연 없음, 설정은 첫 요청 시 읽음)
+func (c *Collector) jitterDelay() time.Duration {
+	c.jitterOnce.Do(func() {
+		if c.RequestJitter > 0 {
+			seed := c.JitterSeed
+			if seed == 0 {
+				seed = time.Now().UnixNano()
+			}
+			c.jitter = newRequestJitter(c.RequestJitter, seed)
+		}
+	})
+	if c.jitter == nil {
+		return 0
+	}
+	return c.jitter.next()
+}
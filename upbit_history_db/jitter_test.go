@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestJitterBounds(t *testing.T) {
+	const limit = 200 * time.Millisecond
+	j := newRequestJitter(limit, 42)
+	lo, hi := limit, time.Duration(0)
+	for i := 0; i < 10000; i++ {
+		d := j.next()
+		if d < 0 || d > limit {
+			t.Fatalf("[%d] 지연 %v, want 0 ~ %v", i, d, limit)
+		}
+		lo, hi = min(lo, d), max(hi, d)
+	}
+	// 범위 전체에 고르게 퍼짐 (한쪽에 몰리지 않음)
+	if lo > 5*time.Millisecond || hi < limit-5*time.Millisecond {
+		t.Errorf("지연 범위 %v ~ %v, want 0 ~ %v 근처까지", lo, hi, limit)
+	}
+}
+
+func TestJitterDelaySeed(t *testing.T) {
+	sequence := func(seed int64) []time.Duration {
+		c := openTestDB(t, "KRW-BTC")
+		c.RequestJitter = 200 * time.Millisecond
+		c.JitterSeed = seed
+		out := make([]time.Duration, 20)
+		for i := range out {
+			out[i] = c.jitterDelay()
+		}
+		return out
+	}
+	a, b, other := sequence(7), sequence(7), sequence(8)
+	same := true
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("같은 시드인데 [%d] %v != %v", i, a[i], b[i])
+		}
+		same = same && a[i] == other[i]
+	}
+	if same {
+		t.Error("다른 시드인데 지연 순서가 같음")
+	}
+
+	if d := openTestDB(t, "KRW-BTC").jitterDelay(); d != 0 {
+		t.Errorf("RequestJitter 0 인데 지연 %v", d)
+	}
+}
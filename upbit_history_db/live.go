@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+)
+
+// Notifier - 전략 신호(Buy/Sell)를 전달받는 곳
+type Notifier interface {
+	Notify(sig Signal, candle Candle) error
+}
+
+// LogNotifier - 신호를 로그로 출력 (Logger 가 nil 이면 기본 log)
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+func (n LogNotifier) Notify(sig Signal, candle Candle) error {
+	logf := log.Printf
+	if n.Logger != nil {
+		logf = n.Logger.Printf
+	}
+	logf("[%s] %s 신호: %s 종가 %s", candle.Market, sig, candle.CandleDateTimeKST, formatFloat(candle.TradePrice))
+	return nil
+}
+
+// WebhookNotifier - 신호를 JSON 으로 POST (Client 가 nil 이면 10초 timeout 기본 클라이언트)
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// webhookPayload - WebhookNotifier 가 보내는 본문
+type webhookPayload struct {
+	Signal    string  `json:"signal"`
+	Market    string  `json:"market"`
+	Timestamp string  `json:"timestamp"` // 캔들 시작 시각 (KST)
+	Price     float64 `json:"price"`
+}
+
+func (n WebhookNotifier) Notify(sig Signal, candle Candle) error {
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return postJSON(client, n.URL, webhookPayload{
+		Signal:    sig.String(),
+		Market:    candle.Market,
+		Timestamp: candle.CandleDateTimeKST,
+		Price:     candle.TradePrice,
+	})
+}
+
+// postJSON - v 를 JSON 으로 url 에 POST (2xx 가 아니면 HTTPStatusError)
+func postJSON(client *http.Client, url string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// 실시간 모드 설정
+const (
+	liveHistory      = 1000             // 전략에 넘기는 최근 캔들 수
+	livePollInterval = 10 * time.Second // 새 캔들 확인 주기
+)
+
+// StreamLive - 마감된 새 캔들을 주기적으로 받아 저장하고 전략을 평가해 Buy/Sell 을 notifier 로 전달
+//
+// ctx 가 취소될 때까지 실행되며 ctx.Err() 를 반환한다. API/저장/알림 오류는 로그만 남기고 계속한다.
+func (c *Collector) StreamLive(ctx context.Context, tf Timeframe, strategy Strategy, notifier Notifier) error {
+	history, err := c.RecentCandles(tf, liveHistory)
+	if err != nil {
+		return err
+	}
+	if n := len(history); n > 0 && c.isProvisional(tf, history[n-1].CandleDateTimeKST) {
+		history = history[:n-1]
+	}
+
+	fmt.Fprintf(c.Output, "[%s] %s 실시간 모드 시작 (이전 캔들 %d개)\n", tf.Name, c.mark(markLaunch), len(history))
+	for {
+		candles, err := c.fetchCandles(ctx, tf, "", nil)
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 실패: %v\n", tf.Name, c.mark(markFail), err)
+		} else {
+			history = c.processClosed(tf, strategy, notifier, history, candles)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(c.Output, "[%s] %s 실시간 모드 종료\n", tf.Name, c.mark(markOK))
+			return ctx.Err()
+		case <-time.After(livePollInterval):
+		}
+	}
+}
+
+// processClosed - API 응답(최신순) 중 history 이후 마감된 캔들을 저장하고 하나씩 전략 평가
+func (c *Collector) processClosed(tf Timeframe, strategy Strategy, notifier Notifier, history, candles []Candle) []Candle {
+	last := ""
+	if len(history) > 0 {
+		last = history[len(history)-1].CandleDateTimeKST
+	}
+
+	var closed []Candle
+	for _, candle := range candles {
+		if candle.CandleDateTimeKST > last && !c.isProvisional(tf, candle.CandleDateTimeKST) {
+			closed = append(closed, candle)
+		}
+	}
+	if len(closed) == 0 {
+		return history
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].CandleDateTimeKST < closed[j].CandleDateTimeKST })
+
+	if _, err := c.ClearFillToNow(tf); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 채움 캔들 삭제 실패: %v\n", tf.Name, c.mark(markWarn), err)
+	}
+	if _, _, err := c.saveCandles(tf, closed); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 저장 실패: %v\n", tf.Name, c.mark(markFail), err)
+	}
+
+	for _, candle := range closed {
+		history = c.evaluateClosed(tf, strategy, notifier, history, candle)
+	}
+	return history
+}
+
+// evaluateClosed - 마감된 candle 을 history 에 붙이고(최근 liveHistory 개 유지) 전략 신호를 notifier 로 전달 (실시간/재생 공용)
+func (c *Collector) evaluateClosed(tf Timeframe, strategy Strategy, notifier Notifier, history []Candle, candle Candle) []Candle {
+	history = append(history, candle)
+	if len(history) > liveHistory {
+		history = history[len(history)-liveHistory:]
+	}
+
+	sig := strategy.Evaluate(history)
+	if sig == Hold {
+		return history
+	}
+	fmt.Fprintf(c.Output, "[%s] %s %s 신호 (%s)\n", tf.Name, c.mark(markStats), sig, candle.CandleDateTimeKST)
+	if err := notifier.Notify(sig, candle); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 알림 실패: %v\n", tf.Name, c.mark(markWarn), err)
+	}
+	return history
+}
+
+func runLive(args []string) error {
+	fs := flag.NewFlagSet("live", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "minute5", "전략을 평가할 시간단위")
+	short := fs.Int("short", 5, "단기 SMA 기간")
+	long := fs.Int("long", 20, "장기 SMA 기간")
+	webhook := fs.String("webhook", "", "신호를 POST 할 URL (비우면 로그만 출력)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if *short < 1 || *long <= *short {
+		return fmt.Errorf("--long 은 --short 보다 커야 합니다")
+	}
+
+	var notifier Notifier = LogNotifier{}
+	if *webhook != "" {
+		notifier = WebhookNotifier{URL: *webhook}
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = collector.StreamLive(ctx, tf, SMACrossover{Short: *short, Long: *long}, notifier)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureNotifier - 받은 신호와 캔들을 기록하는 Notifier (err 가 있으면 기록 후 반환)
+type captureNotifier struct {
+	signals    []Signal
+	timestamps []string
+	err        error
+}
+
+func (n *captureNotifier) Notify(sig Signal, candle Candle) error {
+	n.signals = append(n.signals, sig)
+	n.timestamps = append(n.timestamps, candle.CandleDateTimeKST)
+	return n.err
+}
+
+// liveResponse - KST start 부터 1분 간격 prices 캔들을 API 응답처럼 최신순으로
+func liveResponse(start time.Time, prices ...float64) []Candle {
+	candles := make([]Candle, len(prices))
+	for i, p := range prices {
+		candles[len(prices)-1-i] = testCandle(start.Add(time.Duration(i)*time.Minute), p)
+	}
+	return candles
+}
+
+func TestProcessClosedNotifiesCrossover(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	// 마지막 캔들(09:10)은 아직 진행 중
+	c.now = func() time.Time { return start.Add(-9*time.Hour + 10*time.Minute + 30*time.Second) }
+	// SMA(2)/SMA(4): 09:05 에 위로 교차, 09:08 에 아래로 교차
+	api := liveResponse(start, 10, 10, 10, 10, 10, 12, 14, 14, 8, 6, 100)
+
+	notifier := &captureNotifier{}
+	strategy := SMACrossover{Short: 2, Long: 4}
+	history := c.processClosed(tf, strategy, notifier, nil, api)
+	if len(history) != 10 {
+		t.Errorf("history %d개, want 마감된 10개", len(history))
+	}
+	want := []Signal{Buy, Sell}
+	wantAt := []string{
+		start.Add(5 * time.Minute).Format(timestampLayout),
+		start.Add(8 * time.Minute).Format(timestampLayout),
+	}
+	if len(notifier.signals) != len(want) {
+		t.Fatalf("신호 = %v, want %v", notifier.signals, want)
+	}
+	for i := range want {
+		if notifier.signals[i] != want[i] || notifier.timestamps[i] != wantAt[i] {
+			t.Errorf("[%d] %s %s, want %s %s", i, notifier.signals[i], notifier.timestamps[i], want[i], wantAt[i])
+		}
+	}
+	if n := countRows(t, c, tf, ""); n != 10 {
+		t.Errorf("저장된 캔들 %d개, want 10", n)
+	}
+
+	// 같은 응답을 다시 받으면 이미 평가한 캔들은 건너뜀
+	again := c.processClosed(tf, strategy, notifier, history, api)
+	if len(again) != len(history) || len(notifier.signals) != len(want) {
+		t.Errorf("다시 처리 후 history %d개, 신호 %d개", len(again), len(notifier.signals))
+	}
+}
+
+func TestProcessClosedNotifierErrorIsLogged(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	tf := mustTimeframe(t, "minute1")
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return start.Add(time.Hour) }
+
+	notifier := &captureNotifier{err: errors.New("webhook down")}
+	history := c.processClosed(tf, SMACrossover{Short: 2, Long: 4}, notifier, nil,
+		liveResponse(start, 10, 10, 10, 10, 10, 12, 14, 14, 8, 6))
+	if len(history) != 10 || len(notifier.signals) != 2 {
+		t.Errorf("알림 실패 후 history %d개, 신호 %d개, want 10, 2 (계속 진행)", len(history), len(notifier.signals))
+	}
+	if !strings.Contains(out.String(), "알림 실패: webhook down") {
+		t.Errorf("알림 실패 로그 없음: %q", out.String())
+	}
+}
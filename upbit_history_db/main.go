@@ -1,15 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	sqlite3 "github.com/mattn/go-sqlite3"
 )
 
 // RateLimiter - 업비트 API rate limit 준수 (초당 10회)
@@ -25,6 +37,11 @@ func NewRateLimiter(requestsPerSecond int) *RateLimiter {
 	}
 }
 
+// PerSecond - 설정된 초당 요청 수
+func (rl *RateLimiter) PerSecond() int {
+	return int(time.Second / rl.minGap)
+}
+
 func (rl *RateLimiter) Wait() {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
@@ -45,6 +62,7 @@ type Timeframe struct {
 	Name    string
 	Minutes int
 	APIPath string
+	Params  []string // 캔들 API 가 추가로 받는 파라미터 이름 (예: days 의 convertingPriceUnit)
 }
 
 // Candle 구조체
@@ -58,6 +76,8 @@ type Candle struct {
 	TradePrice           float64 `json:"trade_price"`
 	CandleAccTradePrice  float64 `json:"candle_acc_trade_price"`
 	CandleAccTradeVolume float64 `json:"candle_acc_trade_volume"`
+	IsInterpolated       bool    `json:"is_interpolated,omitempty"` // DB 조회 시에만 채워짐
+	IsProvisional        bool    `json:"is_provisional,omitempty"`  // 저장 당시 진행 중이던 캔들 (DB 조회 시에만 채워짐)
 }
 
 // Collector 구조체
@@ -67,6 +87,188 @@ type Collector struct {
 	rateLimiter *RateLimiter
 	market      string
 	apiURL      string
+	now         func() time.Time
+	cache       *candleCache
+	queries     atomic.Int64 // queryCandles 로 DB 를 읽은 횟수 (캐시 적중 확인용)
+	dbPath      string
+	shards      *yearShards    // nil 이면 단일 파일
+	quiet       bool           // 연결/종료 안내 출력 생략 (JSON 출력 등)
+	indicatorMu sync.Mutex     // indicators 테이블 쓰기 직렬화
+	returnsMu   sync.Mutex     // candle_returns 테이블 쓰기 직렬화
+	scheduler   *Scheduler     // CollectAllMarkets 실행 중에만 설정
+	epoch       bool           // timestamp_ms 컬럼 사용 (EnableEpochTimestamps)
+	budget      *requestBudget // CollectAll 실행 중에만 설정 (MaxRequests > 0)
+	marketsURL  string
+	markets     marketList // ListMarkets 캐시
+	closeOnce   sync.Once  // Close 는 신호 처리와 defer 에서 함께 불릴 수 있음
+	breakerOnce sync.Once
+	breaker     *circuitBreaker // BreakerThreshold > 0 일 때 첫 요청에서 생성
+	retryOnce   sync.Once
+	retries     *retryBudget // RetryBudget > 0 일 때 첫 재시도에서 생성
+	jitterOnce  sync.Once
+	jitter      *requestJitter // RequestJitter > 0 일 때 첫 요청에서 생성
+	closeErr    error
+
+	// Output - 안내/통계 메시지 출력 대상 (기본 os.Stdout, 라이브러리로 쓸 때 io.Discard 로 끄기)
+	//
+	// 시간단위를 병렬로 처리할 때는 lockedWriter 로 감싸 쓰기를 직렬화하므로 bytes.Buffer 처럼 동시 사용에
+	// 안전하지 않은 writer 도 넘길 수 있다.
+	Output io.Writer
+	// PlainOutput - 이모지 대신 [INFO]/[WARN]/[OK] 같은 ASCII 접두어로 출력
+	PlainOutput bool
+
+	// MaxPages - 시간단위별 최대 요청 페이지 수 (0 = 제한 없음)
+	MaxPages int
+	// MaxConcurrency - 동시에 수집하는 시간단위 수 (0 = 전체 동시)
+	MaxConcurrency int
+
+	// MaintenanceBackoff - 재시도 후에도 503(업비트 점검)이면 이만큼 쉬고 같은 페이지부터 재개 (0 = 점검 대기 없이 실패)
+	MaintenanceBackoff time.Duration
+
+	// StallTimeout - 시간단위 수집이 이 시간 동안 진행이 없으면 요청을 취소하고 마지막 저장 위치부터 재시작 (0 = 감시 안 함)
+	StallTimeout time.Duration
+	// PerTimeframeTimeout - 한 시간단위 수집에 쓸 수 있는 최대 시간 (넘으면 체크포인트 저장 후 그 시간단위만 중단, 0 = 제한 없음)
+	PerTimeframeTimeout time.Duration
+
+	// MaxRequests - CollectAll 1회 실행에서 모든 시간단위가 합쳐 보낼 수 있는 최대 API 요청 수 (재시도 포함, 0 = 제한 없음)
+	//
+	// 예산을 다 쓰면 각 시간단위는 받은 페이지까지 저장하고 멈추며, 멈춘 위치를 체크포인트로 남겨
+	// 다음 실행에서 이어서 수집한다.
+	MaxRequests int
+
+	// BreakerThreshold - 캔들 요청(재시도 포함)이 연속 이만큼 실패하면 회로 차단기를 열어 BreakerCooldown 동안
+	// 요청 없이 실패 처리하고, 그 뒤 요청 1개로 복구를 확인 (0 = 사용 안 함, 첫 요청 후에는 바꿔도 반영 안 됨)
+	BreakerThreshold int
+	// BreakerCooldown - 회로 차단기가 열린 뒤 복구 확인까지 기다리는 시간
+	BreakerCooldown time.Duration
+
+	// RetryBudget - 모든 시간단위가 합쳐 분당 할 수 있는 최대 재시도 수 (넘으면 재시도 없이 실패하고
+	// 체크포인트부터 다음 실행에서 이어서 수집, 0 = 제한 없음, 첫 재시도 후에는 바꿔도 반영 안 됨)
+	RetryBudget int
+
+	// RequestJitter - 캔들 요청마다 rate limiter 대기 전에 더하는 무작위 지연의 최대값 (0 ~ RequestJitter, 0 = 사용 안 함)
+	//
+	// 여러 시간단위 goroutine 이 같은 간격으로 동시에 요청해 순간 요청이 몰리는 것을 흩뜨린다.
+	RequestJitter time.Duration
+	// JitterSeed - RequestJitter 난수 시드 (같은 시드면 같은 지연 순서, 0 = 실행마다 다름)
+	JitterSeed int64
+
+	// FailedTimeframeRetries - CollectAll 에서 오류로 끝난 시간단위를 전체 수집 후 다시 수집하는 최대 횟수 (0 = 다시 시도 안 함)
+	FailedTimeframeRetries int
+
+	// StopBefore - 이 시각(KST) 이전 캔들은 수집/저장하지 않음
+	StopBefore time.Time
+	// StopBeforeTimeframes - 시간단위 이름별 StopBefore (예: minute1 은 최근 2년만, 없는 시간단위는 StopBefore 사용)
+	StopBeforeTimeframes map[string]time.Time
+	// MaxInterpolationGap - 이 개수(간격 수)보다 많이 비어 있는 구간은 보간하지 않음 (0 = 제한 없음)
+	MaxInterpolationGap int
+	// MinInterpolationCandles - 실제 캔들이 이 개수보다 적으면 보간 전체를 건너뜀 (새 마켓처럼 짧은 시리즈 보호, 0 = 제한 없음)
+	MinInterpolationCandles int
+	// InterpolateProvisional - 진행 중인 마지막 캔들도 보간 기준점으로 사용 (기본: 마감된 캔들 사이만 보간)
+	InterpolateProvisional bool
+	// Interpolation - 보간 캔들 값 계산 방식 (기본: 전 컬럼 선형보간)
+	Interpolation InterpolationStrategy
+	// IncludeProvisional - 지표 계산에 진행 중인 마지막 캔들 포함 (기본: 마감된 캔들만)
+	IncludeProvisional bool
+
+	// KeepProvisional - TopUp 이 저장 당시 진행 중이던 캔들(is_provisional)을 마감 값으로 덮어쓰지 않음 (기본: 덮어씀)
+	KeepProvisional bool
+	// ExcludeInterpolated - GetCandles/내보내기에서 보간 캔들 제외 (실제 캔들만, 시간 간격이 빌 수 있음)
+	ExcludeInterpolated bool
+	// Location - 내보내기/DisplayCandles 의 timestamp 를 이 시간대 시각(RFC3339, 오프셋 포함)으로 변환
+	// (nil = 저장된 KST 문자열 그대로, 저장 데이터와 GetCandles 는 항상 KST)
+	Location *time.Location
+
+	// PruneAfterRetention - ApplyRetention 이 상위 시간단위로 합친 뒤 원본 캔들 삭제 (기본: 보관)
+	PruneAfterRetention bool
+
+	// Conflict - 이미 있는 timestamp 저장 시 동작 (기본: 기존 캔들 유지)
+	Conflict ConflictMode
+
+	// BulkLoadRebuildIndexes - BulkLoad 중 보조 인덱스를 지웠다가 적재 후 다시 생성 (대량 적재 시 더 빠름)
+	BulkLoadRebuildIndexes bool
+
+	// ImportAlignment - ImportCSV 에서 시간단위 경계에 맞지 않는 timestamp 처리 (기본: 거부)
+	ImportAlignment ImportAlignment
+
+	// Rounding - 저장 전 가격/거래량 반올림 정책 (nil = 원본 그대로, 보간 캔들은 반올림하지 않음)
+	Rounding *RoundingPolicy
+
+	// OnSave - 저장 커밋 성공 후 새로 삽입된 캔들로 호출 (Kafka/Redis 전달 등 확장용)
+	OnSave func([]Candle, Timeframe) error
+	// AbortOnHookError - OnSave 오류 시 해당 시간단위 수집 중단 (기본: 로그만 남기고 계속)
+	AbortOnHookError bool
+
+	// GridObjective - GridSearch 결과 정렬 기준 (기본: Sharpe 비율)
+	GridObjective GridObjective
+
+	// QualityWeights - QualityScore 구성 요소 가중치 (기본 DefaultQualityWeights)
+	QualityWeights QualityWeights
+
+	// StoreReturns - CollectAll 에서 시간단위별 수집/보간 후 ComputeReturns 로 저장 수익률 갱신
+	StoreReturns bool
+
+	// SummaryPath - CollectAll 후 실행 요약(RunSummary) JSON 을 기록할 경로 (빈 값이면 기록 안 함)
+	SummaryPath string
+	// CompletionWebhook - CollectAll 후 실행 요약을 POST 할 URL (빈 값이면 보내지 않음, 실패해도 결과에 영향 없음)
+	CompletionWebhook string
+
+	// Parser - 캔들 API 응답을 Candle 로 변환 (기본 UpbitParser, 다른 거래소 응답 형식용 확장 지점)
+	Parser CandleParser
+
+	// Progress - 수집 진행 이벤트를 받을 채널 (nil 이면 전송 안 함, 닫는 것은 호출자 책임)
+	Progress chan<- ProgressEvent
+}
+
+// 기본 마켓 - 테이블 이름에 기존 bitcoin_ 접두어를 유지
+const defaultMarket = "KRW-BTC"
+
+var marketPattern = regexp.MustCompile(`^[A-Z]+-[A-Z0-9]+$`)
+
+// 저장 timestamp 형식 (KST, 타임존 표기 없음)
+const timestampLayout = "2006-01-02T15:04:05"
+
+// seoul - 저장 timestamp 의 시간대 (tzdata 가 없는 환경에서는 고정 UTC+9)
+var seoul = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Seoul"); err == nil {
+		return loc
+	}
+	return time.FixedZone("KST", 9*60*60)
+}()
+
+// parseKST - 저장 timestamp 를 Asia/Seoul 시각으로 해석
+func parseKST(timestamp string) (time.Time, error) {
+	return time.ParseInLocation(timestampLayout, timestamp, seoul)
+}
+
+// marker - 메시지 접두어 (기본 이모지 / PlainOutput 시 ASCII)
+type marker struct {
+	emoji string
+	plain string
+}
+
+var (
+	markOK     = marker{"✓", "[OK]"}
+	markDone   = marker{"✅", "[OK]"}
+	markFail   = marker{"✗", "[ERROR]"}
+	markWarn   = marker{"⚠️ ", "[WARN]"}
+	markStart  = marker{"📊", "[INFO]"}
+	markLaunch = marker{"🚀", "[INFO]"}
+	markWork   = marker{"🔧", "[INFO]"}
+	markStats  = marker{"📈", "[INFO]"}
+	markPause  = marker{"⏸️ ", "[WAIT]"}
+)
+
+func (c *Collector) mark(m marker) string {
+	return m.text(c.PlainOutput)
+}
+
+// text - plain 이면 ASCII 접두어, 아니면 이모지 (Collector 없이 출력하는 명령용)
+func (m marker) text(plain bool) string {
+	if plain {
+		return m.plain
+	}
+	return m.emoji
 }
 
 var timeframes = []Timeframe{
@@ -78,348 +280,1413 @@ var timeframes = []Timeframe{
 	{Name: "minute30", Minutes: 30, APIPath: "minutes/30"},
 	{Name: "minute60", Minutes: 60, APIPath: "minutes/60"},
 	{Name: "minute240", Minutes: 240, APIPath: "minutes/240"},
-	{Name: "day", Minutes: 1440, APIPath: "days"},
+	{Name: "day", Minutes: 1440, APIPath: "days", Params: []string{"convertingPriceUnit"}},
 	{Name: "week", Minutes: 10080, APIPath: "weeks"},
 	{Name: "month", Minutes: 43200, APIPath: "months"},
 }
 
-func NewCollector(dbPath string) (*Collector, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// findTimeframe - 이름으로 Timeframe 조회
+func findTimeframe(name string) (Timeframe, error) {
+	for _, tf := range timeframes {
+		if tf.Name == name {
+			return tf, nil
+		}
+	}
+	return Timeframe{}, fmt.Errorf("알 수 없는 시간단위: %s", name)
+}
+
+// validateParams - 시간단위 API 가 지원하지 않는 추가 파라미터가 있으면 오류
+func (tf Timeframe) validateParams(params map[string]string) error {
+	for name := range params {
+		if !slices.Contains(tf.Params, name) {
+			return fmt.Errorf("%s 캔들 API 는 %q 파라미터를 지원하지 않습니다", tf.Name, name)
+		}
+	}
+	return nil
+}
+
+// candleEnd - start(KST) 에 시작한 캔들의 마감 시각 (월봉은 달력 기준)
+func candleEnd(tf Timeframe, start time.Time) time.Time {
+	if tf.Name == "month" {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.Add(time.Duration(tf.Minutes) * time.Minute)
+}
+
+// isProvisional - 아직 기간이 끝나지 않은 진행 중 캔들인지 (이후 업데이트로 값이 바뀔 수 있음)
+func (c *Collector) isProvisional(tf Timeframe, timestamp string) bool {
+	start, err := time.Parse(timestampLayout, timestamp)
+	if err != nil {
+		return false
+	}
+	nowKST := c.now().UTC().Add(9 * time.Hour)
+	return candleEnd(tf, start).After(nowKST)
+}
+
+func NewCollector(dbPath string, market string) (*Collector, error) {
+	collector, err := newFetcher(market)
 	if err != nil {
 		return nil, err
 	}
 
-	collector := &Collector{
-		db:          db,
-		market:      "KRW-BTC",
+	collector.dbPath = dbPath
+
+	// 이전 실행이 아직 종료 중이라 DB 가 잠겨 있으면 잠시 후 다시 시도
+	// (각 시도는 드라이버 busy_timeout 만큼 잠금 해제를 기다린 뒤 실패한다)
+	backoff := openRetryBackoff
+	for attempt := 0; ; attempt++ {
+		collector.db, err = sql.Open("sqlite3", sqliteDSN(dbPath))
+		if err != nil {
+			return nil, err
+		}
+
+		err = collector.initDatabase()
+		if err == nil {
+			return collector, nil
+		}
+		collector.db.Close()
+		collector.db = nil
+
+		if !isBusy(err) {
+			return nil, err
+		}
+		if attempt >= openRetries {
+			return nil, fmt.Errorf("잠금이 풀리지 않음 (시도 %d회): %w", attempt+1, err)
+		}
+		fmt.Fprintf(collector.Output, "%s 데이터베이스 잠금으로 초기화 재시도 (%d/%d)\n", collector.mark(markWarn), attempt+1, openRetries)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// sqliteDSN - recursive_triggers 를 켠 연결 문자열 (INSERT OR REPLACE 가 지운 행에도 통계 삭제 트리거 실행)
+func sqliteDSN(path string) string {
+	return path + "?_recursive_triggers=1"
+}
+
+// DB 열기 재시도 설정 (다른 프로세스가 잠근 경우)
+const (
+	openRetries      = 3
+	openRetryBackoff = 500 * time.Millisecond
+)
+
+// newFetcher - DB 없이 API 요청만 하는 Collector (benchmark 등)
+func newFetcher(market string) (*Collector, error) {
+	if !marketPattern.MatchString(market) {
+		return nil, fmt.Errorf("잘못된 마켓 코드: %q", market)
+	}
+
+	return &Collector{
+		market:      market,
 		apiURL:      "https://api.upbit.com/v1/candles",
+		Parser:      UpbitParser{},
+		marketsURL:  "https://api.upbit.com/v1/market/all",
+		now:         time.Now,
+		Output:      os.Stdout,
 		rateLimiter: NewRateLimiter(9), // 초당 9회로 안전하게 설정 (제한: 10회)
+		StopBefore:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		// 점검은 보통 수십 분 이상 이어지므로 짧은 재시도 대신 길게 쉬었다가 재개
+		MaintenanceBackoff: 5 * time.Minute,
+		// HTTP timeout(30초) × 재시도 4회보다 길게 잡아 정상적인 재시도는 멈춤으로 보지 않음
+		StallTimeout: 3 * time.Minute,
+		// 인증 취소/IP 차단처럼 계속 실패하는 상황에서 모든 요청을 재시도하며 차단을 악화시키지 않도록
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+		// 넓은 장애에서 요청마다 재시도하며 요청이 폭주하지 않도록 전체 재시도를 분당 60회로 제한
+		RetryBudget: 60,
+		// 일시적인 네트워크 오류로 실패한 시간단위는 다른 시간단위 수집이 끝난 뒤 한 번 더 시도
+		FailedTimeframeRetries: 1,
+		// 거래소 장애 등 긴 공백을 가짜 데이터로 채우지 않도록 기본 12개까지만 보간
+		MaxInterpolationGap: 12,
+		// 실제 캔들이 적을 때 작은 간격 하나만으로 보간 캔들이 실제보다 많아지지 않도록
+		MinInterpolationCandles: 100,
+		QualityWeights:          DefaultQualityWeights(),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: DefaultTransportConfig().transport(),
 		},
-	}
+	}, nil
+}
 
-	if err := collector.initDatabase(); err != nil {
-		return nil, err
+// table - 마켓/시간단위별 테이블 이름 (KRW-BTC 는 bitcoin_<timeframe>)
+func (c *Collector) table(tf Timeframe) string {
+	prefix := "bitcoin"
+	if c.market != defaultMarket {
+		prefix = strings.ToLower(strings.ReplaceAll(c.market, "-", "_"))
 	}
-
-	return collector, nil
+	return prefix + "_" + tf.Name
 }
 
 func (c *Collector) initDatabase() error {
+	return c.initCandleTables(c.db)
+}
+
+// initCandleTables - db 에 모든 시간단위 캔들 테이블 생성
+//
+// 한 테이블이 실패해도 나머지는 계속 만들고, 실패한 테이블 오류를 합쳐 반환한다.
+func (c *Collector) initCandleTables(db *sql.DB) error {
+	var errs []error
 	for _, tf := range timeframes {
-		query := fmt.Sprintf(`
-			CREATE TABLE IF NOT EXISTS bitcoin_%s (
-				timestamp TEXT PRIMARY KEY,
-				opening_price REAL NOT NULL,
-				high_price REAL NOT NULL,
-				low_price REAL NOT NULL,
-				trade_price REAL NOT NULL,
-				candle_acc_trade_volume REAL NOT NULL,
-				candle_acc_trade_price REAL NOT NULL,
-				is_interpolated INTEGER DEFAULT 0
-			)
-		`, tf.Name)
-
-		if _, err := c.db.Exec(query); err != nil {
-			return err
+		if err := c.createTable(db, tf); err != nil {
+			errs = append(errs, &DBError{Op: "init", Timeframe: tf.Name, Err: fmt.Errorf("%s 테이블 생성: %w", c.table(tf), err)})
 		}
 	}
 
-	fmt.Println("✓ 데이터베이스 초기화 완료")
+	return errors.Join(errs...)
+}
+
+func (c *Collector) createTable(db *sql.DB, tf Timeframe) error {
+	if _, err := db.Exec(candleSchema.createSQL(c.table(tf))); err != nil {
+		return err
+	}
+	// 예전 버전 DB 면 새로 생긴 컬럼을 추가 (CREATE TABLE IF NOT EXISTS 는 기존 테이블을 바꾸지 않음)
+	added, err := candleSchema.addMissingColumns(db, c.table(tf))
+	if err != nil {
+		return err
+	}
+	if len(added) > 0 && !c.quiet {
+		fmt.Fprintf(c.Output, "[%s] %s 누락 컬럼 추가: %s\n", c.table(tf), c.mark(markWork), strings.Join(added, ", "))
+	}
+	if err := c.ensureStatsSummary(db, tf); err != nil {
+		return err
+	}
+	if c.epoch {
+		return c.migrateEpoch(db, tf)
+	}
+	return nil
+}
+
+// timeframeStateTables - (market, timeframe) 열로 시간단위별 파생 상태를 저장하는 테이블 (기본 DB 파일)
+//
+// ResetTimeframe 이 캔들과 함께 지운다. 시간단위별 상태 테이블을 새로 만들면 여기에 추가한다.
+var timeframeStateTables = []string{"indicators", "candle_returns", "collect_checkpoints"}
+
+// ResetTimeframe - 시간단위 테이블을 삭제 후 빈 테이블로 재생성 (테이블이 없어도 안전)
+//
+// 저장 지표, 수익률, 수집 체크포인트 등 timeframeStateTables 의 해당 시간단위 행도 지운다.
+func (c *Collector) ResetTimeframe(tf Timeframe) error {
+	defer c.invalidateCache(tf)
+
+	for _, db := range c.candleDBs() {
+		if _, err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", c.table(tf))); err != nil {
+			return err
+		}
+		if err := c.createTable(db, tf); err != nil {
+			return err
+		}
+	}
+	for _, table := range timeframeStateTables {
+		var exists int
+		if err := c.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			continue
+		}
+		if _, err := c.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE market = ? AND timeframe = ?", table), c.market, tf.Name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (c *Collector) fetchCandles(tf Timeframe, to string) ([]Candle, error) {
+// DBError - 시간단위 DB 작업(Op: init, save, interpolate) 실패 (원래 오류는 Unwrap 으로 확인)
+type DBError struct {
+	Op        string
+	Timeframe string
+	Err       error
+}
+
+func (e *DBError) Error() string {
+	return fmt.Sprintf("%s failed for %s: %v", e.Op, e.Timeframe, e.Err)
+}
+
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusError - 200 이외의 응답 코드
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("API error: %d", e.StatusCode)
+}
+
+// UpbitAPIError - 업비트가 배열 대신 돌려준 오류 객체 {"error":{"name":...,"message":...}}
+type UpbitAPIError struct {
+	StatusCode int
+	Name       string
+	Message    string
+}
+
+func (e *UpbitAPIError) Error() string {
+	return fmt.Sprintf("API error: %d %s: %s", e.StatusCode, e.Name, e.Message)
+}
+
+// Retryable - 잠시 후 다시 요청하면 성공할 수 있는 오류인지 (요청 제한, 서버 오류)
+//
+// 잘못된 마켓/파라미터 등 나머지 오류는 재시도해도 같은 결과이므로 즉시 실패한다.
+func (e *UpbitAPIError) Retryable() bool {
+	return e.Name == "too_many_requests" || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// responseStatus - API 오류의 HTTP 상태 코드 (API 오류가 아니면 0)
+func responseStatus(err error) int {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	var apiErr *UpbitAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// isRetryableFetch - 캔들 요청 실패 중 재시도할 가치가 있는 경우
+func isRetryableFetch(err error) bool {
+	var apiErr *UpbitAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	if status := responseStatus(err); status != 0 {
+		return status == http.StatusTooManyRequests || status >= 500
+	}
+	// 응답 본문이 JSON 이 아니거나 형식이 다르면 다시 요청해도 같음
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return false
+	}
+	// 네트워크 오류 등
+	return true
+}
+
+// 요청 재시도 설정 (요청 제한, 서버 오류, 네트워크 오류)
+const (
+	fetchRetries      = 3
+	fetchRetryBackoff = 500 * time.Millisecond
+)
+
+// fetchCandles - to 이전 캔들 최대 200개 요청 (params 는 시간단위별 추가 파라미터, 없으면 nil)
+func (c *Collector) fetchCandles(ctx context.Context, tf Timeframe, to string, params map[string]string) ([]Candle, error) {
+	if err := tf.validateParams(params); err != nil {
+		return nil, err
+	}
+	if d := c.jitterDelay(); d > 0 && !sleepCtx(ctx, d) {
+		return nil, ctx.Err()
+	}
+
+	breaker := c.circuitBreaker()
+	if breaker != nil {
+		if err := breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	var candles []Candle
+	err := c.withRetry(ctx, tf.Name, func() error {
+		var err error
+		candles, err = c.requestCandles(ctx, tf, to, params)
+		return err
+	})
+	if breaker != nil {
+		c.recordFetch(ctx, breaker, tf, err)
+	}
+	return candles, err
+}
+
+// waitTurn - 요청 전 대기 (여러 마켓 수집 중이면 Scheduler 차례, 아니면 Collector rate limiter)
+func (c *Collector) waitTurn(label string) {
+	if c.scheduler != nil {
+		c.scheduler.Wait(c.market + "/" + label)
+		return
+	}
 	// Rate limiter 적용 - 모든 goroutine이 공유
 	c.rateLimiter.Wait()
+}
 
-	url := fmt.Sprintf("%s/%s?market=%s&count=200", c.apiURL, tf.APIPath, c.market)
-	if to != "" {
-		url += "&to=" + to
+// withRetry - rate limiter 대기 후 fn 실행, 재시도 가능한 오류면 백오프하며 반복 (캔들/체결 요청 공용)
+//
+// ctx 가 취소되면 더 재시도하지 않고 ctx 오류를 반환한다.
+func (c *Collector) withRetry(ctx context.Context, label string, fn func() error) error {
+	backoff := fetchRetryBackoff
+	for attempt := 0; ; attempt++ {
+		if c.budget != nil && !c.budget.take(label) {
+			return errRequestBudget
+		}
+		c.waitTurn(label)
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRetryableFetch(err) || attempt >= fetchRetries {
+			return err
+		}
+		if b := c.retryBudget(); b != nil && !b.take() {
+			return fmt.Errorf("%w (분당 %d회): %w", errRetryBudget, c.RetryBudget, err)
+		}
+		fmt.Fprintf(c.Output, "[%s] %s API 요청 재시도 (%d/%d): %v\n", label, c.mark(markWarn), attempt+1, fetchRetries, err)
+		if !sleepCtx(ctx, backoff) {
+			return ctx.Err()
+		}
+		backoff *= 2
 	}
+}
 
-	resp, err := c.httpClient.Get(url)
+// sleepCtx - d 동안 대기 (ctx 가 먼저 취소되면 false)
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// requestCandles - rate limit 대기 없이 API 1회 요청 (ctx 취소 시 진행 중인 요청 중단)
+func (c *Collector) requestCandles(ctx context.Context, tf Timeframe, to string, params map[string]string) ([]Candle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.candlesURL(tf, to, params), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return c.Parser.ParseCandles(resp.StatusCode, body)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %d", resp.StatusCode)
+// candlesURL - 캔들 API 요청 주소 (쿼리 파라미터는 URL 인코딩)
+func (c *Collector) candlesURL(tf Timeframe, to string, params map[string]string) string {
+	query := url.Values{}
+	query.Set("market", c.market)
+	query.Set("count", "200")
+	if to != "" {
+		query.Set("to", to)
+	}
+	for name, value := range params {
+		query.Set(name, value)
 	}
+	return fmt.Sprintf("%s/%s?%s", c.apiURL, tf.APIPath, query.Encode())
+}
 
+// pageCursor - 다음 페이지 요청의 to 값 (업비트 ISO8601 형식, 경계 캔들이 다시 오지 않도록 1초 전)
+//
+// to 는 해당 시각 "이전" 캔들을 돌려주지만 시각 형식과 경계 처리에 따라 마지막 캔들이 다시 포함될 수 있다.
+func pageCursor(oldestUTC string) string {
+	t, err := time.Parse(timestampLayout, oldestUTC)
+	if err != nil {
+		return oldestUTC
+	}
+	return t.Add(-time.Second).Format(time.RFC3339)
+}
+
+// decodeCandles - 응답 본문이 배열이면 캔들 목록, 오류 객체면 UpbitAPIError
+func decodeCandles(status int, body []byte) ([]Candle, error) {
 	var candles []Candle
-	if err := json.NewDecoder(resp.Body).Decode(&candles); err != nil {
+	if err := decodeResponse(status, body, &candles); err != nil {
 		return nil, err
 	}
-
 	return candles, nil
 }
 
-func (c *Collector) saveCandles(tf Timeframe, candles []Candle) (int, error) {
+// decodeResponse - 업비트 응답 본문을 v 로 해석 (오류 객체면 UpbitAPIError, 그 외 200 이 아니면 HTTPStatusError)
+func decodeResponse(status int, body []byte, v any) error {
+	var raw json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		if status != http.StatusOK {
+			return &HTTPStatusError{StatusCode: status}
+		}
+		return err
+	}
+
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var payload struct {
+			Error struct {
+				Name    json.RawMessage `json:"name"` // 문자열 또는 숫자 ("404", 404)
+				Message string          `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(raw, &payload); err == nil && (payload.Error.Name != nil || payload.Error.Message != "") {
+			return &UpbitAPIError{
+				StatusCode: status,
+				Name:       strings.Trim(string(payload.Error.Name), `"`),
+				Message:    payload.Error.Message,
+			}
+		}
+	}
+
+	if status != http.StatusOK {
+		return &HTTPStatusError{StatusCode: status}
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("응답 해석 실패: %w", err)
+	}
+	return nil
+}
+
+// 저장 재시도 설정 (SQLITE_BUSY / SQLITE_LOCKED)
+const (
+	saveRetries      = 5
+	saveRetryBackoff = 100 * time.Millisecond
+)
+
+// isBusy - 다른 연결이 DB 를 잡고 있어 일시적으로 실패한 경우
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return false
+}
+
+// finite - 가격/거래량 값이 모두 유한한지
+func (c Candle) finite() bool {
+	for _, v := range []float64{c.OpeningPrice, c.HighPrice, c.LowPrice, c.TradePrice, c.CandleAccTradeVolume, c.CandleAccTradePrice} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedCandle - saveCandles 가 저장하지 못한 캔들과 이유
+type FailedCandle struct {
+	Candle Candle
+	Err    error
+}
+
+// saveCandles - 배치 저장 (DB 잠금 시 배치 전체를 백오프하며 재시도, 중복 확인으로 재시도해도 안전)
+//
+// 현재 시각보다 한 간격 넘게 미래이거나 timestamp/값이 잘못된 캔들, 행 단위 INSERT 가 실패하거나
+// panic 한 캔들은 나머지를 커밋한 뒤 failed 로 돌려준다. 한 행 때문에 배치 전체를 잃지 않도록 한다.
+// 연도 분할에서 일부 DB 파일만 커밋된 채 실패하면 커밋된 캔들 수를 오류와 함께 돌려준다.
+func (c *Collector) saveCandles(tf Timeframe, candles []Candle) (saved int, failed []FailedCandle, err error) {
+	return c.saveCandlesAs(tf, candles, c.Conflict)
+}
+
+// saveCandlesAs - Conflict 설정 대신 mode 로 중복을 처리하는 saveCandles (진행 중 캔들 덮어쓰기 등)
+func (c *Collector) saveCandlesAs(tf Timeframe, candles []Candle, mode ConflictMode) (saved int, failed []FailedCandle, err error) {
+	candles, failed = c.rejectInvalid(tf, candles)
 	if len(candles) == 0 {
-		return 0, nil
+		return 0, failed, nil
+	}
+	if c.Rounding != nil {
+		// rejectInvalid 가 새 슬라이스를 만들므로 호출자 캔들은 바뀌지 않음
+		for i := range candles {
+			c.Rounding.apply(&candles[i])
+		}
 	}
 
-	tx, err := c.db.Begin()
-	if err != nil {
-		return 0, err
+	var inserted []Candle
+	pending := candles
+	backoff := saveRetryBackoff
+	for attempt := 0; ; attempt++ {
+		committed, rowFailed, batchErr := c.saveBatch(tf, pending, mode)
+		inserted = append(inserted, committed...)
+		failed = append(failed, rowFailed...)
+		if len(committed) > 0 {
+			c.invalidateCache(tf)
+		}
+		if batchErr == nil {
+			break
+		}
+		if !isBusy(batchErr) || attempt >= saveRetries {
+			// 연도 분할에서 앞 DB 파일에 이미 커밋된 캔들은 저장된 것으로 세고 훅에도 넘김
+			err = &DBError{Op: "save", Timeframe: tf.Name,
+				Err: fmt.Errorf("%d개 캔들 (시도 %d회): %w", len(pending), attempt+1, batchErr)}
+			if hookErr := c.runOnSave(tf, inserted); hookErr != nil {
+				err = errors.Join(err, hookErr)
+			}
+			return len(inserted), failed, err
+		}
+		fmt.Fprintf(c.Output, "[%s] %s DB 잠금으로 저장 재시도 (%d/%d)\n", tf.Name, c.mark(markWarn), attempt+1, saveRetries)
+		time.Sleep(backoff)
+		backoff *= 2
+		pending = withoutCandles(pending, committed, rowFailed)
 	}
-	defer tx.Rollback()
 
-	checkStmt, err := tx.Prepare(fmt.Sprintf(
-		"SELECT COUNT(*) FROM bitcoin_%s WHERE timestamp = ?", tf.Name))
+	return len(inserted), failed, c.runOnSave(tf, inserted)
+}
+
+// runOnSave - 새로 저장된 캔들로 OnSave 훅 호출 (AbortOnHookError 일 때만 훅 오류 반환)
+func (c *Collector) runOnSave(tf Timeframe, inserted []Candle) error {
+	if c.OnSave == nil || len(inserted) == 0 {
+		return nil
+	}
+	if err := c.OnSave(inserted, tf); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s OnSave 훅 실패: %v\n", tf.Name, c.mark(markWarn), err)
+		if c.AbortOnHookError {
+			return fmt.Errorf("OnSave 훅 실패: %w", err)
+		}
+	}
+	return nil
+}
+
+// withoutCandles - 재시도할 캔들 (이미 커밋되었거나 행 단위로 실패한 timestamp 제외)
+func withoutCandles(candles []Candle, saved []Candle, failed []FailedCandle) []Candle {
+	if len(saved) == 0 && len(failed) == 0 {
+		return candles
+	}
+	done := make(map[string]bool, len(saved)+len(failed))
+	for _, candle := range saved {
+		done[candle.CandleDateTimeKST] = true
+	}
+	for _, f := range failed {
+		done[f.Candle.CandleDateTimeKST] = true
+	}
+	remaining := make([]Candle, 0, len(candles))
+	for _, candle := range candles {
+		if !done[candle.CandleDateTimeKST] {
+			remaining = append(remaining, candle)
+		}
+	}
+	return remaining
+}
+
+// rejectInvalid - 미래 캔들(시계 기준 한 간격 초과), timestamp 오류 캔들, NaN/Inf 값 캔들을 걸러냄
+//
+// NaN 은 NULL 로 저장되어 INSERT OR IGNORE 가 NOT NULL 위반을 조용히 무시하므로 미리 거른다.
+func (c *Collector) rejectInvalid(tf Timeframe, candles []Candle) ([]Candle, []FailedCandle) {
+	limit := c.now().UTC().Add(9 * time.Hour).Add(time.Duration(tf.Minutes) * time.Minute)
+
+	valid := make([]Candle, 0, len(candles))
+	var rejected []FailedCandle
+	for _, candle := range candles {
+		t, err := time.Parse(timestampLayout, candle.CandleDateTimeKST)
+		if err == nil && t.After(limit) {
+			err = fmt.Errorf("미래 캔들 (기준 %s)", limit.Format(timestampLayout))
+		}
+		if err == nil && !candle.finite() {
+			err = errors.New("가격/거래량에 NaN 또는 Inf 값")
+		}
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 잘못된 캔들 저장 거부: %q\n", tf.Name, c.mark(markWarn), candle.CandleDateTimeKST)
+			rejected = append(rejected, FailedCandle{Candle: candle, Err: err})
+			continue
+		}
+		valid = append(valid, candle)
+	}
+	return valid, rejected
+}
+
+// saveBatch - 저장 대상 DB 별로 나눠 저장하고 새로 삽입된 캔들과 행 단위로 실패한 캔들 반환
+//
+// DB 파일마다 따로 커밋하므로 뒤 파일에서 실패하면 앞 파일에 커밋된 캔들을 오류와 함께 돌려준다.
+func (c *Collector) saveBatch(tf Timeframe, candles []Candle, mode ConflictMode) ([]Candle, []FailedCandle, error) {
+	if c.shards == nil {
+		return c.saveBatchIn(c.db, tf, candles, mode)
+	}
+
+	var order []*sql.DB
+	groups := make(map[*sql.DB][]Candle)
+	for _, candle := range candles {
+		db, err := c.candleDBFor(candle.CandleDateTimeKST)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := groups[db]; !ok {
+			order = append(order, db)
+		}
+		groups[db] = append(groups[db], candle)
+	}
+
+	var inserted []Candle
+	var failed []FailedCandle
+	for _, db := range order {
+		saved, rowFailed, err := c.saveBatchIn(db, tf, groups[db], mode)
+		if err != nil {
+			return inserted, failed, err
+		}
+		inserted = append(inserted, saved...)
+		failed = append(failed, rowFailed...)
+	}
+	return inserted, failed, nil
+}
+
+// saveBatchIn - 한 트랜잭션으로 저장하고 새로 삽입된 캔들 반환 (ConflictReplace 는 덮어쓴 캔들 포함)
+//
+// 행 단위 오류(panic 포함)는 그 캔들만 failed 에 담고 나머지는 커밋한다. DB 잠금과 ConflictError 의
+// 중복 오류는 배치 전체를 실패시킨다.
+func (c *Collector) saveBatchIn(db *sql.DB, tf Timeframe, candles []Candle, mode ConflictMode) ([]Candle, []FailedCandle, error) {
+	tx, err := db.Begin()
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
-	defer checkStmt.Close()
+	defer tx.Rollback()
 
-	insertStmt, err := tx.Prepare(fmt.Sprintf(`
-		INSERT INTO bitcoin_%s
-		(timestamp, opening_price, high_price, low_price, trade_price,
-		 candle_acc_trade_volume, candle_acc_trade_price, is_interpolated)
-		VALUES (?, ?, ?, ?, ?, ?, ?, 0)
-	`, tf.Name))
+	insertStmt, err := tx.Prepare(c.candleTableSchema().insertSQL(mode.verb(), c.table(tf)))
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
 	defer insertStmt.Close()
 
-	inserted := 0
+	var inserted []Candle
+	var failed []FailedCandle
 	for _, candle := range candles {
-		var count int
-		err := checkStmt.QueryRow(candle.CandleDateTimeKST).Scan(&count)
+		// TopUp 이 마감 후 다시 받아 덮어쓸 수 있도록 진행 중 캔들을 표시
+		candle.IsProvisional = c.isProvisional(tf, candle.CandleDateTimeKST)
+		res, err := c.insertRow(insertStmt, candle)
+		if isBusy(err) {
+			return nil, nil, err
+		}
 		if err != nil {
+			if mode == ConflictError {
+				return nil, nil, fmt.Errorf("%s 저장 실패: %w", candle.CandleDateTimeKST, err)
+			}
+			fmt.Fprintf(c.Output, "[%s] %s 캔들 저장 실패 %q: %v\n", tf.Name, c.mark(markWarn), candle.CandleDateTimeKST, err)
+			failed = append(failed, FailedCandle{Candle: candle, Err: err})
 			continue
 		}
-
-		if count == 0 {
-			_, err = insertStmt.Exec(
-				candle.CandleDateTimeKST,
-				candle.OpeningPrice,
-				candle.HighPrice,
-				candle.LowPrice,
-				candle.TradePrice,
-				candle.CandleAccTradeVolume,
-				candle.CandleAccTradePrice,
-			)
-			if err == nil {
-				inserted++
-			}
+		// ConflictIgnore 에서 이미 있던 timestamp 는 영향받은 행이 0
+		if n, _ := res.RowsAffected(); n > 0 {
+			inserted = append(inserted, candle)
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, err
+		return nil, nil, err
 	}
 
-	return inserted, nil
+	return inserted, failed, nil
+}
+
+// insertRow - 실제 캔들 1개 INSERT (인자 변환 중 panic 도 오류로 돌려줌)
+func (c *Collector) insertRow(stmt *sql.Stmt, candle Candle) (res sql.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("저장 중 panic: %v", r)
+		}
+	}()
+	candle.IsInterpolated = false
+	return stmt.Exec(c.candleRow(&candle)...)
 }
 
-func (c *Collector) collectTimeframe(tf Timeframe, wg *sync.WaitGroup) {
-	defer wg.Done()
+// InterpolationStrategy - 보간 캔들 값 계산 방식
+type InterpolationStrategy int
 
-	fmt.Printf("\n%s\n", "============================================================")
-	fmt.Printf("📊 %s 데이터 수집 시작 (goroutine)\n", tf.Name)
-	fmt.Printf("%s\n", "============================================================")
+const (
+	// InterpolateLinear - 가격/거래량 모두 양쪽 캔들 사이 선형보간
+	InterpolateLinear InterpolationStrategy = iota
+	// InterpolateZeroVolume - 가격은 선형보간, 거래량/거래대금은 0 (거래 없던 구간으로 표시)
+	InterpolateZeroVolume
+)
+
+func (s InterpolationStrategy) String() string {
+	switch s {
+	case InterpolateLinear:
+		return "linear"
+	case InterpolateZeroVolume:
+		return "zero-volume"
+	default:
+		return fmt.Sprintf("InterpolationStrategy(%d)", int(s))
+	}
+}
 
-	totalCount := 0
-	iteration := 0
-	var toTimestamp string
-	var prevOldest string
+// parseInterpolationStrategy - CLI 이름으로 보간 방식 조회
+func parseInterpolationStrategy(name string) (InterpolationStrategy, error) {
+	for _, s := range []InterpolationStrategy{InterpolateLinear, InterpolateZeroVolume} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("알 수 없는 보간 방식: %s (linear, zero-volume)", name)
+}
+
+// ConflictMode - 이미 있는 timestamp 캔들을 저장할 때의 동작
+type ConflictMode int
+
+const (
+	// ConflictIgnore - 기존 캔들 유지 (INSERT OR IGNORE)
+	ConflictIgnore ConflictMode = iota
+	// ConflictReplace - 새 캔들로 덮어씀 (INSERT OR REPLACE, 보간 캔들도 실제 캔들로 교체)
+	ConflictReplace
+	// ConflictError - 중복이면 저장 실패 (INSERT)
+	ConflictError
+)
+
+func (m ConflictMode) String() string {
+	switch m {
+	case ConflictIgnore:
+		return "ignore"
+	case ConflictReplace:
+		return "replace"
+	case ConflictError:
+		return "error"
+	default:
+		return fmt.Sprintf("ConflictMode(%d)", int(m))
+	}
+}
+
+// verb - 저장 모드에 해당하는 INSERT 구문
+func (m ConflictMode) verb() string {
+	switch m {
+	case ConflictReplace:
+		return "INSERT OR REPLACE"
+	case ConflictError:
+		return "INSERT"
+	default:
+		return "INSERT OR IGNORE"
+	}
+}
+
+// parseConflictMode - CLI 이름으로 중복 처리 방식 조회
+func parseConflictMode(name string) (ConflictMode, error) {
+	for _, m := range []ConflictMode{ConflictIgnore, ConflictReplace, ConflictError} {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("알 수 없는 중복 처리 방식: %s (ignore, replace, error)", name)
+}
+
+// CollectResult - 시간단위별 수집 결과
+type CollectResult struct {
+	Timeframe    string
+	Pages        int
+	Fetched      int
+	Saved        int
+	Rejected     int // 미래 시각, 행 저장 오류 등으로 저장하지 못한 캔들 수
+	Interpolated int
+	Requests     int   // 사용한 API 요청 수 (MaxRequests 설정 시에만 집계)
+	TimedOut     bool  // PerTimeframeTimeout 을 넘어 중단됨 (체크포인트부터 다음 실행에서 이어서 수집)
+	Retries      int   // 오류로 끝나 CollectAll 끝에 다시 수집한 횟수 (FailedTimeframeRetries)
+	Err          error // 수집을 중단시킨 오류 (정상 종료 시 nil)
+}
+
+// collectCursor - 시간단위 수집 진행 위치 (watchdog 재시작 시 여기서부터 이어서 수집)
+type collectCursor struct {
+	result           CollectResult
+	toTimestamp      string // 다음 요청의 to (마지막으로 저장한 페이지의 가장 오래된 캔들)
+	prevOldest       string
+	newest           string
+	maintenanceWaits int
+	deadline         time.Time // PerTimeframeTimeout 기준 종료 시각 (c.now 기준, 0 이면 제한 없음)
+}
+
+// collectTimeframe - 최신 캔들부터 과거 방향으로 페이지 단위 수집 (MaxPages 로 제한 가능)
+//
+// StallTimeout 이 0 보다 크면 watchdog 이 진행이 멈춘 요청을 취소하고 마지막 저장 위치부터 다시 수집한다.
+func (c *Collector) collectTimeframe(tf Timeframe) CollectResult {
+	fmt.Fprintf(c.Output, "\n%s\n", "============================================================")
+	fmt.Fprintf(c.Output, "%s %s 데이터 수집 시작 (goroutine)\n", c.mark(markStart), tf.Name)
+	fmt.Fprintf(c.Output, "%s\n", "============================================================")
+
+	cur := &collectCursor{result: CollectResult{Timeframe: tf.Name}}
+	// 실제 캔들이 들어올 자리를 FillToNow 합성 캔들이 막지 않도록 먼저 제거
+	if _, err := c.ClearFillToNow(tf); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 채움 캔들 삭제 실패: %v\n", tf.Name, c.mark(markWarn), err)
+	}
+
+	ctx := context.Background()
+	if c.PerTimeframeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.PerTimeframeTimeout)
+		defer cancel()
+		cur.deadline = c.now().Add(c.PerTimeframeTimeout)
+	}
+
+	if c.StallTimeout > 0 {
+		c.collectWithWatchdog(ctx, tf, cur)
+	} else {
+		c.collectPages(ctx, tf, cur, func(time.Time) {})
+	}
+
+	if ctx.Err() != nil || cur.result.TimedOut {
+		cur.result.TimedOut = true
+		if err := c.saveCheckpoint(tf, cur); err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 체크포인트 저장 실패: %v\n", tf.Name, c.mark(markWarn), err)
+		}
+		fmt.Fprintf(c.Output, "[%s] %s 시간단위 수집 시간 제한(%v) 초과 - 받은 페이지까지 저장 후 중단 (다음 실행에서 이어서 수집)\n",
+			tf.Name, c.mark(markPause), c.PerTimeframeTimeout)
+	}
 
-	for {
-		iteration++
-		candles, err := c.fetchCandles(tf, toTimestamp)
+	result := cur.result
+	fmt.Fprintf(c.Output, "[%s] %s 총 %d개 캔들 수집 및 저장 완료\n", tf.Name, c.mark(markOK), result.Saved)
+	if result.Rejected > 0 {
+		fmt.Fprintf(c.Output, "[%s] %s 미래 시각, 저장 오류 등으로 %d개 캔들 저장하지 못함\n", tf.Name, c.mark(markWarn), result.Rejected)
+	}
+	c.emitProgress(ProgressEvent{
+		Timeframe: tf.Name, Pages: result.Pages, Fetched: result.Fetched, Saved: result.Saved,
+		Newest: cur.newest, Oldest: cur.prevOldest, Done: true, Err: result.Err,
+	})
+	return result
+}
+
+// collectPages - cur 위치부터 페이지 수집
+//
+// 페이지를 저장할 때마다 touch(현재 시각)로 진행을 알리고, 점검 대기처럼 일부러 쉬는 동안은
+// touch(재개 예정 시각)로 watchdog 이 멈춘 것으로 보지 않게 한다.
+// ctx 가 취소되면 cur 를 마지막으로 저장한 페이지 상태로 두고 반환한다.
+func (c *Collector) collectPages(ctx context.Context, tf Timeframe, cur *collectCursor, touch func(until time.Time)) {
+	result := &cur.result
+	for ctx.Err() == nil {
+		if !cur.deadline.IsZero() && !c.now().Before(cur.deadline) {
+			result.TimedOut = true
+			return
+		}
+		result.Pages++
+		candles, err := c.fetchCandles(ctx, tf, cur.toTimestamp, nil)
+		if ctx.Err() != nil {
+			result.Pages--
+			return
+		}
+		if errors.Is(err, errRequestBudget) {
+			result.Pages--
+			if err := c.saveCheckpoint(tf, cur); err != nil {
+				fmt.Fprintf(c.Output, "[%s] %s 체크포인트 저장 실패: %v\n", tf.Name, c.mark(markWarn), err)
+			}
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 예산(%d회) 소진 - 받은 페이지까지 저장 후 중단 (다음 실행에서 이어서 수집)\n",
+				tf.Name, c.mark(markPause), c.MaxRequests)
+			return
+		}
+		if err != nil && c.MaintenanceBackoff > 0 && responseStatus(err) == http.StatusServiceUnavailable {
+			cur.maintenanceWaits++
+			fmt.Fprintf(c.Output, "[%s] %s 업비트 점검 중 (503) - 점검 대기 %d회째, %v 후 같은 위치부터 재개\n",
+				tf.Name, c.mark(markPause), cur.maintenanceWaits, c.MaintenanceBackoff)
+			touch(time.Now().Add(c.MaintenanceBackoff))
+			result.Pages--
+			sleepCtx(ctx, c.MaintenanceBackoff)
+			continue
+		}
+		if cur.maintenanceWaits > 0 && err == nil {
+			fmt.Fprintf(c.Output, "[%s] %s 점검 종료, 수집 재개\n", tf.Name, c.mark(markOK))
+			cur.maintenanceWaits = 0
+		}
 		if err != nil {
-			fmt.Printf("[%s] ✗ API 요청 실패: %v\n", tf.Name, err)
-			break
+			// 재시도 패스나 다음 실행이 이미 받은 구간을 건너뛰고 멈춘 위치부터 이어서 수집하도록
+			if err := c.saveCheckpoint(tf, cur); err != nil {
+				fmt.Fprintf(c.Output, "[%s] %s 체크포인트 저장 실패: %v\n", tf.Name, c.mark(markWarn), err)
+			}
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Err = fmt.Errorf("API 요청 실패: %w", err)
+			return
 		}
 
 		if len(candles) == 0 {
-			fmt.Printf("[%s] ⚠️  더 이상 데이터가 없습니다.\n", tf.Name)
-			break
+			fmt.Fprintf(c.Output, "[%s] %s 더 이상 데이터가 없습니다.\n", tf.Name, c.mark(markWarn))
+			return
+		}
+		result.Fetched += len(candles)
+
+		// 이전 페이지 경계 캔들이 다시 오면 제외 (다음 페이지는 이전 페이지보다 엄격히 과거여야 함)
+		overlap := 0
+		for overlap < len(candles) && cur.prevOldest != "" && candles[overlap].CandleDateTimeKST >= cur.prevOldest {
+			overlap++
+		}
+		candles = candles[overlap:]
+		if len(candles) == 0 {
+			fmt.Fprintf(c.Output, "[%s] %s 동일한 데이터 반복 감지. 수집 중단.\n", tf.Name, c.mark(markWarn))
+			return
 		}
 
 		oldest := candles[len(candles)-1]
 		currentOldest := oldest.CandleDateTimeKST
 
-		// 중복 감지
-		if prevOldest == currentOldest {
-			fmt.Printf("[%s] ⚠️  동일한 데이터 반복 감지. 수집 중단.\n", tf.Name)
-			break
-		}
+		// StopBefore 이전 캔들 제외 (StopBeforeTimeframes 가 없으면 모든 시간단위가 같은 구간을 갖도록)
+		candles, reachedStop := c.trimBeforeStop(tf, candles)
 
 		// DB 저장
-		saved, err := c.saveCandles(tf, candles)
+		saved, failed, err := c.saveCandles(tf, candles)
+		result.Rejected += len(failed)
 		if err != nil {
-			fmt.Printf("[%s] ✗ 저장 실패: %v\n", tf.Name, err)
-			break
+			fmt.Fprintf(c.Output, "[%s] %s 저장 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Saved += saved
+			result.Err = err
+			return
 		}
 
-		totalCount += saved
-		toTimestamp = oldest.CandleDateTimeUTC
-		prevOldest = currentOldest
-
-		if iteration%10 == 0 {
-			fmt.Printf("[%s] 반복 %d: %d개 수집, %d개 저장 (총 %d개)\n",
-				tf.Name, iteration, len(candles), saved, totalCount)
-			fmt.Printf("[%s]   최신: %s, 최고: %s\n",
+		result.Saved += saved
+		cur.toTimestamp = pageCursor(oldest.CandleDateTimeUTC)
+		cur.prevOldest = currentOldest
+		if cur.newest == "" && len(candles) > 0 {
+			cur.newest = candles[0].CandleDateTimeKST
+		}
+		touch(time.Now())
+		c.emitProgress(ProgressEvent{
+			Timeframe: tf.Name, Pages: result.Pages, Fetched: result.Fetched, Saved: result.Saved,
+			Newest: cur.newest, Oldest: currentOldest,
+		})
+
+		if result.Pages%10 == 0 && len(candles) > 0 {
+			fmt.Fprintf(c.Output, "[%s] 반복 %d: %d개 수집, %d개 저장 (총 %d개)\n",
+				tf.Name, result.Pages, len(candles), saved, result.Saved)
+			fmt.Fprintf(c.Output, "[%s]   최신: %s, 최고: %s\n",
 				tf.Name, candles[0].CandleDateTimeKST, currentOldest)
 		}
 
+		if reachedStop {
+			fmt.Fprintf(c.Output, "[%s] %s %s 이전 데이터 도달. 수집 완료.\n",
+				tf.Name, c.mark(markOK), c.stopBefore(tf).Format("2006-01-02"))
+			return
+		}
+
 		if saved == 0 {
-			fmt.Printf("[%s] ⚠️  모든 데이터가 이미 존재합니다. 수집 중단.\n", tf.Name)
-			break
+			// MaxRequests 로 멈췄던 위치가 있으면 거기서부터 이어서 수집
+			oldest, cursor, ok, err := c.takeCheckpoint(tf, cur.prevOldest)
+			if err != nil {
+				fmt.Fprintf(c.Output, "[%s] %s 체크포인트 조회 실패: %v\n", tf.Name, c.mark(markWarn), err)
+			}
+			if ok {
+				fmt.Fprintf(c.Output, "[%s] %s 이미 있는 구간 도달 - 체크포인트 %s 부터 이어서 수집\n", tf.Name, c.mark(markOK), oldest)
+				cur.prevOldest, cur.toTimestamp = oldest, cursor
+				continue
+			}
+			fmt.Fprintf(c.Output, "[%s] %s 모든 데이터가 이미 존재합니다. 수집 중단.\n", tf.Name, c.mark(markWarn))
+			return
 		}
 
-		oldestTime, err := time.Parse("2006-01-02T15:04:05", currentOldest)
-		if err == nil && oldestTime.Year() < 2019 {
-			fmt.Printf("[%s] ✓ 2019년 이전 데이터 도달. 수집 완료.\n", tf.Name)
-			break
+		if c.MaxPages > 0 && result.Pages >= c.MaxPages {
+			fmt.Fprintf(c.Output, "[%s] %s 페이지 제한(%d) 도달. 수집 중단.\n", tf.Name, c.mark(markOK), c.MaxPages)
+			return
 		}
 	}
-
-	fmt.Printf("[%s] ✓ 총 %d개 캔들 수집 및 저장 완료\n", tf.Name, totalCount)
-	c.interpolateMissingData(tf)
 }
 
-func (c *Collector) interpolateMissingData(tf Timeframe) {
-	fmt.Printf("[%s] 🔧 결측값 보간 시작...\n", tf.Name)
-
-	rows, err := c.db.Query(fmt.Sprintf(`
-		SELECT timestamp, opening_price, high_price, low_price,
-		       trade_price, candle_acc_trade_volume, candle_acc_trade_price
-		FROM bitcoin_%s
-		WHERE is_interpolated = 0
-		ORDER BY timestamp ASC
-	`, tf.Name))
-	if err != nil {
-		fmt.Printf("[%s] ✗ 보간 실패: %v\n", tf.Name, err)
-		return
+// stopBefore - tf 의 수집 하한 (StopBeforeTimeframes 우선)
+func (c *Collector) stopBefore(tf Timeframe) time.Time {
+	if t, ok := c.StopBeforeTimeframes[tf.Name]; ok {
+		return t
 	}
-	defer rows.Close()
+	return c.StopBefore
+}
 
-	type Record struct {
-		Timestamp string
-		Values    [6]float64
+// trimBeforeStop - 최신순 캔들 목록에서 tf 의 StopBefore 이전 캔들을 잘라냄
+func (c *Collector) trimBeforeStop(tf Timeframe, candles []Candle) ([]Candle, bool) {
+	stop := c.stopBefore(tf)
+	for i, candle := range candles {
+		t, err := time.Parse(timestampLayout, candle.CandleDateTimeKST)
+		if err == nil && t.Before(stop) {
+			return candles[:i], true
+		}
 	}
+	return candles, false
+}
+
+func (c *Collector) interpolateMissingData(tf Timeframe) (int, error) {
+	return c.interpolateFrom(tf, "")
+}
 
-	var records []Record
-	for rows.Next() {
-		var r Record
-		err := rows.Scan(&r.Timestamp,
-			&r.Values[0], &r.Values[1], &r.Values[2],
-			&r.Values[3], &r.Values[4], &r.Values[5])
+// interpolateFrom - from(KST timestamp) 이후 실제 캔들 사이만 보간 (from 이 비면 전체)
+//
+// from 을 주면 이미 보간한 테이블에 새로 이어 붙인 구간만 다루므로 MinInterpolationCandles 는 보지 않는다.
+func (c *Collector) interpolateFrom(tf Timeframe, from string) (int, error) {
+	fmt.Fprintf(c.Output, "[%s] %s 결측값 보간 시작...\n", tf.Name, c.mark(markWork))
+
+	query := candleSchema.selectSQL(c.table(tf)) + " WHERE is_interpolated = 0 AND timestamp >= ? ORDER BY timestamp ASC"
+	var records []Candle
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query, from)
 		if err != nil {
-			continue
+			fmt.Fprintf(c.Output, "[%s] %s 보간 실패: %v\n", tf.Name, c.mark(markFail), err)
+			return 0, &DBError{Op: "interpolate", Timeframe: tf.Name, Err: err}
 		}
-		records = append(records, r)
+		part, err := c.scanCandles(rows)
+		rows.Close()
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 보간 실패: %v\n", tf.Name, c.mark(markFail), err)
+			return 0, &DBError{Op: "interpolate", Timeframe: tf.Name, Err: err}
+		}
+		records = append(records, part...)
+	}
+
+	// 진행 중 캔들은 값이 계속 바뀌므로 보간 기준점에서 제외
+	if !c.InterpolateProvisional && len(records) > 0 && c.isProvisional(tf, records[len(records)-1].CandleDateTimeKST) {
+		records = records[:len(records)-1]
 	}
 
 	if len(records) < 2 {
-		fmt.Printf("[%s] ✓ 데이터 부족으로 보간 불가\n", tf.Name)
-		return
+		fmt.Fprintf(c.Output, "[%s] %s 데이터 부족으로 보간 불가\n", tf.Name, c.mark(markOK))
+		return 0, nil
+	}
+	if from == "" && c.MinInterpolationCandles > 0 && len(records) < c.MinInterpolationCandles {
+		fmt.Fprintf(c.Output, "[%s] %s 실제 캔들 %d개로 최소 %d개 미만 - 보간 생략\n",
+			tf.Name, c.mark(markWarn), len(records), c.MinInterpolationCandles)
+		return 0, nil
 	}
 
 	interpolatedCount := 0
-	interval := time.Duration(tf.Minutes) * time.Minute
+	skippedGaps := 0
 
+	// 시각은 Asia/Seoul 로 해석하고 candleEnd 로 다음 캔들 시작을 구해 일/주/월봉이 KST 캔들 시작
+	// (업비트 기준 09:00 KST, 월봉은 매월 1일)에 맞게 한다
 	for i := 0; i < len(records)-1; i++ {
-		currentTime, _ := time.Parse("2006-01-02T15:04:05", records[i].Timestamp)
-		nextTime, _ := time.Parse("2006-01-02T15:04:05", records[i+1].Timestamp)
-
-		expectedNext := currentTime.Add(interval)
-
-		if nextTime.After(expectedNext) {
-			gap := int(nextTime.Sub(currentTime) / interval)
-			missingCount := gap - 1
-
-			if missingCount > 0 {
-				for j := 1; j <= missingCount; j++ {
-					ratio := float64(j) / float64(gap)
-					interpolatedTime := currentTime.Add(interval * time.Duration(j))
-
-					var interpolatedValues [6]float64
-					for k := 0; k < 6; k++ {
-						interpolatedValues[k] = records[i].Values[k] +
-							(records[i+1].Values[k]-records[i].Values[k])*ratio
-					}
-
-					_, err := c.db.Exec(fmt.Sprintf(`
-						INSERT OR REPLACE INTO bitcoin_%s
-						(timestamp, opening_price, high_price, low_price, trade_price,
-						 candle_acc_trade_volume, candle_acc_trade_price, is_interpolated)
-						VALUES (?, ?, ?, ?, ?, ?, ?, 1)
-					`, tf.Name),
-						interpolatedTime.Format("2006-01-02T15:04:05"),
-						interpolatedValues[0], interpolatedValues[1], interpolatedValues[2],
-						interpolatedValues[3], interpolatedValues[4], interpolatedValues[5])
-
-					if err == nil {
-						interpolatedCount++
-					}
-				}
+		current, err := parseKST(records[i].CandleDateTimeKST)
+		if err != nil {
+			continue
+		}
+		next, err := parseKST(records[i+1].CandleDateTimeKST)
+		if err != nil {
+			continue
+		}
+
+		var missing []time.Time
+		for t := candleEnd(tf, current); t.Before(next); t = candleEnd(tf, t) {
+			missing = append(missing, t)
+			if c.MaxInterpolationGap > 0 && len(missing) > c.MaxInterpolationGap {
+				break
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if c.MaxInterpolationGap > 0 && len(missing) > c.MaxInterpolationGap {
+			skippedGaps++
+			continue
+		}
+
+		gap := len(missing) + 1
+		for j, t := range missing {
+			candle := c.interpolatedCandle(records[i], records[i+1], t, float64(j+1)/float64(gap))
+
+			db, err := c.candleDBFor(candle.CandleDateTimeKST)
+			if err != nil {
+				continue
 			}
+
+			_, err = db.Exec(c.candleTableSchema().insertSQL("INSERT OR REPLACE", c.table(tf)), c.candleRow(&candle)...)
+
+			if err == nil {
+				interpolatedCount++
+			}
+		}
+	}
+
+	if interpolatedCount > 0 {
+		c.invalidateCache(tf)
+	}
+	if skippedGaps > 0 {
+		fmt.Fprintf(c.Output, "[%s] %s %d개 구간은 보간 한도(%d개) 초과로 비워 둠 (FindGaps 로 확인)\n",
+			tf.Name, c.mark(markWarn), skippedGaps, c.MaxInterpolationGap)
+	}
+
+	fmt.Fprintf(c.Output, "[%s] %s %d개 결측값 보간 완료\n", tf.Name, c.mark(markOK), interpolatedCount)
+	return interpolatedCount, nil
+}
+
+// interpolatedCandle - prev 와 next 사이 t 시각의 보간 캔들 (ratio 는 prev 부터의 비율, Interpolation 방식 반영)
+func (c *Collector) interpolatedCandle(prev, next Candle, t time.Time, ratio float64) Candle {
+	lerp := func(a, b float64) float64 { return a + (b-a)*ratio }
+	candle := Candle{
+		CandleDateTimeKST:    t.In(seoul).Format(timestampLayout),
+		OpeningPrice:         lerp(prev.OpeningPrice, next.OpeningPrice),
+		HighPrice:            lerp(prev.HighPrice, next.HighPrice),
+		LowPrice:             lerp(prev.LowPrice, next.LowPrice),
+		TradePrice:           lerp(prev.TradePrice, next.TradePrice),
+		CandleAccTradeVolume: lerp(prev.CandleAccTradeVolume, next.CandleAccTradeVolume),
+		CandleAccTradePrice:  lerp(prev.CandleAccTradePrice, next.CandleAccTradePrice),
+		IsInterpolated:       true,
+	}
+	if c.Interpolation == InterpolateZeroVolume {
+		candle.CandleAccTradeVolume, candle.CandleAccTradePrice = 0, 0
+	}
+	return candle
+}
+
+// CollectAll - 모든 시간단위 병렬 수집 후 보간, 시간단위별 결과 반환
+func (c *Collector) CollectAll() []CollectResult {
+	started := c.now()
+	fmt.Fprintln(c.Output, "\n"+"============================================================")
+	fmt.Fprintln(c.Output, c.mark(markLaunch)+" 업비트 비트코인 전체 데이터 수집 시작 (병렬 처리)")
+	fmt.Fprintf(c.Output, "   Rate Limit: 초당 %d회 (업비트 제한: 초당 10회)\n", c.rateLimiter.PerSecond())
+	fmt.Fprintln(c.Output, "============================================================")
+
+	if c.MaxRequests > 0 {
+		c.budget = newRequestBudget(c.MaxRequests)
+		defer func() { c.budget = nil }()
+		fmt.Fprintf(c.Output, "   API 요청 예산: %d회\n", c.MaxRequests)
+	}
+
+	results := make([]CollectResult, len(timeframes))
+	c.forEachTimeframe(func(i int, tf Timeframe) {
+		results[i] = c.collectAndInterpolate(tf)
+	})
+	c.retryFailedTimeframes(results)
+
+	fmt.Fprintln(c.Output, "\n"+"============================================================")
+	fmt.Fprintln(c.Output, c.mark(markDone)+" 모든 시간단위 데이터 수집 완료")
+	fmt.Fprintln(c.Output, "============================================================")
+
+	var timedOut []string
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 수집 중 오류: %v\n", r.Timeframe, c.mark(markFail), r.Err)
+		}
+		if r.TimedOut {
+			timedOut = append(timedOut, r.Timeframe)
+		}
+	}
+	if len(timedOut) > 0 {
+		fmt.Fprintf(c.Output, "%s 시간 제한(%v)으로 중단된 시간단위: %s\n", c.mark(markPause), c.PerTimeframeTimeout, strings.Join(timedOut, ", "))
+	}
+	if c.budget != nil {
+		fmt.Fprintf(c.Output, "\n%s API 요청 예산 사용 (%d / %d회):\n", c.mark(markStats), c.budget.used.Load(), c.MaxRequests)
+		for _, r := range results {
+			fmt.Fprintf(c.Output, "  %-10s %6d회 (%5.1f%%)\n", r.Timeframe, r.Requests,
+				float64(r.Requests)/float64(c.MaxRequests)*100)
+		}
+	}
+
+	if c.breaker != nil {
+		if state, trips := c.breaker.stats(); trips > 0 {
+			fmt.Fprintf(c.Output, "%s 회로 차단기 %d회 열림 (현재: %s)\n", c.mark(markWarn), trips, state)
+		}
+	}
+
+	c.PrintStatistics()
+
+	if c.SummaryPath != "" {
+		summary := c.newRunSummary(started, c.now(), results)
+		if err := writeJSONAtomic(c.SummaryPath, summary); err != nil {
+			fmt.Fprintf(c.Output, "%s 실행 요약 저장 실패 (%s): %v\n", c.mark(markWarn), c.SummaryPath, err)
 		}
 	}
+	if c.CompletionWebhook != "" {
+		c.notifyCompletion(c.newRunSummary(started, c.now(), results))
+	}
+	return results
+}
+
+// collectAndInterpolate - CollectAll 의 시간단위 하나 처리 (수집, 보간, StoreReturns)
+func (c *Collector) collectAndInterpolate(tf Timeframe) CollectResult {
+	result := c.collectTimeframe(tf)
+	if c.budget != nil {
+		result.Requests = c.budget.spentBy(tf.Name)
+	}
+	interpolated, err := c.interpolateMissingData(tf)
+	result.Interpolated = interpolated
+	if err != nil && result.Err == nil {
+		result.Err = fmt.Errorf("보간 실패: %w", err)
+	}
+	if c.StoreReturns {
+		if _, err := c.ComputeReturns(tf); err != nil && result.Err == nil {
+			result.Err = fmt.Errorf("수익률 계산 실패: %w", err)
+		}
+	}
+	return result
+}
+
+// retryFailedTimeframes - 오류로 끝난 시간단위만 FailedTimeframeRetries 번까지 다시 수집해 results 에 합침
+//
+// 실패한 위치는 체크포인트로 남아 있으므로 다시 수집하면 그 위치부터 이어진다. 다시 수집한 결과의
+// 페이지/저장 수는 첫 결과에 더하고, 오류는 마지막 시도의 것으로 바꾼다.
+func (c *Collector) retryFailedTimeframes(results []CollectResult) {
+	var recovered []string
+	for attempt := 1; attempt <= c.FailedTimeframeRetries; attempt++ {
+		var failed []int
+		for i, r := range results {
+			if r.Err != nil {
+				failed = append(failed, i)
+			}
+		}
+		if len(failed) == 0 {
+			break
+		}
 
-	fmt.Printf("[%s] ✓ %d개 결측값 보간 완료\n", tf.Name, interpolatedCount)
+		names := make([]string, len(failed))
+		targets := make([]Timeframe, len(failed))
+		for j, i := range failed {
+			names[j], targets[j] = timeframes[i].Name, timeframes[i]
+		}
+		fmt.Fprintf(c.Output, "\n%s 실패한 시간단위 다시 수집 (%d/%d): %s\n", c.mark(markWork),
+			attempt, c.FailedTimeframeRetries, strings.Join(names, ", "))
+
+		c.forEach(targets, func(j int, tf Timeframe) {
+			prev := &results[failed[j]]
+			retry := c.collectAndInterpolate(tf)
+			prev.Pages += retry.Pages
+			prev.Fetched += retry.Fetched
+			prev.Saved += retry.Saved
+			prev.Rejected += retry.Rejected
+			prev.Interpolated = retry.Interpolated
+			prev.Requests = retry.Requests
+			prev.TimedOut = retry.TimedOut
+			prev.Err = retry.Err
+			prev.Retries++
+		})
+		for j, i := range failed {
+			if results[i].Err == nil {
+				recovered = append(recovered, names[j])
+			}
+		}
+	}
+	if len(recovered) > 0 {
+		fmt.Fprintf(c.Output, "%s 다시 수집해 복구한 시간단위: %s\n", c.mark(markOK), strings.Join(recovered, ", "))
+	}
 }
 
-func (c *Collector) CollectAll() {
-	fmt.Println("\n" + "============================================================")
-	fmt.Println("🚀 업비트 비트코인 전체 데이터 수집 시작 (병렬 처리)")
-	fmt.Println("   Rate Limit: 초당 9회 (업비트 제한: 초당 10회)")
-	fmt.Println("============================================================")
+// forEachTimeframe - 모든 시간단위에 대해 fn 을 병렬 실행 (MaxConcurrency 로 동시 실행 수 제한)
+func (c *Collector) forEachTimeframe(fn func(i int, tf Timeframe)) {
+	c.forEach(timeframes, fn)
+}
 
+// forEach - tfs 의 각 시간단위에 대해 fn 을 병렬 실행 (i 는 tfs 안의 위치, MaxConcurrency 로 동시 실행 수 제한)
+func (c *Collector) forEach(tfs []Timeframe, fn func(i int, tf Timeframe)) {
+	c.syncOutput()
 	var wg sync.WaitGroup
+	var sem chan struct{}
+	if c.MaxConcurrency > 0 {
+		sem = make(chan struct{}, c.MaxConcurrency)
+	}
 
-	for _, tf := range timeframes {
+	for i, tf := range tfs {
 		wg.Add(1)
-		go c.collectTimeframe(tf, &wg)
+		go func(i int, tf Timeframe) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			fn(i, tf)
+		}(i, tf)
 	}
 
 	wg.Wait()
+}
 
-	fmt.Println("\n" + "============================================================")
-	fmt.Println("✅ 모든 시간단위 데이터 수집 완료")
-	fmt.Println("============================================================")
+// lockedWriter - 여러 goroutine 이 함께 쓰는 Output (Write 를 한 번에 하나씩 전달)
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
 
-	c.PrintStatistics()
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// syncOutput - Output 을 lockedWriter 로 감쌈 (이미 감쌌으면 그대로, goroutine 을 시작하기 전에 호출)
+func (c *Collector) syncOutput() {
+	if _, ok := c.Output.(*lockedWriter); !ok {
+		c.Output = &lockedWriter{w: c.Output}
+	}
+}
+
+// failedResults - 오류가 기록된 결과들을 하나의 오류로 합침
+func failedResults(results []CollectResult) error {
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Timeframe, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CollectSince - since 부터 현재까지 모든 시간단위 수집 (이번 실행 동안만 StopBefore = since, StopBeforeTimeframes 는 그대로 우선)
+func (c *Collector) CollectSince(since time.Time) []CollectResult {
+	prev := c.StopBefore
+	c.StopBefore = since
+	defer func() { c.StopBefore = prev }()
+
+	return c.CollectAll()
 }
 
 func (c *Collector) PrintStatistics() {
-	fmt.Println("\n📈 데이터 통계:")
-	fmt.Println("------------------------------------------------------------")
+	fmt.Fprintln(c.Output, "\n"+c.mark(markStats)+" 데이터 통계:")
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
 
 	for _, tf := range timeframes {
-		var total, original, interpolated int
-		var oldest, newest sql.NullString
-
-		err := c.db.QueryRow(fmt.Sprintf(`
-			SELECT
-				COUNT(*) as total,
-				SUM(CASE WHEN is_interpolated = 0 THEN 1 ELSE 0 END) as original,
-				SUM(CASE WHEN is_interpolated = 1 THEN 1 ELSE 0 END) as interpolated,
-				MIN(timestamp) as oldest,
-				MAX(timestamp) as newest
-			FROM bitcoin_%s
-		`, tf.Name)).Scan(&total, &original, &interpolated, &oldest, &newest)
-
-		if err != nil || total == 0 {
+		stats, err := c.timeframeStats(tf)
+		if err != nil || stats.Total == 0 {
 			continue
 		}
 
-		fmt.Printf("\n%s:\n", tf.Name)
-		fmt.Printf("  전체: %s개\n", formatNumber(total))
-		fmt.Printf("  원본: %s개\n", formatNumber(original))
-		fmt.Printf("  보간: %s개\n", formatNumber(interpolated))
-		if oldest.Valid && newest.Valid {
-			fmt.Printf("  기간: %s ~ %s\n", oldest.String, newest.String)
+		fmt.Fprintf(c.Output, "\n%s:\n", tf.Name)
+		fmt.Fprintf(c.Output, "  전체: %s개\n", formatNumber(stats.Total))
+		fmt.Fprintf(c.Output, "  원본: %s개\n", formatNumber(stats.Original))
+		fmt.Fprintf(c.Output, "  보간: %s개\n", formatNumber(stats.Interpolated))
+		if stats.Oldest != "" && stats.Newest != "" {
+			fmt.Fprintf(c.Output, "  기간: %s ~ %s\n", stats.Oldest, stats.Newest)
+		}
+	}
+}
+
+// TimeframeStats - 시간단위별 저장 현황
+type TimeframeStats struct {
+	Timeframe    string `json:"timeframe"`
+	Total        int    `json:"total"`
+	Original     int    `json:"original"`
+	Interpolated int    `json:"interpolated"`
+	Oldest       string `json:"oldest,omitempty"`
+	Newest       string `json:"newest,omitempty"`
+	Quality      *Score `json:"quality,omitempty"` // stats --json 에서만 채움 (QualityScore)
+}
+
+// Statistics - 모든 시간단위 저장 현황
+func (c *Collector) Statistics() ([]TimeframeStats, error) {
+	all := make([]TimeframeStats, 0, len(timeframes))
+	for _, tf := range timeframes {
+		stats, err := c.timeframeStats(tf)
+		if err != nil {
+			return nil, fmt.Errorf("%s 통계 조회 실패: %w", tf.Name, err)
+		}
+		all = append(all, stats)
+	}
+	return all, nil
+}
+
+// timeframeStats - 캔들 DB 전체(연도 분할 포함)를 합산한 저장 현황 (timeframe_summary 요약 행 사용)
+func (c *Collector) timeframeStats(tf Timeframe) (TimeframeStats, error) {
+	stats := TimeframeStats{Timeframe: tf.Name}
+	for _, db := range c.candleDBs() {
+		part, ok, err := c.summaryStats(db, tf)
+		if err == nil && !ok {
+			part, err = c.scanTimeframeStats(db, tf)
+		}
+		if err != nil {
+			return TimeframeStats{}, err
+		}
+
+		stats.Total += part.Total
+		stats.Original += part.Original
+		stats.Interpolated += part.Interpolated
+		if part.Oldest != "" && (stats.Oldest == "" || part.Oldest < stats.Oldest) {
+			stats.Oldest = part.Oldest
+		}
+		if part.Newest > stats.Newest {
+			stats.Newest = part.Newest
 		}
 	}
+	return stats, nil
+}
+
+// scanTimeframeStats - 한 DB 파일의 tf 테이블 전체 집계 (요약 행이 없을 때)
+func (c *Collector) scanTimeframeStats(db *sql.DB, tf Timeframe) (TimeframeStats, error) {
+	var stats TimeframeStats
+	var oldest, newest sql.NullString
+	err := db.QueryRow(fmt.Sprintf(`
+		SELECT
+			COUNT(*) as total,
+			COALESCE(SUM(CASE WHEN is_interpolated = 0 THEN 1 ELSE 0 END), 0) as original,
+			COALESCE(SUM(CASE WHEN is_interpolated = 1 THEN 1 ELSE 0 END), 0) as interpolated,
+			MIN(timestamp) as oldest,
+			MAX(timestamp) as newest
+		FROM %s
+	`, c.table(tf))).Scan(&stats.Total, &stats.Original, &stats.Interpolated, &oldest, &newest)
+	if err != nil {
+		return TimeframeStats{}, err
+	}
+	stats.Oldest, stats.Newest = oldest.String, newest.String
+	return stats, nil
 }
 
 func formatNumber(n int) string {
@@ -434,17 +1701,34 @@ func formatNumber(n int) string {
 	return result
 }
 
+// Close - WAL 체크포인트 후 DB 연결 종료 (여러 번 호출해도 한 번만 닫음)
 func (c *Collector) Close() error {
-	fmt.Println("\n✓ 데이터베이스 연결 종료")
+	c.closeOnce.Do(func() { c.closeErr = c.close() })
+	return c.closeErr
+}
+
+func (c *Collector) close() error {
+	if c.db == nil {
+		return nil
+	}
+	c.checkpointAll()
+	if !c.quiet {
+		fmt.Fprintln(c.Output, "\n"+c.mark(markOK)+" 데이터베이스 연결 종료")
+	}
+	if c.shards != nil {
+		if err := c.shards.close(); err != nil {
+			c.db.Close()
+			return err
+		}
+	}
 	return c.db.Close()
 }
 
 func main() {
-	collector, err := NewCollector("upbit_bitcoin.db")
-	if err != nil {
-		log.Fatal("데이터베이스 초기화 실패:", err)
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		log.Fatal(err)
 	}
-	defer collector.Close()
-
-	collector.CollectAll()
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// marketListTTL - ListMarkets 결과 캐시 유지 시간
+const marketListTTL = time.Minute
+
+// MarketInfo - 업비트 거래 가능 마켓 (GET /v1/market/all?isDetails=true)
+type MarketInfo struct {
+	Market      string   `json:"market"` // 예: KRW-BTC (호가 통화-종목)
+	KoreanName  string   `json:"korean_name"`
+	EnglishName string   `json:"english_name"`
+	Warning     bool     `json:"warning"`            // 투자유의 종목
+	Cautions    []string `json:"cautions,omitempty"` // 주의 사유 (PRICE_FLUCTUATIONS 등)
+}
+
+// Quote - 호가 통화 (KRW, BTC, USDT)
+func (m MarketInfo) Quote() string {
+	quote, _, _ := strings.Cut(m.Market, "-")
+	return quote
+}
+
+// marketAllEntry - /v1/market/all 응답 항목 (market_warning 은 예전 형식, market_event 는 새 형식)
+type marketAllEntry struct {
+	Market        string `json:"market"`
+	KoreanName    string `json:"korean_name"`
+	EnglishName   string `json:"english_name"`
+	MarketWarning string `json:"market_warning"`
+	MarketEvent   struct {
+		Warning bool            `json:"warning"`
+		Caution map[string]bool `json:"caution"`
+	} `json:"market_event"`
+}
+
+// marketList - 마지막 ListMarkets 결과 캐시
+type marketList struct {
+	mu      sync.Mutex
+	fetched time.Time
+	markets []MarketInfo
+}
+
+// ListMarkets - 업비트 전체 마켓 목록 (marketListTTL 동안은 캐시한 결과 반환)
+func (c *Collector) ListMarkets() ([]MarketInfo, error) {
+	c.markets.mu.Lock()
+	defer c.markets.mu.Unlock()
+	if c.markets.markets != nil && c.now().Sub(c.markets.fetched) < marketListTTL {
+		return c.markets.markets, nil
+	}
+
+	var entries []marketAllEntry
+	err := c.withRetry(context.Background(), "markets", func() error {
+		resp, err := c.httpClient.Get(c.marketsURL + "?isDetails=true")
+		if err != nil {
+			return err
+		}
+		defer drainAndClose(resp.Body)
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return decodeResponse(resp.StatusCode, body, &entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	markets := make([]MarketInfo, len(entries))
+	for i, e := range entries {
+		markets[i] = MarketInfo{
+			Market:      e.Market,
+			KoreanName:  e.KoreanName,
+			EnglishName: e.EnglishName,
+			Warning:     e.MarketWarning == "CAUTION" || e.MarketEvent.Warning,
+		}
+		for reason, on := range e.MarketEvent.Caution {
+			if on {
+				markets[i].Cautions = append(markets[i].Cautions, reason)
+			}
+		}
+		sort.Strings(markets[i].Cautions)
+	}
+	c.markets.markets, c.markets.fetched = markets, c.now()
+	return markets, nil
+}
+
+func runMarkets(args []string) error {
+	fs := flag.NewFlagSet("markets", flag.ContinueOnError)
+	quote := fs.String("quote", "", "호가 통화로 거르기 (예: KRW, BTC, USDT)")
+	plain := fs.Bool("plain", false, "이모지 없이 ASCII 접두어로 출력")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	collector, err := newFetcher(defaultMarket)
+	if err != nil {
+		return err
+	}
+	collector.PlainOutput = *plain
+
+	markets, err := collector.ListMarkets()
+	if err != nil {
+		return fmt.Errorf("마켓 목록 조회 실패: %w", err)
+	}
+
+	shown := 0
+	for _, m := range markets {
+		if *quote != "" && !strings.EqualFold(m.Quote(), *quote) {
+			continue
+		}
+		line := fmt.Sprintf("  %-14s %s (%s)", m.Market, m.KoreanName, m.EnglishName)
+		if m.Warning {
+			line += " " + collector.mark(markWarn) + "투자유의"
+		}
+		if len(m.Cautions) > 0 {
+			line += " [" + strings.Join(m.Cautions, ", ") + "]"
+		}
+		fmt.Println(line)
+		shown++
+	}
+	fmt.Printf("%s %d개 마켓 (--market 에 코드를 지정)\n", collector.mark(markOK), shown)
+	return nil
+}
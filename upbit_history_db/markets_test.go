@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const sampleMarkets = `[
+	{"market":"KRW-BTC","korean_name":"비트코인","english_name":"Bitcoin","market_warning":"NONE"},
+	{"market":"KRW-XYZ","korean_name":"엑스와이지","english_name":"Xyz","market_warning":"CAUTION"},
+	{"market":"BTC-ETH","korean_name":"이더리움","english_name":"Ethereum",
+	 "market_event":{"warning":false,"caution":{"TRADING_VOLUME_SOARING":true,"PRICE_FLUCTUATIONS":true,"DEPOSIT_AMOUNT_SOARING":false}}}
+]`
+
+func TestListMarkets(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Get("isDetails") != "true" {
+			t.Errorf("isDetails 없음: %s", r.URL.RawQuery)
+		}
+		io.WriteString(w, sampleMarkets)
+	}))
+	defer srv.Close()
+
+	c := openTestDB(t, "KRW-BTC")
+	c.marketsURL = srv.URL
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	markets, err := c.ListMarkets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MarketInfo{
+		{Market: "KRW-BTC", KoreanName: "비트코인", EnglishName: "Bitcoin"},
+		{Market: "KRW-XYZ", KoreanName: "엑스와이지", EnglishName: "Xyz", Warning: true},
+		{Market: "BTC-ETH", KoreanName: "이더리움", EnglishName: "Ethereum", Cautions: []string{"PRICE_FLUCTUATIONS", "TRADING_VOLUME_SOARING"}},
+	}
+	if !reflect.DeepEqual(markets, want) {
+		t.Errorf("markets = %+v, want %+v", markets, want)
+	}
+	if q := markets[2].Quote(); q != "BTC" {
+		t.Errorf("Quote = %q, want BTC", q)
+	}
+
+	// TTL 안에서는 캐시, 지나면 다시 조회
+	if _, err := c.ListMarkets(); err != nil || calls.Load() != 1 {
+		t.Errorf("캐시 조회 후 요청 %d회 (err %v), want 1", calls.Load(), err)
+	}
+	now = now.Add(marketListTTL)
+	if _, err := c.ListMarkets(); err != nil || calls.Load() != 2 {
+		t.Errorf("TTL 후 요청 %d회 (err %v), want 2", calls.Load(), err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewCollectorWaitsForTransientLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candles.db")
+	// 이전 실행이 아직 쓰기 트랜잭션을 잡고 있는 상황
+	other, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	tx, err := other.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("CREATE TABLE shutting_down (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	released := make(chan time.Time, 1)
+	time.AfterFunc(300*time.Millisecond, func() {
+		tx.Commit()
+		released <- time.Now()
+	})
+
+	c, err := NewCollector(path, "KRW-BTC")
+	if err != nil {
+		t.Fatalf("잠금이 풀린 뒤에도 실패: %v", err)
+	}
+	c.Output = io.Discard
+	defer c.Close()
+	opened := time.Now()
+	if at := <-released; opened.Before(at) {
+		t.Error("잠금이 풀리기 전에 초기화가 끝남 (잠금을 흉내내지 못함)")
+	}
+	var n int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'bitcoin_minute1'").Scan(&n); err != nil || n != 1 {
+		t.Errorf("캔들 테이블 없음 (n=%d, err=%v)", n, err)
+	}
+}
+
+func TestInitDatabaseCreatesRemainingTables(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	for _, tf := range timeframes {
+		if _, err := c.db.Exec("DROP TABLE " + c.table(tf)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 같은 이름의 뷰가 있으면 minute5 테이블만 만들 수 없음
+	if _, err := c.db.Exec("CREATE VIEW bitcoin_minute5 AS SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.initDatabase()
+	var dbErr *DBError
+	if !errors.As(err, &dbErr) || dbErr.Op != "init" || dbErr.Timeframe != "minute5" {
+		t.Fatalf("err = %v, want minute5 init DBError", err)
+	}
+	for _, tf := range timeframes {
+		var kind string
+		if err := c.db.QueryRow("SELECT type FROM sqlite_master WHERE name = ?", c.table(tf)).Scan(&kind); err != nil {
+			t.Errorf("%s: %v", c.table(tf), err)
+			continue
+		}
+		want := "table"
+		if tf.Name == "minute5" {
+			want = "view"
+		}
+		if kind != want {
+			t.Errorf("%s 종류 = %s, want %s", c.table(tf), kind, want)
+		}
+	}
+}
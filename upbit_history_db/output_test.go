@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCollectorOutputWriter(t *testing.T) {
+	c, _ := newTestCollector(t)
+	var out bytes.Buffer
+	c.Output = &out
+	c.MaxPages = 1
+
+	c.CollectAll()
+	c.PrintStatistics()
+	log := out.String()
+	for _, want := range []string{
+		"업비트 비트코인 전체 데이터 수집 시작",
+		"minute1 데이터 수집 시작",
+		"[minute1] " + c.mark(markOK) + " 총 200개 캔들 수집 및 저장 완료",
+		"모든 시간단위 데이터 수집 완료",
+		"데이터 통계:",
+		"  전체: 200개",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("출력에 %q 없음", want)
+		}
+	}
+}
+
+func TestCollectorDiscardOutputIsSilent(t *testing.T) {
+	c, _ := newTestCollector(t)
+	c.MaxPages = 1
+
+	// 표준 출력으로 새는 내용이 있는지 파이프로 확인
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	leaked := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		leaked <- string(data)
+	}()
+
+	c.CollectAll()
+	c.PrintStatistics()
+	os.Stdout = stdout
+	w.Close()
+	if s := <-leaked; s != "" {
+		t.Errorf("Output = io.Discard 인데 표준 출력에 씀:\n%s", s)
+	}
+}
+
+func TestForEachSerializesOutput(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+
+	// go test -race 로 실행하면 감싸지 않은 bytes.Buffer 동시 쓰기를 잡아냄
+	for run := 0; run < 2; run++ {
+		c.forEachTimeframe(func(i int, tf Timeframe) {
+			for j := 0; j < 50; j++ {
+				fmt.Fprintf(c.Output, "[%s] line %d\n", tf.Name, j)
+			}
+		})
+	}
+	if _, ok := c.Output.(*lockedWriter); !ok {
+		t.Fatalf("Output = %T, want *lockedWriter", c.Output)
+	}
+	if c.Output.(*lockedWriter).w != &out {
+		t.Error("lockedWriter 가 두 번 감싸짐")
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2*50*len(timeframes) {
+		t.Errorf("출력 %d줄, want %d", len(lines), 2*50*len(timeframes))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[") || !strings.Contains(line, "] line ") {
+			t.Fatalf("섞인 줄: %q", line)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CandleParser - 거래소 캔들 API 응답을 내부 Candle 로 변환 (저장 계층은 Candle 만 다룸)
+//
+// 반환하는 캔들은 업비트처럼 최신순이어야 하고 CandleDateTimeKST/UTC 는 timestampLayout 형식이어야 한다.
+type CandleParser interface {
+	ParseCandles(status int, body []byte) ([]Candle, error)
+}
+
+// UpbitParser - 업비트 캔들 응답 (Candle 의 json 태그, 오류 객체는 UpbitAPIError)
+type UpbitParser struct{}
+
+func (UpbitParser) ParseCandles(status int, body []byte) ([]Candle, error) {
+	return decodeCandles(status, body)
+}
+
+// BinanceKlineParser - 바이낸스식 kline 배열 응답
+//
+//	[[시작 시각(ms), "시가", "고가", "저가", "종가", "거래량", 마감 시각(ms), "거래대금", ...], ...]
+//
+// 오래된 순으로 오므로 최신순으로 뒤집는다. 응답에 마켓 코드가 없어 Market 을 채운다.
+type BinanceKlineParser struct {
+	Market string
+}
+
+func (p BinanceKlineParser) ParseCandles(status int, body []byte) ([]Candle, error) {
+	if status != http.StatusOK {
+		return nil, &HTTPStatusError{StatusCode: status}
+	}
+	var rows [][]json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("응답 해석 실패: %w", err)
+	}
+
+	candles := make([]Candle, len(rows))
+	for i, row := range rows {
+		if len(row) < 8 {
+			return nil, fmt.Errorf("kline %d: 필드가 %d개로 부족합니다", i, len(row))
+		}
+		var openTime int64
+		if err := json.Unmarshal(row[0], &openTime); err != nil {
+			return nil, fmt.Errorf("kline %d 시작 시각: %w", i, err)
+		}
+		var values [6]float64 // 시가, 고가, 저가, 종가, 거래량, 거래대금
+		for j, field := range []int{1, 2, 3, 4, 5, 7} {
+			v, err := parseKlineNumber(row[field])
+			if err != nil {
+				return nil, fmt.Errorf("kline %d 필드 %d: %w", i, field, err)
+			}
+			values[j] = v
+		}
+
+		utc := time.UnixMilli(openTime).UTC()
+		candle := &candles[len(rows)-1-i]
+		candle.Market = p.Market
+		candle.CandleDateTimeUTC = utc.Format(timestampLayout)
+		candle.CandleDateTimeKST = utc.Add(9 * time.Hour).Format(timestampLayout)
+		candle.OpeningPrice, candle.HighPrice, candle.LowPrice, candle.TradePrice = values[0], values[1], values[2], values[3]
+		candle.CandleAccTradeVolume, candle.CandleAccTradePrice = values[4], values[5]
+	}
+	return candles, nil
+}
+
+// parseKlineNumber - kline 숫자 필드 (문자열 "123.4" 또는 숫자)
+func parseKlineNumber(raw json.RawMessage) (float64, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strconv.ParseFloat(text, 64)
+	}
+	var v float64
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
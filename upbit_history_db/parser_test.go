@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// sampleKlines - 바이낸스 kline 응답 (오래된 순, 2024-01-01 00:00 / 00:01 UTC)
+const sampleKlines = `[
+	[1704067200000, "42283.58", "42298.62", "42261.02", "42284.17", "35.9241", 1704067259999, "1519066.05", 1197, "18.9", "799203.1", "0"],
+	[1704067260000, "42284.17", "42300.00", "42280.11", "42295.10", 12.5, 1704067319999, "528688.75", 634, "6.1", "258000.2", "0"]
+]`
+
+func TestBinanceKlineParser(t *testing.T) {
+	candles, err := BinanceKlineParser{Market: "BTCUSDT"}.ParseCandles(http.StatusOK, []byte(sampleKlines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Candle{
+		{
+			Market:            "BTCUSDT",
+			CandleDateTimeUTC: "2024-01-01T00:01:00",
+			CandleDateTimeKST: "2024-01-01T09:01:00",
+			OpeningPrice:      42284.17, HighPrice: 42300.00, LowPrice: 42280.11, TradePrice: 42295.10,
+			CandleAccTradeVolume: 12.5, CandleAccTradePrice: 528688.75,
+		},
+		{
+			Market:            "BTCUSDT",
+			CandleDateTimeUTC: "2024-01-01T00:00:00",
+			CandleDateTimeKST: "2024-01-01T09:00:00",
+			OpeningPrice:      42283.58, HighPrice: 42298.62, LowPrice: 42261.02, TradePrice: 42284.17,
+			CandleAccTradeVolume: 35.9241, CandleAccTradePrice: 1519066.05,
+		},
+	}
+	if len(candles) != len(want) {
+		t.Fatalf("캔들 %d개, want %d", len(candles), len(want))
+	}
+	for i := range want {
+		if candles[i] != want[i] {
+			t.Errorf("[%d] = %+v, want %+v (최신순)", i, candles[i], want[i])
+		}
+	}
+
+	var statusErr *HTTPStatusError
+	if _, err := (BinanceKlineParser{}).ParseCandles(http.StatusTooManyRequests, []byte(`{"code":-1003}`)); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("429 응답 err = %v, want HTTPStatusError", err)
+	}
+	for _, bad := range []string{`[[1704067200000, "1", "2", "3"]]`, `[["soon", "1", "2", "3", "4", "5", 0, "6"]]`, `[[0, "x", "2", "3", "4", "5", 0, "6"]]`} {
+		if _, err := (BinanceKlineParser{}).ParseCandles(http.StatusOK, []byte(bad)); err == nil {
+			t.Errorf("%s: 오류 없음", bad)
+		}
+	}
+}
+
+func TestCollectorUsesParser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, sampleKlines)
+	}))
+	defer srv.Close()
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	c.Parser = BinanceKlineParser{Market: "KRW-BTC"}
+
+	tf := mustTimeframe(t, "minute1")
+	candles, err := c.requestCandles(context.Background(), tf, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, _, err := c.saveCandles(tf, candles); err != nil || saved != 2 {
+		t.Fatalf("saved = %d, err = %v, want 2", saved, err)
+	}
+	if n := countRows(t, c, tf, "timestamp = ? AND trade_price = 42284.17", "2024-01-01T09:00:00"); n != 1 {
+		t.Errorf("바이낸스 캔들이 KST 09:00 으로 저장되지 않음")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// 요청 1회당 최대 캔들 수
+const candlesPerRequest = 200
+
+// TimeframePlan - 시간단위별 수집 예상치
+type TimeframePlan struct {
+	Timeframe  string    `json:"timeframe"`
+	StopBefore time.Time `json:"stop_before"` // 이 시간단위의 수집 하한 (StopBeforeTimeframes 반영)
+	Expected   int       `json:"expected"`    // StopBefore ~ 현재 구간의 예상 캔들 수
+	Existing   int       `json:"existing"`    // 같은 구간에 이미 저장된 실제 캔들 수
+	Missing    int       `json:"missing"`
+	Requests   int       `json:"requests"`
+}
+
+// CollectionPlan - 실제 요청 없이 계산한 수집 계획
+type CollectionPlan struct {
+	StopBefore        time.Time       `json:"stop_before"`
+	Timeframes        []TimeframePlan `json:"timeframes"`
+	TotalRequests     int             `json:"total_requests"`
+	EstimatedDuration time.Duration   `json:"estimated_duration"`
+}
+
+// PlanCollection - StopBefore 와 기존 데이터 기준으로 시간단위별 예상 요청 수 계산 (API 호출 없음)
+//
+// 요청당 200개 캔들을 가정하며, 새 데이터 확인을 위해 시간단위마다 최소 1회 요청으로 계산한다.
+// 예상 소요 시간은 모든 시간단위가 공유하는 rate limit 기준이다.
+func (c *Collector) PlanCollection() (CollectionPlan, error) {
+	plan := CollectionPlan{StopBefore: c.StopBefore}
+	nowKST := c.now().UTC().Add(9 * time.Hour)
+
+	for _, tf := range timeframes {
+		stop := c.stopBefore(tf)
+		existing := 0
+		for _, db := range c.candleDBsBetween(stop, time.Time{}) {
+			var count int
+			err := db.QueryRow(fmt.Sprintf(
+				"SELECT COUNT(*) FROM %s WHERE is_interpolated = 0 AND timestamp >= ?", c.table(tf)),
+				stop.Format(timestampLayout)).Scan(&count)
+			if err != nil {
+				return CollectionPlan{}, err
+			}
+			existing += count
+		}
+
+		tp := TimeframePlan{
+			Timeframe:  tf.Name,
+			StopBefore: stop,
+			Expected:   expectedCandles(tf, stop, nowKST),
+			Existing:   existing,
+		}
+		if tp.Missing = tp.Expected - tp.Existing; tp.Missing < 0 {
+			tp.Missing = 0
+		}
+		tp.Requests = (tp.Missing + candlesPerRequest - 1) / candlesPerRequest
+		if tp.Requests < 1 {
+			tp.Requests = 1
+		}
+		if c.MaxPages > 0 && tp.Requests > c.MaxPages {
+			tp.Requests = c.MaxPages
+		}
+
+		plan.Timeframes = append(plan.Timeframes, tp)
+		plan.TotalRequests += tp.Requests
+	}
+
+	plan.EstimatedDuration = time.Duration(plan.TotalRequests) * c.rateLimiter.minGap
+	return plan, nil
+}
+
+// expectedCandles - [from, to) 구간의 예상 캔들 수 (월봉은 달력 기준)
+func expectedCandles(tf Timeframe, from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	if tf.Name == "month" {
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+	return int(to.Sub(from)/(time.Duration(tf.Minutes)*time.Minute)) + 1
+}
+
+func (c *Collector) printPlan(plan CollectionPlan) {
+	fmt.Fprintln(c.Output, "\n"+c.mark(markStats)+" 수집 계획 (dry-run, API 호출 없음):")
+	fmt.Fprintf(c.Output, "  기준: %s 이후\n", plan.StopBefore.Format("2006-01-02"))
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  %-10s %12s %12s %12s %8s\n", "timeframe", "expected", "existing", "missing", "requests")
+	for _, tp := range plan.Timeframes {
+		fmt.Fprintf(c.Output, "  %-10s %12s %12s %12s %8s\n", tp.Timeframe,
+			formatNumber(tp.Expected), formatNumber(tp.Existing), formatNumber(tp.Missing), formatNumber(tp.Requests))
+	}
+	for _, tp := range plan.Timeframes {
+		if !tp.StopBefore.Equal(plan.StopBefore) {
+			fmt.Fprintf(c.Output, "  %-10s 기준: %s 이후\n", tp.Timeframe, tp.StopBefore.Format("2006-01-02"))
+		}
+	}
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	fmt.Fprintf(c.Output, "  총 요청: %s회, 예상 소요 시간: %v (초당 %d회 기준)\n",
+		formatNumber(plan.TotalRequests), plan.EstimatedDuration.Round(time.Second), c.rateLimiter.PerSecond())
+}
+
+// only - 한 시간단위만 남긴 계획
+func (p CollectionPlan) only(tf Timeframe) CollectionPlan {
+	filtered := CollectionPlan{StopBefore: p.StopBefore}
+	for _, tp := range p.Timeframes {
+		if tp.Timeframe == tf.Name {
+			filtered.Timeframes = append(filtered.Timeframes, tp)
+			filtered.TotalRequests += tp.Requests
+		}
+	}
+	if p.TotalRequests > 0 {
+		filtered.EstimatedDuration = p.EstimatedDuration * time.Duration(filtered.TotalRequests) / time.Duration(p.TotalRequests)
+	}
+	return filtered
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanCollectionAccountsForExistingRows(t *testing.T) {
+	c, f := newTestCollector(t)
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) // KST 09:00
+	c.now = func() time.Time { return now }
+	nowKST := now.Add(9 * time.Hour)
+	c.StopBefore = nowKST.Add(-1000 * time.Minute)
+	minute1 := mustTimeframe(t, "minute1")
+	seed(t, c, minute1, kstMinutes(c.StopBefore, 400)...)
+	// StopBefore 이전 캔들은 세지 않음
+	seed(t, c, minute1, c.StopBefore.Add(-time.Hour))
+
+	plan, err := c.PlanCollection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.calls.Load() != 0 {
+		t.Errorf("dry-run 인데 API 요청 %d회", f.calls.Load())
+	}
+	if len(plan.Timeframes) != len(timeframes) {
+		t.Fatalf("시간단위 %d개, want %d", len(plan.Timeframes), len(timeframes))
+	}
+
+	got := plan.Timeframes[0]
+	if got.Timeframe != "minute1" || got.Expected != 1001 || got.Existing != 400 || got.Missing != 601 || got.Requests != 4 {
+		t.Errorf("minute1 계획 = %+v, want expected 1001, existing 400, missing 601, requests 4", got)
+	}
+	// 빈 시간단위: minute60 은 1000분 구간에 17개 → 1회
+	if got := plan.Timeframes[6]; got.Timeframe != "minute60" || got.Existing != 0 || got.Requests != 1 {
+		t.Errorf("minute60 계획 = %+v", got)
+	}
+
+	total := 0
+	for _, tp := range plan.Timeframes {
+		total += tp.Requests
+	}
+	if plan.TotalRequests != total {
+		t.Errorf("total_requests = %d, 시간단위 합 %d", plan.TotalRequests, total)
+	}
+	if want := time.Duration(total) * c.rateLimiter.minGap; plan.EstimatedDuration != want {
+		t.Errorf("estimated_duration = %v, want %v", plan.EstimatedDuration, want)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// volumeProfileMaxMinutes - VolumeProfile 이 받는 가장 큰 시간단위 (그보다 크면 캔들이 여러 시간에 걸침)
+const volumeProfileMaxMinutes = 60
+
+// HourBucket - KST 시각(0~23시)별 평균 거래량
+type HourBucket struct {
+	Hour      int     `json:"hour"`
+	Candles   int     `json:"candles"`    // 평균에 쓴 실제 캔들 수
+	AvgVolume float64 `json:"avg_volume"` // candle_acc_trade_volume 평균 (캔들이 없으면 0)
+}
+
+// VolumeProfile - 실제(보간 아닌) tf 캔들을 KST 시각별로 묶은 평균 거래량 (항상 0~23시 24개)
+//
+// 캔들 1개가 한 시간 안에 들어가는 분 단위(minute60 이하) 시간단위만 지원한다.
+func (c *Collector) VolumeProfile(tf Timeframe) ([]HourBucket, error) {
+	if tf.Minutes > volumeProfileMaxMinutes {
+		return nil, fmt.Errorf("시간대별 거래량은 minute%d 이하 시간단위만 지원합니다: %s", volumeProfileMaxMinutes, tf.Name)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT CAST(strftime('%%H', timestamp) AS INTEGER) AS hour, COUNT(*), SUM(candle_acc_trade_volume)
+		FROM %s WHERE is_interpolated = 0 GROUP BY hour
+	`, c.table(tf))
+
+	buckets := make([]HourBucket, 24)
+	sums := make([]float64, 24)
+	for hour := range buckets {
+		buckets[hour].Hour = hour
+	}
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var hour, count int
+			var sum float64
+			if err := rows.Scan(&hour, &count, &sum); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if hour < 0 || hour > 23 {
+				continue
+			}
+			buckets[hour].Candles += count
+			sums[hour] += sum
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for hour := range buckets {
+		if buckets[hour].Candles > 0 {
+			buckets[hour].AvgVolume = sums[hour] / float64(buckets[hour].Candles)
+		}
+	}
+	return buckets, nil
+}
+
+// PriceBucket - 가격 구간별 누적 거래량 (Low 이상 High 미만, 마지막 구간은 High 포함)
+type PriceBucket struct {
+	Low     float64 `json:"low"`
+	High    float64 `json:"high"`
+	Candles int     `json:"candles"` // 대표가격이 이 구간에 든 실제 캔들 수
+	Volume  float64 `json:"volume"`  // candle_acc_trade_volume 합
+}
+
+// VolumeByPrice - from 이상 to 이하 실제(보간 아닌) tf 캔들의 거래량을 대표가격(고가+저가+종가)/3 구간별로 합산
+//
+// 대표가격 최저~최고를 buckets 개 같은 폭으로 나눈다. 캔들이 없으면 nil, 모든 대표가격이 같으면
+// 그 가격 하나짜리 구간 1개를 반환한다.
+func (c *Collector) VolumeByPrice(tf Timeframe, from, to time.Time, buckets int) ([]PriceBucket, error) {
+	if buckets < 1 {
+		return nil, fmt.Errorf("buckets 는 1 이상이어야 합니다: %d", buckets)
+	}
+	candles, err := c.GetCandles(tf, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var actual []Candle
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, candle := range candles {
+		if candle.IsInterpolated {
+			continue
+		}
+		actual = append(actual, candle)
+		lowest = math.Min(lowest, candle.TypicalPrice())
+		highest = math.Max(highest, candle.TypicalPrice())
+	}
+	if len(actual) == 0 {
+		return nil, nil
+	}
+	if highest == lowest {
+		buckets = 1
+	}
+
+	width := (highest - lowest) / float64(buckets)
+	result := make([]PriceBucket, buckets)
+	for i := range result {
+		result[i].Low = lowest + width*float64(i)
+		result[i].High = lowest + width*float64(i+1)
+	}
+	result[buckets-1].High = highest
+	for _, candle := range actual {
+		i := buckets - 1
+		if width > 0 {
+			i = min(int((candle.TypicalPrice()-lowest)/width), buckets-1)
+		}
+		result[i].Candles++
+		result[i].Volume += candle.CandleAccTradeVolume
+	}
+	return result, nil
+}
+
+func (c *Collector) printVolumeProfile(tf Timeframe, buckets []HourBucket) {
+	peak := 0.0
+	for _, b := range buckets {
+		peak = max(peak, b.AvgVolume)
+	}
+
+	fmt.Fprintf(c.Output, "\n%s %s 시간대별 평균 거래량 (%s, KST):\n", c.mark(markStats), tf.Name, c.market)
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	for _, b := range buckets {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("#", int(b.AvgVolume/peak*30))
+		}
+		fmt.Fprintf(c.Output, "  %02d시 %14.4f %10s개  %s\n", b.Hour, b.AvgVolume, formatNumber(b.Candles), bar)
+	}
+}
+
+func (c *Collector) printVolumeByPrice(tf Timeframe, buckets []PriceBucket) {
+	peak := 0.0
+	for _, b := range buckets {
+		peak = max(peak, b.Volume)
+	}
+
+	fmt.Fprintf(c.Output, "\n%s %s 가격대별 거래량 (%s):\n", c.mark(markStats), tf.Name, c.market)
+	fmt.Fprintln(c.Output, "------------------------------------------------------------")
+	for i := len(buckets) - 1; i >= 0; i-- {
+		b := buckets[i]
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("#", int(b.Volume/peak*30))
+		}
+		fmt.Fprintf(c.Output, "  %14.2f ~ %14.2f %14.4f %8s개  %s\n", b.Low, b.High, b.Volume, formatNumber(b.Candles), bar)
+	}
+}
+
+func runVolumeProfile(args []string) error {
+	fs := flag.NewFlagSet("volume-profile", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "minute60", "집계할 분 단위 시간단위 (minute1 ~ minute60)")
+	priceBuckets := fs.Int("price-buckets", 0, "시간대 대신 가격대를 이 개수로 나눠 거래량 합산 (0 = 시간대별)")
+	from := fs.String("from", "", "가격대별 집계 시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "가격대별 집계 종료 시각 (KST, 포함)")
+	asJSON := fs.Bool("json", false, "JSON 으로 출력")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	common.quiet = *asJSON
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	if *priceBuckets > 0 {
+		priced, err := collector.VolumeByPrice(tf, fromTime, toTime, *priceBuckets)
+		if err != nil {
+			return err
+		}
+		if !*asJSON {
+			collector.printVolumeByPrice(tf, priced)
+			return nil
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(priced)
+	}
+
+	buckets, err := collector.VolumeProfile(tf)
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		collector.printVolumeProfile(tf, buckets)
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buckets)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVolumeProfilePeakHour(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute30")
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 이틀치 30분 캔들, KST 22시만 거래량 10배 (22:30 은 35)
+	var candles []Candle
+	for i := 0; i < 96; i++ {
+		kst := t0.Add(time.Duration(i) * 30 * time.Minute)
+		candle := testCandle(kst, 100)
+		candle.CandleAccTradeVolume = 10
+		if kst.Hour() == 22 {
+			candle.CandleAccTradeVolume = 100
+			if kst.Minute() == 30 {
+				candle.CandleAccTradeVolume = 35
+			}
+		}
+		candles = append(candles, candle)
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+	// 보간 캔들은 거래량이 커도 제외
+	if _, err := c.db.Exec("UPDATE bitcoin_minute30 SET is_interpolated = 1, candle_acc_trade_volume = 1000 WHERE timestamp = ?",
+		t0.Add(3*time.Hour).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := c.VolumeProfile(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 24 {
+		t.Fatalf("%d개, want 24", len(buckets))
+	}
+	peak := buckets[0]
+	for hour, b := range buckets {
+		if b.Hour != hour {
+			t.Errorf("[%d] hour = %d", hour, b.Hour)
+		}
+		if b.AvgVolume > peak.AvgVolume {
+			peak = b
+		}
+	}
+	if peak.Hour != 22 || peak.Candles != 4 || !closeTo(peak.AvgVolume, 67.5, 1e-9) {
+		t.Errorf("최대 거래량 시간대 = %+v, want 22시 캔들 4개 평균 67.5", peak)
+	}
+	if b := buckets[3]; b.Candles != 3 || b.AvgVolume != 10 {
+		t.Errorf("3시 = %+v, want 보간 제외 캔들 3개 평균 10", b)
+	}
+
+	if _, err := c.VolumeProfile(mustTimeframe(t, "day")); err == nil {
+		t.Error("day 시간단위에 오류 없음")
+	}
+}
+
+func TestVolumeByPrice(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	times := kstMinutes(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 15)
+	// 대표가격 100~109 에 거래량 1씩, 뒤 5개는 105 에 거래량 50씩 몰림
+	var candles []Candle
+	for i, ts := range times {
+		candle := testCandle(ts, 100+float64(i))
+		if i >= 10 {
+			candle = testCandle(ts, 105)
+			candle.CandleAccTradeVolume = 50
+		}
+		candles = append(candles, candle)
+	}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := c.VolumeByPrice(tf, time.Time{}, time.Time{}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 5 || buckets[0].Low != 100 || buckets[4].High != 109 {
+		t.Fatalf("buckets = %+v, want 100~109 를 5개로", buckets)
+	}
+	peak, total := 0, 0.0
+	for i, b := range buckets {
+		total += b.Volume
+		if b.Volume > buckets[peak].Volume {
+			peak = i
+		}
+	}
+	// 폭 1.8: 세 번째 구간 [103.6, 105.4) 에 104, 105 와 몰린 5개
+	if b := buckets[peak]; peak != 2 || b.Candles != 7 || b.Volume != 252 {
+		t.Errorf("최대 거래량 구간 [%d] = %+v, want [2] 캔들 7개 거래량 252", peak, b)
+	}
+	if total != 260 {
+		t.Errorf("거래량 합 %v, want 260", total)
+	}
+
+	// 한 가격만 있으면 구간 하나, 빈 범위는 nil
+	single, err := c.VolumeByPrice(tf, times[10], times[14], 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(single) != 1 || single[0] != (PriceBucket{Low: 105, High: 105, Candles: 5, Volume: 250}) {
+		t.Errorf("단일 가격 = %+v", single)
+	}
+	empty, err := c.VolumeByPrice(tf, times[14].Add(time.Hour), time.Time{}, 5)
+	if err != nil || empty != nil {
+		t.Errorf("빈 범위 = %+v, %v, want nil", empty, err)
+	}
+	if _, err := c.VolumeByPrice(tf, time.Time{}, time.Time{}, 0); err == nil {
+		t.Error("buckets 0 에 오류 없음")
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "time"
+
+// ProgressEvent - 시간단위 수집 진행 상황 (페이지 저장마다, 그리고 수집 종료 시 Done=true 로 한 번)
+type ProgressEvent struct {
+	Timeframe string
+	Pages     int
+	Fetched   int
+	Saved     int
+	Newest    string // 이번 실행에서 받은 가장 최신 캔들 (KST)
+	Oldest    string // 지금까지 내려간 가장 과거 캔들 (KST)
+	Done      bool
+	Err       error
+	At        time.Time
+}
+
+// emitProgress - Progress 채널이 설정된 경우에만 전송 (받는 쪽이 느리면 수집도 기다린다)
+func (c *Collector) emitProgress(ev ProgressEvent) {
+	if c.Progress == nil {
+		return
+	}
+	ev.At = time.Now()
+	c.Progress <- ev
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollectTimeframeEmitsProgress(t *testing.T) {
+	c, f := newTestCollector(t)
+	f.floor = f.head.Add(-449 * time.Minute) // 450개 → 3페이지
+	events := make(chan ProgressEvent, 16)
+	c.Progress = events
+
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	close(events)
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	var pages []ProgressEvent
+	var done []ProgressEvent
+	for ev := range events {
+		if ev.Timeframe != "minute1" || ev.At.IsZero() {
+			t.Errorf("잘못된 이벤트 %+v", ev)
+		}
+		if ev.Done {
+			done = append(done, ev)
+		} else {
+			pages = append(pages, ev)
+		}
+	}
+	if len(done) != 1 || done[0].Saved != result.Saved || done[0].Pages != result.Pages {
+		t.Fatalf("완료 이벤트 %+v, want 한 번 (결과 %+v)", done, result)
+	}
+	if len(pages) == 0 || len(pages) > result.Pages {
+		t.Fatalf("페이지 이벤트 %d개, 페이지 %d", len(pages), result.Pages)
+	}
+	for i := 1; i < len(pages); i++ {
+		if pages[i].Saved < pages[i-1].Saved || pages[i].Oldest > pages[i-1].Oldest {
+			t.Errorf("진행이 뒤로 감: %+v → %+v", pages[i-1], pages[i])
+		}
+	}
+	if last := pages[len(pages)-1]; last.Newest == "" || last.Saved != result.Saved {
+		t.Errorf("마지막 페이지 이벤트 = %+v, 저장 %d", last, result.Saved)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressBarWidth - 진행 막대 칸 수
+const progressBarWidth = 24
+
+// isTerminal - 파일이 터미널(문자 장치)인지
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressBar - collect --progress 의 여러 줄 진행 표시 (수집 중인 시간단위마다 한 줄)
+//
+// 수집 로그도 이 writer 로 받아 막대를 지운 뒤 로그를 쓰고 막대를 다시 그린다.
+// 끝난 시간단위는 막대에서 빠진다 (완료/실패는 수집 로그에 이미 남는다).
+type progressBar struct {
+	mu     sync.Mutex
+	out    io.Writer
+	c      *Collector
+	order  []string                 // 처음 이벤트가 온 순서
+	active map[string]ProgressEvent // 수집 중인 시간단위의 마지막 이벤트
+	drawn  int                      // 화면에 그려진 막대 줄 수
+}
+
+func newProgressBar(out io.Writer, c *Collector) *progressBar {
+	return &progressBar{out: out, c: c, active: make(map[string]ProgressEvent)}
+}
+
+// Write - 로그 출력 (막대 위에 쓰고 막대를 다시 그림)
+func (b *progressBar) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var buf bytes.Buffer
+	b.clear(&buf)
+	buf.Write(p)
+	if len(p) > 0 && p[len(p)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	b.draw(&buf)
+	if _, err := b.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// update - 진행 이벤트 반영 후 다시 그림
+func (b *progressBar) update(ev ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var buf bytes.Buffer
+	b.clear(&buf)
+	if _, ok := b.active[ev.Timeframe]; !ok && !ev.Done {
+		b.order = append(b.order, ev.Timeframe)
+	}
+	if ev.Done {
+		delete(b.active, ev.Timeframe)
+		b.removeOrder(ev.Timeframe)
+	} else {
+		b.active[ev.Timeframe] = ev
+	}
+	b.draw(&buf)
+	b.out.Write(buf.Bytes())
+}
+
+func (b *progressBar) removeOrder(name string) {
+	for i, n := range b.order {
+		if n == name {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// clear - 그려 둔 막대 줄을 지움 (ANSI: 커서를 첫 줄로 올린 뒤 화면 끝까지 지우기)
+func (b *progressBar) clear(buf *bytes.Buffer) {
+	if b.drawn > 0 {
+		fmt.Fprintf(buf, "\x1b[%dA\r\x1b[J", b.drawn)
+	}
+	b.drawn = 0
+}
+
+func (b *progressBar) draw(buf *bytes.Buffer) {
+	for _, name := range b.order {
+		buf.WriteString("\r" + b.line(b.active[name]) + "\n")
+	}
+	b.drawn = len(b.order)
+}
+
+// line - "  minute1   [#######.........]  31%  12,345개  ~ 2024-01-01T00:00:00"
+func (b *progressBar) line(ev ProgressEvent) string {
+	oldest := ev.Oldest
+	if oldest == "" {
+		oldest = "-"
+	}
+	bar, percent := strings.Repeat(".", progressBarWidth), "   -"
+	if tf, err := findTimeframe(ev.Timeframe); err == nil {
+		if frac, ok := b.fraction(tf, ev.Oldest); ok {
+			filled := int(frac * progressBarWidth)
+			bar = strings.Repeat("#", filled) + strings.Repeat(".", progressBarWidth-filled)
+			percent = fmt.Sprintf("%3.0f%%", frac*100)
+		}
+	}
+	return fmt.Sprintf("  %-9s [%s] %s  %10s개  ~ %s", ev.Timeframe, bar, percent, formatNumber(ev.Saved), oldest)
+}
+
+// fraction - 현재부터 StopBefore 까지 중 oldest 까지 내려간 비율 (0~1)
+func (b *progressBar) fraction(tf Timeframe, oldest string) (float64, bool) {
+	stop := b.c.stopBefore(tf)
+	t, err := time.Parse(timestampLayout, oldest)
+	if stop.IsZero() || err != nil {
+		return 0, false
+	}
+	nowKST := b.c.now().UTC().Add(9 * time.Hour)
+	total := nowKST.Sub(stop)
+	if total <= 0 {
+		return 1, true
+	}
+	return min(max(float64(nowKST.Sub(t))/float64(total), 0), 1), true
+}
+
+// withProgressBar - 터미널이면 fn 실행 동안 진행 막대를 표시 (아니면 fn 만 실행, 일반 로그 그대로)
+func (c *Collector) withProgressBar(fn func()) {
+	if !isTerminal(os.Stdout) {
+		fn()
+		return
+	}
+
+	events := make(chan ProgressEvent, len(timeframes)*4)
+	bar := newProgressBar(c.Output, c)
+	logs := c.Output
+	c.Progress, c.Output = events, bar
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range events {
+			bar.update(ev)
+		}
+	}()
+
+	fn()
+	c.Progress = nil
+	close(events)
+	<-done
+	c.Output = logs
+}
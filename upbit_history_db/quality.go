@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// qualityAnomalyZ - QualityScore 가 이상치로 세는 이동 중앙값 편차 기준 (anomalies 기본값과 같음)
+const qualityAnomalyZ = 10
+
+// qualityAnomalyPenalty - 이상치 비율 1%p 당 깎는 이상치 점수 (이상치는 드물어서 비율 그대로면 큰 테이블에서 점수가 거의 안 움직임)
+//
+// 캔들 1만 개에 이상치 1개(0.01%)면 99점, 0.1% 면 90점, 1% 이상이면 0점이다.
+const qualityAnomalyPenalty = 100
+
+// QualityWeights - QualityScore 구성 요소별 가중치 (합으로 나누므로 비율만 의미 있음)
+type QualityWeights struct {
+	Coverage      float64 `json:"coverage"`
+	Interpolation float64 `json:"interpolation"`
+	Anomaly       float64 `json:"anomaly"`
+	Validity      float64 `json:"validity"`
+}
+
+// DefaultQualityWeights - 커버리지 40, 보간/이상치/OHLC 유효성 각 20
+func DefaultQualityWeights() QualityWeights {
+	return QualityWeights{Coverage: 40, Interpolation: 20, Anomaly: 20, Validity: 20}
+}
+
+func (w QualityWeights) validate() error {
+	for _, v := range []float64{w.Coverage, w.Interpolation, w.Anomaly, w.Validity} {
+		if v < 0 {
+			return fmt.Errorf("가중치는 0 이상이어야 합니다: %v", v)
+		}
+	}
+	if w.Coverage+w.Interpolation+w.Anomaly+w.Validity == 0 {
+		return fmt.Errorf("가중치 합이 0 입니다")
+	}
+	return nil
+}
+
+// parseQualityWeights - "40,20,20,20" 형식 (커버리지, 보간, 이상치, 유효성 순)
+func parseQualityWeights(text string) (QualityWeights, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 4 {
+		return QualityWeights{}, fmt.Errorf("가중치 4개가 필요합니다 (커버리지,보간,이상치,유효성): %q", text)
+	}
+	var values [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return QualityWeights{}, fmt.Errorf("잘못된 가중치 %q: %w", part, err)
+		}
+		values[i] = v
+	}
+	w := QualityWeights{Coverage: values[0], Interpolation: values[1], Anomaly: values[2], Validity: values[3]}
+	return w, w.validate()
+}
+
+// QualityComponents - 구성 요소별 0~100 점수
+type QualityComponents struct {
+	Coverage      float64 `json:"coverage"`      // 첫~마지막 캔들 구간에서 저장된 캔들 비율
+	Interpolation float64 `json:"interpolation"` // 100 - 보간 캔들 비율
+	Anomaly       float64 `json:"anomaly"`       // 100 - 이상치 캔들 비율(%) × qualityAnomalyPenalty (최저 0)
+	Validity      float64 `json:"validity"`      // OHLC 관계(저가 ≤ 시가/종가 ≤ 고가, 가격 > 0)가 맞는 캔들 비율
+}
+
+// Score - QualityScore 결과 (Score 는 구성 요소 점수의 가중 평균)
+type Score struct {
+	Score       float64           `json:"score"`
+	Components  QualityComponents `json:"components"`
+	Weights     QualityWeights    `json:"weights"`
+	Candles     int               `json:"candles"`
+	Missing     int               `json:"missing"`
+	Anomalies   int               `json:"anomalies"`
+	InvalidOHLC int               `json:"invalid_ohlc"`
+}
+
+// QualityScore - tf 데이터 품질을 커버리지, 보간 비율, 이상치 수, OHLC 유효성으로 0~100 점수화 (읽기 전용)
+//
+// 비율은 모두 coverage 와 같은 예상 캔들 수(첫~마지막 캔들 구간) 또는 저장된 캔들 수 기준이며, 가중치는
+// QualityWeights 설정을 쓴다. 캔들이 없으면 0 점이다. 이상치는 DetectAnomalies 와 같은 방식으로
+// qualityAnomalyZ 를 기준으로 센다.
+func (c *Collector) QualityScore(tf Timeframe) (Score, error) {
+	weights := c.QualityWeights
+	score := Score{Weights: weights}
+	if err := weights.validate(); err != nil {
+		return score, err
+	}
+
+	cov, err := c.coverage(tf)
+	if err != nil {
+		return score, err
+	}
+	if cov.Expected == 0 {
+		return score, nil
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		return score, err
+	}
+	if len(candles) == 0 {
+		return score, nil
+	}
+
+	for _, candle := range candles {
+		if !candle.validOHLC() {
+			score.InvalidOHLC++
+		}
+	}
+	score.Candles = len(candles)
+	score.Missing = cov.Missing
+	score.Anomalies = len(findAnomalies(candles, qualityAnomalyZ))
+
+	n := float64(len(candles))
+	score.Components = QualityComponents{
+		Coverage:      cov.CoveragePct,
+		Interpolation: 100 - cov.InterpolatedPct,
+		Anomaly:       math.Max(0, 100-float64(score.Anomalies)/n*100*qualityAnomalyPenalty),
+		Validity:      100 - float64(score.InvalidOHLC)/n*100,
+	}
+	total := weights.Coverage + weights.Interpolation + weights.Anomaly + weights.Validity
+	score.Score = (weights.Coverage*score.Components.Coverage +
+		weights.Interpolation*score.Components.Interpolation +
+		weights.Anomaly*score.Components.Anomaly +
+		weights.Validity*score.Components.Validity) / total
+	return score, nil
+}
+
+// validOHLC - 가격이 양수이고 저가 ≤ 시가/종가 ≤ 고가인지 (NaN/Inf 는 유효하지 않음)
+func (c Candle) validOHLC() bool {
+	if !c.finite() || c.LowPrice <= 0 {
+		return false
+	}
+	return c.LowPrice <= c.HighPrice &&
+		c.LowPrice <= c.OpeningPrice && c.OpeningPrice <= c.HighPrice &&
+		c.LowPrice <= c.TradePrice && c.TradePrice <= c.HighPrice
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQualityScoreMovesWithGapsAndAnomalies(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.QualityWeights = DefaultQualityWeights()
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, kstMinutes(t0, 10000)...)
+
+	clean := qualityScore(t, c, tf)
+	if clean.Score != 100 || clean.Anomalies != 0 || clean.Missing != 0 {
+		t.Fatalf("깨끗한 데이터 점수 = %+v, want 100", clean)
+	}
+
+	// 캔들 1만 개 중 100개(1%) 빈 구간
+	if _, err := c.db.Exec("DELETE FROM bitcoin_minute1 WHERE timestamp >= ? AND timestamp < ?",
+		t0.Add(5000*time.Minute).Format(timestampLayout), t0.Add(5100*time.Minute).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.invalidateCache(tf)
+	gapped := qualityScore(t, c, tf)
+	if gapped.Missing != 100 {
+		t.Errorf("missing = %d, want 100", gapped.Missing)
+	}
+	if gapped.Components.Coverage >= clean.Components.Coverage || gapped.Score >= clean.Score {
+		t.Errorf("빈 구간 후 점수 %.2f (coverage %.2f) 가 내려가지 않음", gapped.Score, gapped.Components.Coverage)
+	}
+
+	// 0.05% 튀는 값: 비율 그대로라면 이상치 점수는 99.95 로 거의 안 움직임
+	for _, minute := range []int{1000, 2000, 3000, 4000, 6000} {
+		if _, err := c.db.Exec(`UPDATE bitcoin_minute1 SET opening_price = opening_price * 10, high_price = high_price * 10,
+			low_price = low_price * 10, trade_price = trade_price * 10 WHERE timestamp = ?`,
+			t0.Add(time.Duration(minute)*time.Minute).Format(timestampLayout)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.invalidateCache(tf)
+	spiked := qualityScore(t, c, tf)
+	if spiked.Anomalies != 5 {
+		t.Errorf("anomalies = %d, want 5", spiked.Anomalies)
+	}
+	if spiked.Components.Anomaly > 96 || spiked.Score >= gapped.Score {
+		t.Errorf("이상치 5개 후 이상치 점수 %.2f, 전체 %.2f (이전 %.2f)", spiked.Components.Anomaly, spiked.Score, gapped.Score)
+	}
+	if spiked.Components.Validity != 100 {
+		t.Errorf("validity = %.2f, want 100", spiked.Components.Validity)
+	}
+}
+
+func qualityScore(t *testing.T, c *Collector, tf Timeframe) Score {
+	t.Helper()
+	score, err := c.QualityScore(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return score
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetCandles - 저장된 캔들을 시간 오름차순으로 조회 (from 이상 to 이하, zero 값이면 해당 방향 제한 없음)
+func (c *Collector) GetCandles(tf Timeframe, from, to time.Time) ([]Candle, error) {
+	key := candleCacheKey{
+		table:  c.table(tf),
+		market: c.market,
+		from:   from.Format(timestampLayout),
+		to:     to.Format(timestampLayout),
+		real:   c.ExcludeInterpolated,
+	}
+	if c.cache != nil {
+		if candles, ok := c.cache.get(key); ok {
+			return candles, nil
+		}
+	}
+
+	candles, err := c.queryCandles(tf, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		c.cache.put(key, candles)
+	}
+	return candles, nil
+}
+
+// DisplayCandles - GetCandles 결과의 timestamp 를 Location 시간대로 변환해 반환 (표시용, from/to 는 KST)
+//
+// 변환한 캔들은 KST 로 해석하는 함수(지표, 보간 등)에 다시 넣지 않는다.
+func (c *Collector) DisplayCandles(tf Timeframe, from, to time.Time) ([]Candle, error) {
+	candles, err := c.GetCandles(tf, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return c.localize(candles), nil
+}
+
+// localize - Location 이 있으면 CandleDateTimeKST 를 그 시간대 RFC3339 문자열로 바꾼 복사본 (원본/캐시는 그대로)
+func (c *Collector) localize(candles []Candle) []Candle {
+	if c.Location == nil {
+		return candles
+	}
+	out := make([]Candle, len(candles))
+	for i, candle := range candles {
+		if t, err := parseKST(candle.CandleDateTimeKST); err == nil {
+			candle.CandleDateTimeKST = t.In(c.Location).Format(time.RFC3339)
+		}
+		out[i] = candle
+	}
+	return out
+}
+
+// CommonRange - tfs 가 모두 데이터를 가진 구간 (각 시간단위 [가장 오래된, 가장 최근] 캔들 시각의 교집합, KST)
+//
+// 여러 시간단위를 함께 분석할 때 GetCandles(tf, start, end) 로 같은 구간만 가져와 한쪽만 늦게 시작하는
+// 가장자리 효과를 없앤다. 데이터가 없는 시간단위가 있거나 구간이 겹치지 않으면 오류를 반환한다.
+func (c *Collector) CommonRange(tfs []Timeframe) (start, end time.Time, err error) {
+	if len(tfs) == 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("시간단위가 필요합니다")
+	}
+	for i, tf := range tfs {
+		stats, err := c.timeframeStats(tf)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if stats.Total == 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("%s 에 저장된 캔들이 없습니다", tf.Name)
+		}
+		oldest, err := time.Parse(timestampLayout, stats.Oldest)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%s 잘못된 timestamp %q: %w", tf.Name, stats.Oldest, err)
+		}
+		newest, err := time.Parse(timestampLayout, stats.Newest)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%s 잘못된 timestamp %q: %w", tf.Name, stats.Newest, err)
+		}
+		if i == 0 || oldest.After(start) {
+			start = oldest
+		}
+		if i == 0 || newest.Before(end) {
+			end = newest
+		}
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("시간단위 구간이 겹치지 않습니다 (가장 늦은 시작 %s, 가장 이른 끝 %s)",
+			start.Format(timestampLayout), end.Format(timestampLayout))
+	}
+	return start, end, nil
+}
+
+// queryCandles - GetCandles 와 같은 조건으로 최대 limit 개 조회 (limit <= 0 이면 전체, 캐시 미사용)
+func (c *Collector) queryCandles(tf Timeframe, from, to time.Time, limit int) ([]Candle, error) {
+	c.queries.Add(1)
+	var where []string
+	var args []interface{}
+	switch {
+	case c.epoch:
+		// 정수 인덱스로 범위 비교 (문자열 비교와 같은 결과)
+		if !from.IsZero() {
+			where = append(where, "timestamp_ms >= ?")
+			args = append(args, queryMillis(from))
+		}
+		if !to.IsZero() {
+			where = append(where, "timestamp_ms <= ?")
+			args = append(args, queryMillis(to))
+		}
+	default:
+		if !from.IsZero() {
+			where = append(where, "timestamp >= ?")
+			args = append(args, from.Format(timestampLayout))
+		}
+		if !to.IsZero() {
+			where = append(where, "timestamp <= ?")
+			args = append(args, to.Format(timestampLayout))
+		}
+	}
+	if c.ExcludeInterpolated {
+		where = append(where, "is_interpolated = 0")
+	}
+
+	query := candleSchema.selectSQL(c.table(tf))
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY timestamp ASC"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	// 연도 분할 시 연도 오름차순 파일을 이어 붙이면 전체가 시간 오름차순
+	var candles []Candle
+	for _, db := range c.candleDBsBetween(from, to) {
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		part, err := c.scanCandles(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, part...)
+		if limit > 0 && len(candles) >= limit {
+			return candles[:limit], nil
+		}
+	}
+	return candles, nil
+}
+
+// RecentCandles - 가장 최근 n 개 캔들을 시간 오름차순으로 조회 (저장된 수가 적으면 전부)
+func (c *Collector) RecentCandles(tf Timeframe, n int) ([]Candle, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("n 은 1 이상이어야 합니다: %d", n)
+	}
+
+	query := candleSchema.selectSQL(c.table(tf))
+	if c.ExcludeInterpolated {
+		query += " WHERE is_interpolated = 0"
+	}
+	query += " ORDER BY timestamp DESC LIMIT ?"
+
+	// 연도 분할 시 최신 연도 파일부터 필요한 만큼만 조회
+	dbs := c.candleDBs()
+	var candles []Candle
+	for i := len(dbs) - 1; i >= 0 && len(candles) < n; i-- {
+		rows, err := dbs[i].Query(query, n-len(candles))
+		if err != nil {
+			return nil, err
+		}
+		part, err := c.scanCandles(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, part...)
+	}
+
+	for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
+		candles[i], candles[j] = candles[j], candles[i]
+	}
+	return candles, nil
+}
+
+// scanCandles - candleSchema.selectSQL 조회 결과를 Candle 로 변환
+func (c *Collector) scanCandles(rows *sql.Rows) ([]Candle, error) {
+	var candles []Candle
+	for rows.Next() {
+		candle := Candle{Market: c.market}
+		if err := rows.Scan(candleFields(&candle)...); err != nil {
+			return nil, err
+		}
+		if kst, err := time.Parse(timestampLayout, candle.CandleDateTimeKST); err == nil {
+			candle.CandleDateTimeUTC = kst.Add(-9 * time.Hour).Format(timestampLayout)
+		}
+		candles = append(candles, candle)
+	}
+	return candles, rows.Err()
+}
+
+// Gap - 저장된 캔들 사이의 빈 구간 (Start ~ End 가 비어 있는 timestamp)
+type Gap struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Missing int    `json:"missing"`
+}
+
+// FindGaps - 보간 캔들을 포함해 저장된 캔들 사이에 비어 있는 구간 조회
+func (c *Collector) FindGaps(tf Timeframe) ([]Gap, error) {
+	return c.findGaps(tf, false)
+}
+
+// findGaps - FindGaps (realOnly 면 보간 캔들도 빈 것으로 보고 실제 캔들 사이 구간 조회)
+func (c *Collector) findGaps(tf Timeframe, realOnly bool) ([]Gap, error) {
+	var gaps []Gap
+	var prev time.Time
+
+	query := fmt.Sprintf("SELECT timestamp FROM %s ORDER BY timestamp ASC", c.table(tf))
+	if realOnly {
+		query = fmt.Sprintf("SELECT timestamp FROM %s WHERE is_interpolated = 0 ORDER BY timestamp ASC", c.table(tf))
+	}
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var ts string
+			if err := rows.Scan(&ts); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			current, err := time.Parse(timestampLayout, ts)
+			if err != nil {
+				continue
+			}
+
+			if !prev.IsZero() {
+				// 월봉은 달마다 길이가 다르므로 보간과 같이 candleEnd 로 한 칸씩 진행
+				start, last, missing := candleEnd(tf, prev), time.Time{}, 0
+				for t := start; t.Before(current); t = candleEnd(tf, t) {
+					last = t
+					missing++
+				}
+				if missing > 0 {
+					gaps = append(gaps, Gap{
+						Start:   start.Format(timestampLayout),
+						End:     last.Format(timestampLayout),
+						Missing: missing,
+					})
+				}
+			}
+			prev = current
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return gaps, nil
+}
+
+// LongestCleanRun - 보간/누락 없이 실제 캔들이 연속된 가장 긴 구간 (start, end 는 첫/마지막 캔들 시각, KST)
+//
+// 연속 여부는 candleEnd 로 계산한 다음 캔들 시각과 비교한다. 길이가 같으면 더 이른 구간을 반환하며,
+// 실제 캔들이 없으면 count 는 0 이다.
+func (c *Collector) LongestCleanRun(tf Timeframe) (start, end time.Time, count int, err error) {
+	var runStart, prev time.Time
+	runCount := 0
+
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(fmt.Sprintf("SELECT timestamp, is_interpolated FROM %s ORDER BY timestamp ASC", c.table(tf)))
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, err
+		}
+
+		for rows.Next() {
+			var ts string
+			var interpolated bool
+			if err := rows.Scan(&ts, &interpolated); err != nil {
+				rows.Close()
+				return time.Time{}, time.Time{}, 0, err
+			}
+			current, err := time.Parse(timestampLayout, ts)
+			if err != nil || interpolated {
+				runCount = 0
+				continue
+			}
+
+			if runCount == 0 || !candleEnd(tf, prev).Equal(current) {
+				runStart, runCount = current, 0
+			}
+			runCount++
+			prev = current
+			if runCount > count {
+				start, end, count = runStart, current, runCount
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, err
+		}
+	}
+	return start, end, count, nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecentCandles(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	if err := c.EnableYearSharding(); err != nil {
+		t.Fatal(err)
+	}
+	tf := mustTimeframe(t, "day")
+	t0 := time.Date(2023, 12, 29, 9, 0, 0, 0, time.UTC)
+	var times []time.Time
+	for i := 0; i < 6; i++ {
+		times = append(times, t0.AddDate(0, 0, i))
+	}
+	seed(t, c, tf, times...)
+
+	// 최근 4개는 2023년, 2024년 파일에 걸쳐 있음
+	candles, err := c.RecentCandles(tf, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 4 {
+		t.Fatalf("%d개, want 4", len(candles))
+	}
+	for i, candle := range candles {
+		if want := times[i+2].Format(timestampLayout); candle.CandleDateTimeKST != want {
+			t.Errorf("[%d] = %s, want %s (오름차순)", i, candle.CandleDateTimeKST, want)
+		}
+	}
+
+	all, err := c.RecentCandles(tf, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(times) {
+		t.Errorf("n 이 저장된 수보다 크면 전부: %d개, want %d", len(all), len(times))
+	}
+
+	if _, err := c.RecentCandles(tf, 0); err == nil {
+		t.Error("n = 0 이 허용됨")
+	}
+}
+
+func TestLongestCleanRun(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return t0.Add(time.Duration(5*i) * time.Minute) }
+
+	if _, _, count, err := c.LongestCleanRun(tf); err != nil || count != 0 {
+		t.Fatalf("빈 테이블 = %d, %v, want 0", count, err)
+	}
+
+	// 실제 캔들 0~4, 7~14, 16~19, 23~29 - 5, 6, 15 는 보간으로 채우고 20~22 는 보간 한도를 넘어 빈 채로 둠
+	missing := map[int]bool{5: true, 6: true, 15: true, 20: true, 21: true, 22: true}
+	var times []time.Time
+	for i := 0; i < 30; i++ {
+		if !missing[i] {
+			times = append(times, at(i))
+		}
+	}
+	seed(t, c, tf, times...)
+	c.MaxInterpolationGap = 2
+	if _, err := c.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, c, tf, "is_interpolated = 1"); n != 3 {
+		t.Fatalf("보간 캔들 %d개, want 3", n)
+	}
+
+	start, end, count, err := c.LongestCleanRun(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 8 || !start.Equal(at(7)) || !end.Equal(at(14)) {
+		t.Errorf("가장 긴 구간 = %v ~ %v (%d개), want %v ~ %v (8개)", start, end, count, at(7), at(14))
+	}
+}
+
+func TestCommonRange(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	minute60, day, week := mustTimeframe(t, "minute60"), mustTimeframe(t, "day"), mustTimeframe(t, "week")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	// 일봉은 1~10일, 60분봉은 3일부터 12일까지 (늦게 시작하고 늦게 끝남)
+	days := make([]time.Time, 10)
+	for i := range days {
+		days[i] = t0.AddDate(0, 0, i)
+	}
+	seed(t, c, day, days...)
+	seed(t, c, minute60, t0.AddDate(0, 0, 2), t0.AddDate(0, 0, 2).Add(5*time.Hour), t0.AddDate(0, 0, 11))
+
+	start, end, err := c.CommonRange([]Timeframe{day, minute60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(t0.AddDate(0, 0, 2)) || !end.Equal(t0.AddDate(0, 0, 9)) {
+		t.Errorf("구간 = %v ~ %v, want 3일 ~ 10일", start, end)
+	}
+	// 구간으로 가져온 캔들은 양쪽 모두 그 안에 있음
+	aligned, err := c.GetCandles(day, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aligned) != 8 || aligned[0].CandleDateTimeKST != start.Format(timestampLayout) {
+		t.Errorf("일봉 %d개 (첫 %v), want 3일부터 8개", len(aligned), aligned)
+	}
+
+	// 겹치지 않거나 비어 있으면 오류
+	seed(t, c, week, t0.AddDate(0, 1, 0))
+	if _, _, err := c.CommonRange([]Timeframe{day, week}); err == nil || !strings.Contains(err.Error(), "겹치지 않습니다") {
+		t.Errorf("겹치지 않는 구간 err = %v", err)
+	}
+	if _, _, err := c.CommonRange([]Timeframe{day, mustTimeframe(t, "month")}); err == nil {
+		t.Error("빈 시간단위에 오류 없음")
+	}
+}
+
+func TestDisplayCandlesLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata 없음: %v", err)
+	}
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "day")
+	winter := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, winter, summer)
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		want     []string
+	}{
+		{"KST", nil, []string{"2024-01-01T09:00:00", "2024-07-01T09:00:00"}},
+		{"UTC", time.UTC, []string{"2024-01-01T00:00:00Z", "2024-07-01T00:00:00Z"}},
+		{"America/New_York", newYork, []string{"2023-12-31T19:00:00-05:00", "2024-06-30T20:00:00-04:00"}},
+	}
+	for _, tt := range tests {
+		c.Location = tt.location
+		candles, err := c.DisplayCandles(tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, candle := range candles {
+			got = append(got, candle.CandleDateTimeKST)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: timestamp = %v, want %v", tt.name, got, tt.want)
+		}
+
+		var out strings.Builder
+		if _, err := c.ExportCSV(&out, tf, time.Time{}, time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), "\n"+tt.want[0]+",") {
+			t.Errorf("%s: CSV 에 %s 가 없음:\n%s", tt.name, tt.want[0], out.String())
+		}
+	}
+
+	// 저장된 값과 GetCandles 결과(캐시 포함)는 KST 그대로
+	c.Location = newYork
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 2 || candles[0].CandleDateTimeKST != "2024-01-01T09:00:00" {
+		t.Errorf("GetCandles = %+v, want KST 문자열 그대로", candles)
+	}
+	if n := countRows(t, c, tf, "timestamp IN (?, ?)", "2024-01-01T09:00:00", "2024-07-01T09:00:00"); n != 2 {
+		t.Errorf("저장된 KST timestamp %d개, want 2", n)
+	}
+}
+
+func TestFindGapsMonth(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "month")
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 9, 0, 0, 0, time.UTC) }
+	// 1~3월은 연속 (2월은 29일), 4~5월이 빠짐, 6~8월은 연속 (30/31일 달)
+	seed(t, c, tf, month(1), month(2), month(3), month(6), month(7), month(8))
+
+	gaps, err := c.FindGaps(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Gap{Start: month(4).Format(timestampLayout), End: month(5).Format(timestampLayout), Missing: 2}
+	if len(gaps) != 1 || gaps[0] != want {
+		t.Errorf("gaps = %+v, want [%+v] (달 길이가 달라도 연속)", gaps, want)
+	}
+
+	// LongestCleanRun 과 같은 기준으로 연속을 판단
+	start, end, count, err := c.LongestCleanRun(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(month(1)) || !end.Equal(month(3)) || count != 3 {
+		t.Errorf("LongestCleanRun = %v ~ %v (%d), want 1월 ~ 3월 (3)", start, end, count)
+	}
+
+	cov, err := c.coverage(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cov.Missing != 2 || cov.Expected != 8 {
+		t.Errorf("coverage missing = %d, expected = %d, want 2, 8", cov.Missing, cov.Expected)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ComputeRangePct - 캔들별 (고가-저가)/종가 를 range_pct 컬럼에 저장 (컬럼이 없으면 추가, 갱신한 행 수 반환)
+//
+// range_pct 가 비어 있는 행만 계산하므로 다시 실행하면 새 캔들만 채운다 (ConflictReplace 로 덮어쓴 캔들도
+// 다시 비어 있게 됨). 종가가 0 인 행은 나눌 수 없으므로 NULL 로 둔다.
+func (c *Collector) ComputeRangePct(tf Timeframe) (int, error) {
+	table := c.table(tf)
+	updated := 0
+	for _, db := range c.candleDBs() {
+		_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN range_pct REAL", table))
+		if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return updated, err
+		}
+		res, err := db.Exec(fmt.Sprintf(`
+			UPDATE %s SET range_pct = (high_price - low_price) / trade_price
+			WHERE range_pct IS NULL AND trade_price != 0
+		`, table))
+		if err != nil {
+			return updated, err
+		}
+		n, _ := res.RowsAffected()
+		updated += int(n)
+	}
+	return updated, nil
+}
+
+func runRangePct(args []string) error {
+	fs := flag.NewFlagSet("range-pct", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "해당 시간단위만 계산 (기본: 전체)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	targets := timeframes
+	if *timeframe != "" {
+		tf, err := findTimeframe(*timeframe)
+		if err != nil {
+			return err
+		}
+		targets = []Timeframe{tf}
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	var errs []error
+	for _, tf := range targets {
+		n, err := collector.ComputeRangePct(tf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tf.Name, err))
+			continue
+		}
+		fmt.Fprintf(collector.Output, "[%s] %s range_pct %d개 갱신\n", tf.Name, collector.mark(markOK), n)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeRangePct(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	candle := func(i int, high, low, close float64) Candle {
+		candle := testCandle(t0.Add(time.Duration(i)*time.Minute), close)
+		candle.HighPrice, candle.LowPrice = high, low
+		return candle
+	}
+	candles := []Candle{candle(0, 110, 90, 100), candle(1, 105, 105, 105), candle(2, 0, 0, 0)}
+	if _, _, err := c.saveCandles(tf, candles); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := c.ComputeRangePct(tf); err != nil || n != 2 {
+		t.Fatalf("첫 계산 = %d, %v, want 2 (종가 0 행 제외)", n, err)
+	}
+
+	// 새 캔들만 계산
+	candles = append(candles, candle(3, 52, 48, 50))
+	if _, _, err := c.saveCandles(tf, candles[3:]); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := c.ComputeRangePct(tf); err != nil || n != 1 {
+		t.Fatalf("두 번째 계산 = %d, %v, want 1", n, err)
+	}
+	if n, err := c.ComputeRangePct(tf); err != nil || n != 0 {
+		t.Errorf("세 번째 계산 = %d, %v, want 0", n, err)
+	}
+
+	for _, candle := range candles {
+		var got sql.NullFloat64
+		if err := c.db.QueryRow("SELECT range_pct FROM bitcoin_minute1 WHERE timestamp = ?", candle.CandleDateTimeKST).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if candle.TradePrice == 0 {
+			if got.Valid {
+				t.Errorf("%s: 종가 0 인데 range_pct = %v, want NULL", candle.CandleDateTimeKST, got.Float64)
+			}
+			continue
+		}
+		want := (candle.HighPrice - candle.LowPrice) / candle.TradePrice
+		if !got.Valid || math.Abs(got.Float64-want) > 1e-12 {
+			t.Errorf("%s: range_pct = %+v, want %v", candle.CandleDateTimeKST, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// RefetchResult - RefetchGaps 결과
+type RefetchResult struct {
+	Timeframe    string
+	Gaps         int // 다시 요청한 빈 구간 수
+	Requests     int // 보낸 캔들 요청 수
+	Recovered    int // 업비트에서 다시 받아 저장한 실제 캔들 수
+	Remaining    int // 다시 받은 뒤에도 남은 빈 구간 수 (보간 전)
+	Interpolated int // 남은 구간을 보간한 캔들 수
+}
+
+// RefetchGaps - 실제 캔들 사이 빈 구간(보간 캔들 포함)마다 구간 끝을 to 로 다시 요청해 실제 캔들을 채운 뒤, 남은 구간만 보간
+//
+// 요청 실패나 중간 수집 중단으로 빠진 캔들은 업비트에 남아 있으므로 보간 전에 먼저 받아 온다. 이미 보간한
+// 구간도 다시 요청하며, 실제 캔들을 받으면 그 구간의 보간 캔들을 지우고 저장한다 (남은 자리는 새 기준
+// 캔들로 다시 보간). 구간이 200개보다 길면 같은 구간을 과거 방향으로 이어서 요청하고, 받은 캔들이 구간
+// 시작보다 이전이면 멈춘다 (거래가 없던 구간은 다시 받아도 비어 있음). 구간 밖의 캔들은 저장하지 않는다.
+func (c *Collector) RefetchGaps(tf Timeframe) (RefetchResult, error) {
+	result := RefetchResult{Timeframe: tf.Name}
+	gaps, err := c.findGaps(tf, true)
+	if err != nil {
+		return result, err
+	}
+	defer c.invalidateCache(tf)
+
+	ctx := context.Background()
+	for _, gap := range gaps {
+		end, err := time.Parse(timestampLayout, gap.End)
+		if err != nil {
+			continue
+		}
+		result.Gaps++
+		// 구간 다음 캔들 시작 시각 (to 는 그 이전 캔들을 돌려줌)
+		to := candleEnd(tf, end).Add(-9 * time.Hour).Format(time.RFC3339)
+		cleared := false
+
+		for {
+			result.Requests++
+			candles, err := c.fetchCandles(ctx, tf, to, nil)
+			if errors.Is(err, errRequestBudget) {
+				result.Requests--
+				return result, err
+			}
+			if err != nil {
+				return result, fmt.Errorf("%s ~ %s 구간 요청 실패: %w", gap.Start, gap.End, err)
+			}
+
+			inGap := make([]Candle, 0, len(candles))
+			for _, candle := range candles {
+				if candle.CandleDateTimeKST >= gap.Start && candle.CandleDateTimeKST <= gap.End {
+					inGap = append(inGap, candle)
+				}
+			}
+			if len(inGap) > 0 && !cleared {
+				if err := c.deleteInterpolatedBetween(tf, gap); err != nil {
+					return result, err
+				}
+				cleared = true
+			}
+			saved, _, err := c.saveCandles(tf, inGap)
+			result.Recovered += saved
+			if err != nil {
+				return result, err
+			}
+
+			if len(candles) == 0 {
+				break
+			}
+			oldest := candles[len(candles)-1]
+			if oldest.CandleDateTimeKST <= gap.Start {
+				break
+			}
+			to = pageCursor(oldest.CandleDateTimeUTC)
+		}
+	}
+	if result.Recovered > 0 {
+		fmt.Fprintf(c.Output, "[%s] %s 빈 구간 %d개에서 실제 캔들 %d개 다시 받음\n", tf.Name, c.mark(markOK), result.Gaps, result.Recovered)
+	}
+
+	remaining, err := c.FindGaps(tf)
+	if err != nil {
+		return result, err
+	}
+	result.Remaining = len(remaining)
+	if result.Remaining == 0 {
+		return result, nil
+	}
+	result.Interpolated, err = c.interpolateMissingData(tf)
+	return result, err
+}
+
+// deleteInterpolatedBetween - gap 구간(양 끝 포함)의 보간 캔들 삭제
+func (c *Collector) deleteInterpolatedBetween(tf Timeframe, gap Gap) error {
+	for _, db := range c.candleDBs() {
+		if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE is_interpolated >= 1 AND timestamp >= ? AND timestamp <= ?", c.table(tf)),
+			gap.Start, gap.End); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRefetchGaps(args []string) error {
+	fs := flag.NewFlagSet("refetch-gaps", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "다시 받을 시간단위 (필수, 예: minute1)")
+	rate := fs.Int("rate", 9, "초당 최대 API 요청 수 (업비트 제한: 10)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if *rate <= 0 {
+		return fmt.Errorf("--rate 는 1 이상이어야 합니다")
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.rateLimiter = NewRateLimiter(*rate)
+
+	result, err := collector.RefetchGaps(tf)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[%s] %s 빈 구간 %d개, 요청 %d회, 다시 받은 캔들 %s개, 남은 구간 %d개 (보간 %s개)\n", tf.Name, collector.mark(markOK),
+		result.Gaps, result.Requests, formatNumber(result.Recovered), result.Remaining, formatNumber(result.Interpolated))
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefetchGapsRecoversRealCandles(t *testing.T) {
+	c, f := newTestCollector(t)
+	tf := mustTimeframe(t, "minute1")
+	start := f.head.Add(9*time.Hour - 10*time.Minute)
+	minutes := kstMinutes(start, 11)
+	seed(t, c, tf, append(minutes[:3:3], minutes[8:]...)...)
+
+	result, err := c.RefetchGaps(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Gaps != 1 || result.Recovered != 5 || result.Remaining != 0 || result.Interpolated != 0 {
+		t.Errorf("result = %+v, want gaps 1, recovered 5, remaining 0", result)
+	}
+	assertFakeCandles(t, c, tf, minutes[3:8])
+}
+
+func TestRefetchGapsReplacesInterpolatedCandles(t *testing.T) {
+	c, f := newTestCollector(t)
+	tf := mustTimeframe(t, "minute1")
+	start := f.head.Add(9*time.Hour - 10*time.Minute)
+	minutes := kstMinutes(start, 11)
+	seed(t, c, tf, append(minutes[:3:3], minutes[8:]...)...)
+	if n, err := c.interpolateMissingData(tf); err != nil || n != 5 {
+		t.Fatalf("보간 %d개, err %v, want 5", n, err)
+	}
+
+	result, err := c.RefetchGaps(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Gaps != 1 || result.Recovered != 5 {
+		t.Errorf("result = %+v, want gaps 1, recovered 5", result)
+	}
+	if n := countRows(t, c, tf, "is_interpolated >= 1"); n != 0 {
+		t.Errorf("보간 캔들 %d개 남음", n)
+	}
+	assertFakeCandles(t, c, tf, minutes[3:8])
+}
+
+// assertFakeCandles - times(KST) 캔들이 fakeUpbit 에서 받은 실제 캔들인지 확인
+func assertFakeCandles(t *testing.T, c *Collector, tf Timeframe, times []time.Time) {
+	t.Helper()
+	candles, err := c.GetCandles(tf, times[0], times[len(times)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != len(times) {
+		t.Fatalf("캔들 %d개, want %d", len(candles), len(times))
+	}
+	for i, candle := range candles {
+		if want := fakePrice(times[i].Add(-9 * time.Hour)); candle.IsInterpolated || candle.OpeningPrice != want {
+			t.Errorf("%s: 보간 %v, 시가 %v, want 실제 캔들 시가 %v", candle.CandleDateTimeKST, candle.IsInterpolated, candle.OpeningPrice, want)
+		}
+	}
+}
+
+func TestRefetchGapsMonth(t *testing.T) {
+	c, _ := newTestCollector(t)
+	tf := mustTimeframe(t, "month")
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 9, 0, 0, 0, time.UTC) }
+	seed(t, c, tf, month(1), month(2), month(3), month(6))
+
+	result, err := c.RefetchGaps(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Gaps != 1 || result.Recovered != 2 || result.Remaining != 0 {
+		t.Errorf("result = %+v, want gaps 1, recovered 2, remaining 0", result)
+	}
+	assertFakeCandles(t, c, tf, []time.Time{month(4), month(5)})
+}
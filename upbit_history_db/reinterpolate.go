@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// ReinterpolateResult - 시간단위별 재보간 결과
+type ReinterpolateResult struct {
+	Timeframe    string
+	Deleted      int // 삭제한 기존 보간 캔들 수
+	Interpolated int // 현재 보간 방식으로 새로 만든 캔들 수
+	Err          error
+}
+
+// ReinterpolateAll - 모든 시간단위의 보간 캔들을 지우고 현재 Interpolation 방식으로 다시 생성
+//
+// 수집과 같은 MaxConcurrency 제한으로 시간단위를 병렬 처리하며, 실패한 시간단위 오류를 합쳐 반환한다.
+func (c *Collector) ReinterpolateAll() ([]ReinterpolateResult, error) {
+	fmt.Fprintf(c.Output, "%s 전체 재보간 시작 (보간 방식: %s)\n", c.mark(markStart), c.Interpolation)
+
+	results := make([]ReinterpolateResult, len(timeframes))
+	c.forEachTimeframe(func(i int, tf Timeframe) {
+		results[i] = c.reinterpolate(tf)
+	})
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 재보간 실패: %v\n", r.Timeframe, c.mark(markFail), r.Err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.Timeframe, r.Err))
+			continue
+		}
+		fmt.Fprintf(c.Output, "[%s] %s 기존 보간 %s개 삭제, %s개 재생성\n",
+			r.Timeframe, c.mark(markOK), formatNumber(r.Deleted), formatNumber(r.Interpolated))
+	}
+	return results, errors.Join(errs...)
+}
+
+func (c *Collector) reinterpolate(tf Timeframe) ReinterpolateResult {
+	result := ReinterpolateResult{Timeframe: tf.Name}
+
+	for _, db := range c.candleDBs() {
+		res, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE is_interpolated >= 1", c.table(tf)))
+		if err != nil {
+			result.Err = fmt.Errorf("기존 보간 삭제 실패: %w", err)
+			return result
+		}
+		deleted, _ := res.RowsAffected()
+		result.Deleted += int(deleted)
+	}
+	c.invalidateCache(tf)
+
+	result.Interpolated, result.Err = c.interpolateMissingData(tf)
+	return result
+}
+
+// CompactInterpolation - 실제 캔들 사이 빈 구간마다 보간 캔들이 정확히 필요한 만큼만 있도록 고침
+//
+// 부분 수집을 여러 번 하면 예전 보간 캔들이 새로 받은 실제 캔들과 어긋나 남을 수 있다. 전체를 다시
+// 만드는 reinterpolate 와 달리 한 번 훑으며 구간에 맞지 않는 보간 캔들(시각이 어긋났거나 보간 한도를
+// 넘는 구간, 첫 실제 캔들 이전)만 지우고 빠진 시각만 현재 Interpolation 방식으로 채운다. 이미 맞는
+// 보간 캔들의 값은 그대로 두며, 마지막 실제 캔들 이후(FillToNow 결과)와 진행 중 캔들 직전 구간은 건드리지 않는다.
+func (c *Collector) CompactInterpolation(tf Timeframe) (deleted, added int, err error) {
+	var rows []Candle
+	realCount := 0
+	for _, db := range c.candleDBs() {
+		result, err := db.Query(candleSchema.selectSQL(c.table(tf)) + " ORDER BY timestamp ASC")
+		if err != nil {
+			return 0, 0, err
+		}
+		part, err := c.scanCandles(result)
+		result.Close()
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, row := range part {
+			if !row.IsInterpolated {
+				realCount++
+			}
+		}
+		rows = append(rows, part...)
+	}
+	if c.MinInterpolationCandles > 0 && realCount < c.MinInterpolationCandles {
+		return 0, 0, nil
+	}
+
+	var stale []string
+	var missing []Candle
+	prev := -1        // 직전 실제 캔들 인덱스
+	var between []int // 직전 실제 캔들 이후의 보간 캔들 인덱스
+	for i, row := range rows {
+		if row.IsInterpolated {
+			between = append(between, i)
+			continue
+		}
+		if !c.InterpolateProvisional && c.isProvisional(tf, row.CandleDateTimeKST) {
+			break
+		}
+
+		if prev < 0 {
+			for _, j := range between {
+				stale = append(stale, rows[j].CandleDateTimeKST)
+			}
+		} else if expected, ok := c.gapTimes(tf, rows[prev], row); ok {
+			existing := make(map[string]bool, len(between))
+			for _, j := range between {
+				existing[rows[j].CandleDateTimeKST] = true
+			}
+			want := make(map[string]bool, len(expected))
+			gap := len(expected) + 1
+			for k, t := range expected {
+				candle := c.interpolatedCandle(rows[prev], row, t, float64(k+1)/float64(gap))
+				want[candle.CandleDateTimeKST] = true
+				if !existing[candle.CandleDateTimeKST] {
+					missing = append(missing, candle)
+				}
+			}
+			for _, j := range between {
+				if !want[rows[j].CandleDateTimeKST] {
+					stale = append(stale, rows[j].CandleDateTimeKST)
+				}
+			}
+		}
+		prev, between = i, nil
+	}
+
+	if len(stale) > 0 || len(missing) > 0 {
+		defer c.invalidateCache(tf)
+	}
+	for _, ts := range stale {
+		db, err := c.candleDBFor(ts)
+		if err != nil {
+			return deleted, added, err
+		}
+		res, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE timestamp = ? AND is_interpolated >= 1", c.table(tf)), ts)
+		if err != nil {
+			return deleted, added, fmt.Errorf("보간 캔들 삭제 실패: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		deleted += int(n)
+	}
+	for i := range missing {
+		db, err := c.candleDBFor(missing[i].CandleDateTimeKST)
+		if err != nil {
+			return deleted, added, err
+		}
+		if _, err := db.Exec(c.candleTableSchema().insertSQL("INSERT OR REPLACE", c.table(tf)), c.candleRow(&missing[i])...); err != nil {
+			return deleted, added, fmt.Errorf("보간 캔들 추가 실패: %w", err)
+		}
+		added++
+	}
+	return deleted, added, nil
+}
+
+// gapTimes - 실제 캔들 prev 와 next 사이에 보간으로 채울 시각 (MaxInterpolationGap 을 넘으면 빈 목록, 시각 해석 실패 시 ok=false)
+func (c *Collector) gapTimes(tf Timeframe, prev, next Candle) (times []time.Time, ok bool) {
+	start, err := parseKST(prev.CandleDateTimeKST)
+	if err != nil {
+		return nil, false
+	}
+	end, err := parseKST(next.CandleDateTimeKST)
+	if err != nil {
+		return nil, false
+	}
+	for t := candleEnd(tf, start); t.Before(end); t = candleEnd(tf, t) {
+		times = append(times, t)
+		if c.MaxInterpolationGap > 0 && len(times) > c.MaxInterpolationGap {
+			return nil, true
+		}
+	}
+	return times, true
+}
+
+// RecomputeFlags - 보간 캔들(is_interpolated >= 1)을 지운 뒤 보간을 다시 실행해 플래그를 현재 로직 기준으로 맞춤
+//
+// 보간 기능 이전에 만든 DB 나 플래그가 잘못 기록된 DB 용이다. 플래그가 1 인 행은 reinterpolate 처럼 지우고
+// 다시 만들므로 보간 캔들이 실제 캔들로 바뀌지 않는다. 플래그가 0 인 행은 모두 실제 캔들로 보고 남기므로,
+// 예전 버전이 보간 캔들을 0 으로 기록한 DB 에서는 그 행을 실제 캔들과 구분할 수 없어 그대로 남는다.
+func (c *Collector) RecomputeFlags(tf Timeframe) (deleted int, interpolated int, err error) {
+	result := c.reinterpolate(tf)
+	return result.Deleted, result.Interpolated, result.Err
+}
+
+func runRecomputeFlags(args []string) error {
+	fs := flag.NewFlagSet("recompute-flags", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "대상 시간단위 (필수, 예: minute1)")
+	force := fs.Bool("force", false, "실제로 플래그를 다시 계산 (지정하지 않으면 아무것도 하지 않음)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *timeframe == "" {
+		return fmt.Errorf("--timeframe 이 필요합니다")
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if !*force {
+		return fmt.Errorf("%s 의 보간 캔들을 지우고 is_interpolated 플래그를 다시 계산합니다. 계속하려면 --force 를 지정하세요", tf.Name)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	deleted, interpolated, err := collector.RecomputeFlags(tf)
+	if err != nil {
+		return fmt.Errorf("%s 플래그 재계산 실패: %w", tf.Name, err)
+	}
+	fmt.Printf("[%s] %s 기존 보간 %s개 삭제, %s개 보간\n", tf.Name, collector.mark(markOK), formatNumber(deleted), formatNumber(interpolated))
+	return nil
+}
+
+func runReinterpolate(args []string) error {
+	fs := flag.NewFlagSet("reinterpolate", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	concurrency := fs.Int("concurrency", 0, "동시에 처리할 시간단위 수 (0 = 전체 동시)")
+	strategy := fs.String("interpolation", InterpolateLinear.String(), "보간 방식 (linear, zero-volume)")
+	compact := fs.Bool("compact", false, "전체를 다시 만들지 않고 구간에 맞지 않는 보간 캔들만 지우고 빠진 것만 채움")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	interpolation, err := parseInterpolationStrategy(*strategy)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.MaxConcurrency = *concurrency
+	collector.Interpolation = interpolation
+
+	if *compact {
+		var errs []error
+		for _, tf := range timeframes {
+			deleted, added, err := collector.CompactInterpolation(tf)
+			if err != nil {
+				fmt.Fprintf(collector.Output, "[%s] %s 보간 정리 실패: %v\n", tf.Name, collector.mark(markFail), err)
+				errs = append(errs, fmt.Errorf("%s: %w", tf.Name, err))
+				continue
+			}
+			fmt.Fprintf(collector.Output, "[%s] %s 어긋난 보간 %s개 삭제, 빠진 보간 %s개 추가\n",
+				tf.Name, collector.mark(markOK), formatNumber(deleted), formatNumber(added))
+		}
+		return errors.Join(errs...)
+	}
+
+	_, err = collector.ReinterpolateAll()
+	return err
+}
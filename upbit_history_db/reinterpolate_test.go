@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecomputeFlagsKeepsInterpolatedRows(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.Add(time.Minute), t0.Add(5*time.Minute))
+	if _, err := c.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, interpolated, err := c.RecomputeFlags(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 3 || interpolated != 3 {
+		t.Errorf("deleted = %d, interpolated = %d, want 3, 3", deleted, interpolated)
+	}
+	if real := countRows(t, c, tf, "is_interpolated = 0"); real != 3 {
+		t.Errorf("실제 캔들 %d개, want 3 (보간 캔들이 실제 캔들로 바뀜)", real)
+	}
+	if synthetic := countRows(t, c, tf, "is_interpolated = 1"); synthetic != 3 {
+		t.Errorf("보간 캔들 %d개, want 3", synthetic)
+	}
+}
+
+func TestRecomputeFlagsLegacyTable(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.Add(time.Minute), t0.Add(4*time.Minute))
+	// 보간 기능 이전 버전: 빈 구간이 있고 모든 행이 0, 실제 캔들 하나가 보간으로 잘못 기록됨
+	if _, err := c.db.Exec("UPDATE bitcoin_minute1 SET is_interpolated = 1 WHERE timestamp = ?",
+		t0.Add(time.Minute).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.RecomputeFlags(tf); err != nil {
+		t.Fatal(err)
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{false, true, true, true, false}
+	if len(candles) != len(want) {
+		t.Fatalf("캔들 %d개, want %d", len(candles), len(want))
+	}
+	for i, candle := range candles {
+		if candle.IsInterpolated != want[i] {
+			t.Errorf("%s 보간 = %v, want %v", candle.CandleDateTimeKST, candle.IsInterpolated, want[i])
+		}
+	}
+}
+
+func TestReinterpolateAllWithNewStrategy(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	minute1, day := mustTimeframe(t, "minute1"), mustTimeframe(t, "day")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, minute1, t0, t0.Add(3*time.Minute))
+	seed(t, c, day, t0, t0.AddDate(0, 0, 2))
+	if _, err := c.interpolateMissingData(minute1); err != nil {
+		t.Fatal(err)
+	}
+	volumes := func() []float64 {
+		t.Helper()
+		candles, err := c.GetCandles(minute1, t0.Add(time.Minute), t0.Add(2*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v []float64
+		for _, candle := range candles {
+			v = append(v, candle.CandleAccTradeVolume)
+		}
+		return v
+	}
+	if v := volumes(); len(v) != 2 || v[0] == 0 {
+		t.Fatalf("선형 보간 거래량 = %v", v)
+	}
+
+	c.Interpolation = InterpolateZeroVolume
+	results, err := c.ReinterpolateAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := volumes(); len(v) != 2 || v[0] != 0 || v[1] != 0 {
+		t.Errorf("zero-volume 재보간 후 거래량 = %v, want [0 0]", v)
+	}
+	if len(results) != len(timeframes) {
+		t.Fatalf("결과 %d개, want %d", len(results), len(timeframes))
+	}
+	for _, r := range results {
+		switch r.Timeframe {
+		case "minute1":
+			if r.Deleted != 2 || r.Interpolated != 2 {
+				t.Errorf("minute1 결과 = %+v, want 2 삭제, 2 보간", r)
+			}
+		case "day":
+			if r.Deleted != 0 || r.Interpolated != 1 {
+				t.Errorf("day 결과 = %+v, want 0 삭제, 1 보간", r)
+			}
+		}
+	}
+}
+
+func TestCompactInterpolationCleansStaleRows(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.MaxInterpolationGap = 3
+	tf := mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	slot := func(n int) time.Time { return t0.Add(time.Duration(n) * 5 * time.Minute) }
+	seed(t, c, tf, slot(0), slot(3), slot(6), slot(15))
+
+	// 예전 부분 수집이 남긴 보간 캔들
+	interpolated := func(ts time.Time, price float64) {
+		t.Helper()
+		candle := testCandle(ts, price)
+		candle.IsInterpolated = true
+		if _, err := c.db.Exec(c.candleTableSchema().insertSQL("INSERT", "bitcoin_minute5"), c.candleRow(&candle)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	interpolated(slot(-1), 1)                   // 첫 실제 캔들 이전
+	interpolated(slot(1), 999)                  // 맞는 자리 (값은 그대로 둠)
+	interpolated(slot(1).Add(2*time.Minute), 1) // 5분 간격에 어긋난 시각
+	interpolated(slot(4), 1)                    // 맞는 자리
+	interpolated(slot(10), 1)                   // 보간 한도(3)를 넘는 구간
+	interpolated(slot(16), 1)                   // 마지막 실제 캔들 이후 (FillToNow) 는 건드리지 않음
+
+	deleted, added, err := c.CompactInterpolation(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 3 || added != 2 {
+		t.Errorf("deleted = %d, added = %d, want 3, 2", deleted, added)
+	}
+	var want []string
+	for _, n := range []int{1, 2, 4, 5, 16} {
+		want = append(want, slot(n).Format(timestampLayout))
+	}
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, candle := range candles {
+		if candle.IsInterpolated {
+			got = append(got, candle.CandleDateTimeKST)
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("보간 캔들 = %v, want %v", got, want)
+	}
+	if n := countRows(t, c, tf, "timestamp = ? AND trade_price = 999", slot(1).Format(timestampLayout)); n != 1 {
+		t.Error("이미 맞는 보간 캔들 값이 바뀜")
+	}
+
+	// 한 번 더 실행해도 바뀌지 않음
+	if deleted, added, err := c.CompactInterpolation(tf); err != nil || deleted != 0 || added != 0 {
+		t.Errorf("두 번째 실행 = %d, %d, %v, want 0, 0", deleted, added, err)
+	}
+}
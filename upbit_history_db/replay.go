@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Replay - 저장된 tf 캔들(from 이상 to 이하)을 실시간으로 도착하는 것처럼 시간 오름차순으로 out 에 전송
+//
+// 캔들 사이 대기 시간은 실제 시각 차이를 speed 로 나눈 값이다 (speed 60 이면 1분봉이 1초마다, 0 이면 대기 없음).
+// ctx 가 취소되면 ctx.Err() 를 반환하며, 반환할 때 out 을 닫는다.
+func (c *Collector) Replay(ctx context.Context, tf Timeframe, from, to time.Time, speed float64, out chan<- Candle) error {
+	defer close(out)
+	if speed < 0 {
+		return fmt.Errorf("speed 는 0 이상이어야 합니다: %v", speed)
+	}
+
+	candles, err := c.GetCandles(tf, from, to)
+	if err != nil {
+		return err
+	}
+
+	var prev time.Time
+	for i, candle := range candles {
+		at, err := parseKST(candle.CandleDateTimeKST)
+		if err != nil {
+			return fmt.Errorf("잘못된 timestamp %q: %w", candle.CandleDateTimeKST, err)
+		}
+		if i > 0 && speed > 0 {
+			if !sleepCtx(ctx, time.Duration(float64(at.Sub(prev))/speed)) {
+				return ctx.Err()
+			}
+		}
+		prev = at
+
+		select {
+		case out <- candle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "minute5", "재생할 시간단위")
+	from := fs.String("from", "", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "종료 시각 (KST, 포함)")
+	speed := fs.Float64("speed", 0, "재생 배속 (60 = 1분을 1초에, 0 = 대기 없이)")
+	short := fs.Int("short", 5, "단기 SMA 기간")
+	long := fs.Int("long", 20, "장기 SMA 기간")
+	webhook := fs.String("webhook", "", "신호를 POST 할 URL (비우면 로그만 출력)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+	if *short < 1 || *long <= *short {
+		return fmt.Errorf("--long 은 --short 보다 커야 합니다")
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	var notifier Notifier = LogNotifier{}
+	if *webhook != "" {
+		notifier = WebhookNotifier{URL: *webhook}
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	candles := make(chan Candle)
+	done := make(chan error, 1)
+	go func() { done <- collector.Replay(ctx, tf, fromTime, toTime, *speed, candles) }()
+
+	fmt.Fprintf(collector.Output, "[%s] %s 재생 시작 (배속 %v)\n", tf.Name, collector.mark(markLaunch), *speed)
+	strategy := SMACrossover{Short: *short, Long: *long}
+	var history []Candle
+	replayed := 0
+	for candle := range candles {
+		history = collector.evaluateClosed(tf, strategy, notifier, history, candle)
+		replayed++
+	}
+	fmt.Fprintf(collector.Output, "[%s] %s 재생 종료 (캔들 %s개)\n", tf.Name, collector.mark(markOK), formatNumber(replayed))
+
+	if err := <-done; err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
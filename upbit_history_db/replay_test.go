@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplayOrderAndCount(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	times := kstMinutes(t0, 10)
+	// 저장 순서와 관계없이 시간 오름차순으로 재생
+	seed(t, c, tf, times[5:]...)
+	seed(t, c, tf, times[:5]...)
+
+	// 60000 배속: 캔들 사이 1ms
+	out := make(chan Candle)
+	errc := make(chan error, 1)
+	started := time.Now()
+	go func() { errc <- c.Replay(context.Background(), tf, times[2], times[7], 60000, out) }()
+	var got []string
+	for candle := range out {
+		got = append(got, candle.CandleDateTimeKST)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 6 {
+		t.Fatalf("%d개 전송, want from~to 6개: %v", len(got), got)
+	}
+	for i, ts := range got {
+		if want := times[2+i].Format(timestampLayout); ts != want {
+			t.Errorf("[%d] = %s, want %s", i, ts, want)
+		}
+	}
+	if elapsed := time.Since(started); elapsed < 5*time.Millisecond {
+		t.Errorf("재생 %v, want 간격 5개 × 1ms 이상 대기", elapsed)
+	}
+}
+
+func TestReplayCancel(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	seed(t, c, tf, kstMinutes(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 10)...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan Candle)
+	errc := make(chan error, 1)
+	go func() { errc <- c.Replay(ctx, tf, time.Time{}, time.Time{}, 0, out) }()
+	<-out
+	<-out
+	cancel()
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("취소 후 err = %v, want context.Canceled", err)
+	}
+	if _, ok := <-out; ok {
+		t.Error("취소 후 out 이 닫히지 않음")
+	}
+
+	if err := c.Replay(context.Background(), tf, time.Time{}, time.Time{}, -1, make(chan Candle)); err == nil {
+		t.Error("음수 speed 에 오류 없음")
+	}
+}
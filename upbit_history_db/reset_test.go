@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResetTimeframe(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	minute1, minute5 := mustTimeframe(t, "minute1"), mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, minute1, kstMinutes(t0, 10)...)
+	seed(t, c, minute5, t0, t0.Add(5*time.Minute))
+	for _, tf := range []Timeframe{minute1, minute5} {
+		if _, err := c.ComputeReturns(tf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.ResetTimeframe(minute1); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countRows(t, c, minute1, ""); n != 0 {
+		t.Errorf("초기화 후 캔들 %d개", n)
+	}
+	if n := countRows(t, c, minute5, ""); n != 2 {
+		t.Errorf("다른 시간단위 캔들 %d개, want 2", n)
+	}
+	returns := func(tf Timeframe) int {
+		var n int
+		if err := c.db.QueryRow("SELECT COUNT(*) FROM candle_returns WHERE timeframe = ?", tf.Name).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := returns(minute1); n != 0 {
+		t.Errorf("초기화한 시간단위 수익률 %d행 남음", n)
+	}
+	if n := returns(minute5); n != 2 {
+		t.Errorf("다른 시간단위 수익률 %d행, want 2", n)
+	}
+
+	fresh := openTestDB(t, "KRW-BTC")
+	if got, want := tableSQL(t, c, minute1), tableSQL(t, fresh, minute1); got != want {
+		t.Errorf("초기화 후 스키마가 initDatabase 와 다름:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestResetTimeframeWithoutStateTables(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	if err := c.ResetTimeframe(mustTimeframe(t, "day")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tableSQL - tf 테이블의 CREATE TABLE 문
+func tableSQL(t *testing.T, c *Collector, tf Timeframe) string {
+	t.Helper()
+	var sql string
+	if err := c.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", c.table(tf)).Scan(&sql); err != nil {
+		t.Fatal(err)
+	}
+	return sql
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// retentionTargets - ApplyRetention 이 오래된 캔들을 합쳐 넣을 상위 시간단위
+//
+// 분봉은 UTC 기준 경계(업비트 캔들과 동일)로, minute240 은 일봉 경계(KST 09:00)로 묶는다.
+var retentionTargets = map[string]string{
+	"minute1":   "minute5",
+	"minute3":   "minute15",
+	"minute5":   "minute15",
+	"minute10":  "minute30",
+	"minute15":  "minute60",
+	"minute30":  "minute60",
+	"minute60":  "minute240",
+	"minute240": "day",
+}
+
+// RetentionResult - ApplyRetention 결과
+type RetentionResult struct {
+	Source     string
+	Target     string
+	Aggregated int // 상위 시간단위에 새로 저장한 캔들 수 (이미 있던 캔들은 유지)
+	Deleted    int // 삭제한 원본 캔들 수 (PruneAfterRetention 일 때만)
+}
+
+// ApplyRetention - keepFullSince(KST) 이전의 tf 캔들을 상위 시간단위로 합쳐 저장
+//
+// 구간이 cutoff 에 걸친 상위 캔들은 건드리지 않는다. 보간 캔들은 합치지 않으며,
+// 실제 캔들이 하나도 없는 구간은 건너뛴다. PruneAfterRetention 이 true 면 합친 뒤
+// cutoff 이전 원본 캔들(보간 포함)을 삭제한다. 상위 캔들이 하나라도 저장에 실패하면 삭제하지 않고 오류를 반환한다.
+func (c *Collector) ApplyRetention(tf Timeframe, keepFullSince time.Time) (RetentionResult, error) {
+	targetName, ok := retentionTargets[tf.Name]
+	if !ok {
+		return RetentionResult{}, fmt.Errorf("%s 는 합칠 상위 시간단위가 없습니다", tf.Name)
+	}
+	target, err := findTimeframe(targetName)
+	if err != nil {
+		return RetentionResult{}, err
+	}
+	result := RetentionResult{Source: tf.Name, Target: target.Name}
+
+	cutoff := bucketStart(target, keepFullSince).Format(timestampLayout)
+	var source []Candle
+	for _, db := range c.candleDBsBetween(time.Time{}, keepFullSince) {
+		rows, err := db.Query(candleSchema.selectSQL(c.table(tf))+
+			" WHERE is_interpolated = 0 AND timestamp < ? ORDER BY timestamp ASC", cutoff)
+		if err != nil {
+			return result, err
+		}
+		part, err := c.scanCandles(rows)
+		rows.Close()
+		if err != nil {
+			return result, err
+		}
+		source = append(source, part...)
+	}
+
+	aggregated := aggregateCandles(target, source)
+	saved, failed, err := c.saveCandles(target, aggregated)
+	result.Aggregated = saved
+	if err != nil {
+		return result, fmt.Errorf("%s 저장 실패: %w", target.Name, err)
+	}
+	fmt.Fprintf(c.Output, "[%s] %s %s 이전 캔들 %s개 → %s %s개 저장\n", tf.Name, c.mark(markOK),
+		cutoff, formatNumber(len(source)), target.Name, formatNumber(saved))
+
+	if !c.PruneAfterRetention {
+		return result, nil
+	}
+	// 저장하지 못한 상위 캔들이 있으면 그 구간 원본이 유일한 데이터이므로 삭제하지 않음
+	if len(failed) > 0 {
+		return result, fmt.Errorf("%s 캔들 %d개 저장 실패로 %s 원본 삭제를 건너뜁니다 (첫 실패 %s: %v)",
+			target.Name, len(failed), tf.Name, failed[0].Candle.CandleDateTimeKST, failed[0].Err)
+	}
+
+	defer c.invalidateCache(tf)
+	for _, db := range c.candleDBsBetween(time.Time{}, keepFullSince) {
+		res, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE timestamp < ?", c.table(tf)), cutoff)
+		if err != nil {
+			return result, fmt.Errorf("%s 삭제 실패: %w", tf.Name, err)
+		}
+		n, _ := res.RowsAffected()
+		result.Deleted += int(n)
+	}
+	fmt.Fprintf(c.Output, "[%s] %s %s 이전 캔들 %s개 삭제\n", tf.Name, c.mark(markOK), cutoff, formatNumber(result.Deleted))
+	return result, nil
+}
+
+// bucketStart - timestamp(KST) 가 속한 tf 캔들의 시작 시각 (UTC 경계 기준, 월봉은 매월 1일 09:00, 결과는 KST)
+func bucketStart(tf Timeframe, kst time.Time) time.Time {
+	if tf.Name == "month" {
+		start := time.Date(kst.Year(), kst.Month(), 1, 9, 0, 0, 0, time.UTC)
+		if kst.Before(start) {
+			start = start.AddDate(0, -1, 0)
+		}
+		return start
+	}
+	utc := kst.Add(-9 * time.Hour)
+	return utc.Truncate(time.Duration(tf.Minutes) * time.Minute).Add(9 * time.Hour)
+}
+
+// aggregateCandles - 시간 오름차순 캔들을 tf 구간별 OHLCV 캔들로 합침
+func aggregateCandles(tf Timeframe, candles []Candle) []Candle {
+	var result []Candle
+	for _, candle := range candles {
+		t, err := time.Parse(timestampLayout, candle.CandleDateTimeKST)
+		if err != nil {
+			continue
+		}
+		start := bucketStart(tf, t)
+		timestamp := start.Format(timestampLayout)
+
+		if n := len(result); n > 0 && result[n-1].CandleDateTimeKST == timestamp {
+			last := &result[n-1]
+			last.HighPrice = max(last.HighPrice, candle.HighPrice)
+			last.LowPrice = min(last.LowPrice, candle.LowPrice)
+			last.TradePrice = candle.TradePrice
+			last.CandleAccTradeVolume += candle.CandleAccTradeVolume
+			last.CandleAccTradePrice += candle.CandleAccTradePrice
+			continue
+		}
+
+		bucket := candle
+		bucket.CandleDateTimeKST = timestamp
+		bucket.CandleDateTimeUTC = start.Add(-9 * time.Hour).Format(timestampLayout)
+		bucket.IsInterpolated = false
+		result = append(result, bucket)
+	}
+	return result
+}
+
+func runRetention(args []string) error {
+	fs := flag.NewFlagSet("retention", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "minute1", "합칠 시간단위")
+	keepSince := fs.String("keep-since", "", "이 날짜(KST, YYYY-MM-DD) 이후는 원본 해상도 유지 (필수)")
+	prune := fs.Bool("delete", false, "합친 뒤 이전 원본 캔들 삭제")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *keepSince == "" {
+		return fmt.Errorf("--keep-since 가 필요합니다")
+	}
+	cutoff, err := time.Parse("2006-01-02", *keepSince)
+	if err != nil {
+		return fmt.Errorf("잘못된 --keep-since 날짜: %w", err)
+	}
+	tf, err := findTimeframe(*timeframe)
+	if err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.PruneAfterRetention = *prune
+
+	_, err = collector.ApplyRetention(tf, cutoff)
+	return err
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyRetentionAggregatesAndPrunes(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.PruneAfterRetention = true
+	minute1, minute5 := mustTimeframe(t, "minute1"), mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, minute1, kstMinutes(t0, 20)...)
+
+	result, err := c.ApplyRetention(minute1, t0.Add(10*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Aggregated != 2 || result.Deleted != 10 {
+		t.Errorf("aggregated = %d, deleted = %d, want 2, 10", result.Aggregated, result.Deleted)
+	}
+	if n := countRows(t, c, minute1, ""); n != 10 {
+		t.Errorf("남은 minute1 캔들 %d개, want 10", n)
+	}
+	if n := countRows(t, c, minute1, "timestamp < ?", t0.Add(10*time.Minute).Format(timestampLayout)); n != 0 {
+		t.Errorf("기준 이전 minute1 캔들 %d개 남음", n)
+	}
+
+	candles, err := c.GetCandles(minute5, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("minute5 캔들 %d개, want 2", len(candles))
+	}
+	// seed 가격은 100+i, 고가/저가 ±1, 거래량 1
+	first := candles[0]
+	if first.CandleDateTimeKST != t0.Format(timestampLayout) || first.OpeningPrice != 100 || first.TradePrice != 104 ||
+		first.HighPrice != 105 || first.LowPrice != 99 || first.CandleAccTradeVolume != 5 {
+		t.Errorf("첫 minute5 캔들 = %+v", first)
+	}
+}
+
+func TestApplyRetentionKeepsSourceWhenSaveFails(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	c.PruneAfterRetention = true
+	minute1, minute5 := mustTimeframe(t, "minute1"), mustTimeframe(t, "minute5")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, minute1, kstMinutes(t0, 10)...)
+	// 합계가 Inf 가 되어 두 번째 minute5 캔들은 저장이 거부됨
+	if _, err := c.db.Exec("UPDATE bitcoin_minute1 SET candle_acc_trade_volume = 1e308 WHERE timestamp >= ?",
+		t0.Add(5*time.Minute).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.invalidateCache(minute1)
+
+	result, err := c.ApplyRetention(minute1, t0.Add(10*time.Minute))
+	if err == nil {
+		t.Fatal("상위 캔들 저장 실패인데 오류 없음")
+	}
+	if result.Aggregated != 1 || result.Deleted != 0 {
+		t.Errorf("aggregated = %d, deleted = %d, want 1, 0", result.Aggregated, result.Deleted)
+	}
+	if n := countRows(t, c, minute1, ""); n != 10 {
+		t.Errorf("minute1 캔들 %d개, want 10 (원본이 삭제됨)", n)
+	}
+	if n := countRows(t, c, minute5, ""); n != 1 {
+		t.Errorf("minute5 캔들 %d개, want 1", n)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errRetryBudget - 실행 전체가 공유하는 재시도 예산을 다 써서 재시도하지 않음
+var errRetryBudget = errors.New("재시도 예산 소진")
+
+// retryBudget - 분당 재시도 수를 제한하는 토큰 버킷 (모든 시간단위/요청이 공유)
+//
+// 버킷은 perMinute 개로 가득 찬 채 시작하고 분당 perMinute 개 속도로 다시 찬다. 넓은 장애에서
+// 요청마다 따로 재시도해 요청 폭주가 생기지 않도록 한다.
+type retryBudget struct {
+	mu        sync.Mutex
+	perMinute float64
+	tokens    float64
+	last      time.Time
+	now       func() time.Time
+}
+
+func newRetryBudget(perMinute int, now func() time.Time) *retryBudget {
+	return &retryBudget{perMinute: float64(perMinute), tokens: float64(perMinute), last: now(), now: now}
+}
+
+// take - 재시도 1회분을 차감 (남은 토큰이 없으면 false)
+func (b *retryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.perMinute, b.tokens+elapsed.Minutes()*b.perMinute)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// retryBudget - RetryBudget 으로 만든 공유 재시도 예산 (0 이면 nil, 첫 재시도 시 설정을 읽음)
+func (c *Collector) retryBudget() *retryBudget {
+	c.retryOnce.Do(func() {
+		if c.RetryBudget > 0 {
+			c.retries = newRetryBudget(c.RetryBudget, c.now)
+		}
+	})
+	return c.retries
+}
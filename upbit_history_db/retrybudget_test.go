@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBudgetFailsFastWhenExhausted(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	c.BreakerThreshold = 0
+	c.RetryBudget = 1
+	tf := mustTimeframe(t, "day")
+
+	// 첫 요청이 예산 1회를 쓰고 두 번째 실패에서 소진
+	_, err := c.fetchCandles(context.Background(), tf, "", nil)
+	if !errors.Is(err, errRetryBudget) || calls.Load() != 2 {
+		t.Fatalf("err = %v, 요청 %d회, want errRetryBudget, 2회", err, calls.Load())
+	}
+	// 이후 요청은 재시도 없이 바로 실패 (원래 오류도 함께 전달)
+	started := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := c.fetchCandles(context.Background(), tf, "", nil)
+		if !errors.Is(err, errRetryBudget) || responseStatus(err) != http.StatusInternalServerError {
+			t.Errorf("[%d] err = %v, want errRetryBudget + 500", i, err)
+		}
+	}
+	if n := calls.Load(); n != 7 {
+		t.Errorf("요청 %d회, want 7 (추가 요청마다 1회씩만)", n)
+	}
+	if elapsed := time.Since(started); elapsed > fetchRetryBackoff {
+		t.Errorf("예산 소진 후 %v 걸림, want 백오프 없이", elapsed)
+	}
+}
+
+func TestRetryBudgetRefills(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newRetryBudget(2, func() time.Time { return now })
+	if !b.take() || !b.take() || b.take() {
+		t.Fatal("처음에는 perMinute 개만 허용")
+	}
+	// 분당 2개 속도로 다시 참 (30초에 1개)
+	now = now.Add(30 * time.Second)
+	if !b.take() || b.take() {
+		t.Error("30초 후 1개만 허용해야 함")
+	}
+	// 오래 지나도 perMinute 개까지만
+	now = now.Add(time.Hour)
+	if !b.take() || !b.take() || b.take() {
+		t.Error("가득 차도 perMinute 개까지만")
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+)
+
+// returnRow - candle_returns 한 행 (직전 캔들 timestamp/종가를 함께 저장해 바뀐 행을 찾는다)
+type returnRow struct {
+	timestamp     string
+	prevTimestamp sql.NullString // 첫 캔들이면 NULL
+	price         float64
+	prevPrice     float64
+}
+
+// logReturn - ln(price / prevPrice) (첫 캔들, 0 이하 가격은 WithReturns 와 같이 0)
+func (r returnRow) logReturn() float64 {
+	if !r.prevTimestamp.Valid || r.prevPrice <= 0 || r.price <= 0 {
+		return 0
+	}
+	return math.Log(r.price / r.prevPrice)
+}
+
+// ensureReturnsTable - 저장 수익률 테이블 (기본 DB 파일에 생성)
+func (c *Collector) ensureReturnsTable() error {
+	_, err := c.db.Exec(`
+		CREATE TABLE IF NOT EXISTS candle_returns (
+			market TEXT NOT NULL,
+			timeframe TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			prev_timestamp TEXT,
+			log_return REAL NOT NULL,
+			price REAL NOT NULL,
+			prev_price REAL NOT NULL,
+			PRIMARY KEY (market, timeframe, timestamp)
+		)
+	`)
+	return err
+}
+
+// ComputeReturns - tf 캔들의 직전 캔들 대비 로그 수익률을 candle_returns 테이블에 저장 (갱신한 행 수 반환)
+//
+// 저장된 행과 캔들을 timestamp 순으로 맞춰 보며, 새 캔들, 종가가 바뀐 캔들(재보간 등),
+// 직전 캔들이 바뀐 캔들(과거 구간 수집으로 사이에 캔들이 생긴 경우 포함)만 다시 쓰고
+// 사라진 캔들의 행은 지운다. 첫 캔들의 수익률은 0 이고 prev_timestamp 는 NULL 이다.
+func (c *Collector) ComputeReturns(tf Timeframe) (int, error) {
+	if err := c.ensureReturnsTable(); err != nil {
+		return 0, fmt.Errorf("candle_returns 테이블 생성 실패: %w", err)
+	}
+
+	stale, orphans, err := c.staleReturns(tf)
+	if err != nil {
+		return 0, err
+	}
+	if len(stale) == 0 && len(orphans) == 0 {
+		return 0, nil
+	}
+
+	c.returnsMu.Lock()
+	defer c.returnsMu.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, ts := range orphans {
+		if _, err := tx.Exec("DELETE FROM candle_returns WHERE market = ? AND timeframe = ? AND timestamp = ?",
+			c.market, tf.Name, ts); err != nil {
+			return 0, err
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO candle_returns (market, timeframe, timestamp, prev_timestamp, log_return, price, prev_price)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, r := range stale {
+		if _, err := stmt.Exec(c.market, tf.Name, r.timestamp, r.prevTimestamp, r.logReturn(), r.price, r.prevPrice); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(stale), nil
+}
+
+// staleReturns - 다시 써야 할 행과 지울 timestamp (캔들과 저장 행을 timestamp 순으로 병합)
+//
+// 쓰기는 읽기 커서를 모두 닫은 뒤 해야 하므로 바뀐 행만 메모리에 모은다.
+func (c *Collector) staleReturns(tf Timeframe) (stale []returnRow, orphans []string, err error) {
+	stored, err := c.db.Query(`
+		SELECT timestamp, prev_timestamp, price, prev_price FROM candle_returns
+		WHERE market = ? AND timeframe = ? ORDER BY timestamp ASC
+	`, c.market, tf.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stored.Close()
+
+	var cur *returnRow
+	next := func() error {
+		cur = nil
+		if !stored.Next() {
+			return stored.Err()
+		}
+		var r returnRow
+		if err := stored.Scan(&r.timestamp, &r.prevTimestamp, &r.price, &r.prevPrice); err != nil {
+			return err
+		}
+		cur = &r
+		return nil
+	}
+	if err := next(); err != nil {
+		return nil, nil, err
+	}
+
+	var prev returnRow
+	query := fmt.Sprintf("SELECT timestamp, trade_price FROM %s ORDER BY timestamp ASC", c.table(tf))
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, nil, err
+		}
+		for rows.Next() {
+			want := returnRow{prevTimestamp: sql.NullString{String: prev.timestamp, Valid: prev.timestamp != ""}, prevPrice: prev.price}
+			if err := rows.Scan(&want.timestamp, &want.price); err != nil {
+				rows.Close()
+				return nil, nil, err
+			}
+			for err == nil && cur != nil && cur.timestamp < want.timestamp {
+				orphans = append(orphans, cur.timestamp)
+				err = next()
+			}
+			if err == nil && (cur == nil || *cur != want) {
+				stale = append(stale, want)
+			}
+			if err == nil && cur != nil && cur.timestamp == want.timestamp {
+				err = next()
+			}
+			if err != nil {
+				rows.Close()
+				return nil, nil, err
+			}
+			prev = want
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for cur != nil {
+		orphans = append(orphans, cur.timestamp)
+		if err := next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return stale, orphans, nil
+}
+
+// StoredReturns - ComputeReturns 로 저장한 로그 수익률 조회 (시간 오름차순, 첫 캔들은 0)
+func (c *Collector) StoredReturns(tf Timeframe) ([]IndicatorPoint, error) {
+	if err := c.ensureReturnsTable(); err != nil {
+		return nil, err
+	}
+	rows, err := c.db.Query(`
+		SELECT timestamp, log_return FROM candle_returns
+		WHERE market = ? AND timeframe = ?
+		ORDER BY timestamp ASC
+	`, c.market, tf.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []IndicatorPoint
+	for rows.Next() {
+		var p IndicatorPoint
+		if err := rows.Scan(&p.Timestamp, &p.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func runReturns(args []string) error {
+	fs := flag.NewFlagSet("returns", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	timeframe := fs.String("timeframe", "", "해당 시간단위만 계산 (기본: 전체)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	targets := timeframes
+	if *timeframe != "" {
+		tf, err := findTimeframe(*timeframe)
+		if err != nil {
+			return err
+		}
+		targets = []Timeframe{tf}
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	var errs []error
+	for _, tf := range targets {
+		n, err := collector.ComputeReturns(tf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tf.Name, err))
+			continue
+		}
+		fmt.Fprintf(collector.Output, "[%s] %s 수익률 %d개 갱신\n", tf.Name, collector.mark(markOK), n)
+	}
+	return errors.Join(errs...)
+}
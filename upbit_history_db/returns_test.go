@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeReturnsIncremental(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	minute := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+	seed(t, c, tf, minute(0), minute(1), minute(2), minute(5))
+
+	// compute - ComputeReturns 후 저장값이 WithReturns 참조 계산과 같은지 (갱신 행 수 반환)
+	compute := func() int {
+		t.Helper()
+		n, err := c.ComputeReturns(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := c.StoredReturns(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := WithReturns(candles)
+		if len(stored) != len(want) {
+			t.Fatalf("저장 수익률 %d개, want %d", len(stored), len(want))
+		}
+		for i, r := range want {
+			if stored[i].Timestamp != r.CandleDateTimeKST || !closeTo(stored[i].Value, r.LogReturn, 1e-12) {
+				t.Errorf("[%d] = %+v, want %s %v", i, stored[i], r.CandleDateTimeKST, r.LogReturn)
+			}
+		}
+		return n
+	}
+
+	if n := compute(); n != 4 {
+		t.Errorf("첫 계산 %d행, want 4", n)
+	}
+	if n := compute(); n != 0 {
+		t.Errorf("바뀐 캔들이 없는데 %d행 갱신", n)
+	}
+
+	// 새 캔들은 그 행만, 사이에 생긴 캔들은 그 행과 직전 캔들이 바뀐 다음 행까지
+	seed(t, c, tf, minute(6))
+	if n := compute(); n != 1 {
+		t.Errorf("새 캔들 1개 후 %d행 갱신, want 1", n)
+	}
+	seed(t, c, tf, minute(3))
+	if n := compute(); n != 2 {
+		t.Errorf("사이 캔들 1개 후 %d행 갱신, want 2", n)
+	}
+
+	// 종가가 바뀐 캔들 (재보간 등)은 그 행과 다음 행
+	if _, err := c.db.Exec("UPDATE bitcoin_minute1 SET trade_price = 90 WHERE timestamp = ?", minute(1).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.invalidateCache(tf)
+	if n := compute(); n != 2 {
+		t.Errorf("종가 변경 후 %d행 갱신, want 2", n)
+	}
+
+	// 사라진 캔들의 행은 지움
+	if _, err := c.db.Exec("DELETE FROM bitcoin_minute1 WHERE timestamp = ?", minute(6).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.invalidateCache(tf)
+	compute()
+}
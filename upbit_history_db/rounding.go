@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// RoundingPolicy - saveCandles 가 저장 전에 캔들 값을 반올림하는 방식 (Collector.Rounding 이 nil 이면 원본 그대로)
+//
+// 업비트 응답은 소수 자릿수가 길어 KRW 마켓에서는 원 단위 이하가 의미 없는 잡음이 되고 파일만 커진다.
+// 빈 RoundingPolicy{} 는 아무 값도 바꾸지 않는다.
+type RoundingPolicy struct {
+	// PriceTick - 시가/고가/저가/종가를 이 단위의 배수로 반올림 (0 = 반올림 안 함, 예: 1 이면 원 단위)
+	PriceTick float64
+	// RoundVolume - 거래량을 VolumeDecimals 자리로 반올림할지
+	RoundVolume    bool
+	VolumeDecimals int
+	// RoundValue - 거래대금을 ValueDecimals 자리로 반올림할지
+	RoundValue    bool
+	ValueDecimals int
+}
+
+// validate - 음수 단위나 NaN 처럼 값을 망가뜨리는 설정 거부
+func (p RoundingPolicy) validate() error {
+	if p.PriceTick < 0 || math.IsNaN(p.PriceTick) || math.IsInf(p.PriceTick, 0) {
+		return fmt.Errorf("가격 반올림 단위는 0 이상이어야 합니다: %v", p.PriceTick)
+	}
+	if (p.RoundVolume && p.VolumeDecimals < 0) || (p.RoundValue && p.ValueDecimals < 0) {
+		return fmt.Errorf("소수 자릿수는 0 이상이어야 합니다: 거래량 %d, 거래대금 %d", p.VolumeDecimals, p.ValueDecimals)
+	}
+	return nil
+}
+
+// apply - candle 값을 정책에 맞게 반올림
+func (p RoundingPolicy) apply(candle *Candle) {
+	if p.PriceTick > 0 {
+		for _, v := range []*float64{&candle.OpeningPrice, &candle.HighPrice, &candle.LowPrice, &candle.TradePrice} {
+			*v = math.Round(*v/p.PriceTick) * p.PriceTick
+		}
+	}
+	if p.RoundVolume {
+		candle.CandleAccTradeVolume = roundDecimals(candle.CandleAccTradeVolume, p.VolumeDecimals)
+	}
+	if p.RoundValue {
+		candle.CandleAccTradePrice = roundDecimals(candle.CandleAccTradePrice, p.ValueDecimals)
+	}
+}
+
+// roundDecimals - v 를 소수 decimals 자리로 반올림
+func roundDecimals(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
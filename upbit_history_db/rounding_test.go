@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundingPolicy(t *testing.T) {
+	day := mustTimeframe(t, "day")
+	raw := Candle{
+		Market:               "KRW-BTC",
+		CandleDateTimeKST:    "2024-01-01T09:00:00",
+		OpeningPrice:         100.4,
+		HighPrice:            101.6,
+		LowPrice:             99.49,
+		TradePrice:           100.5,
+		CandleAccTradeVolume: 0.123456789123,
+		CandleAccTradePrice:  12345.678,
+	}
+	stored := func(policy *RoundingPolicy) Candle {
+		t.Helper()
+		c := openTestDB(t, "KRW-BTC")
+		c.Rounding = policy
+		in := []Candle{raw}
+		if _, _, err := c.saveCandles(day, in); err != nil {
+			t.Fatal(err)
+		}
+		if in[0] != raw {
+			t.Errorf("호출자 캔들이 바뀜: %+v", in[0])
+		}
+		candles, err := c.GetCandles(day, time.Time{}, time.Time{})
+		if err != nil || len(candles) != 1 {
+			t.Fatalf("캔들 %d개, err %v", len(candles), err)
+		}
+		return candles[0]
+	}
+
+	for name, policy := range map[string]*RoundingPolicy{"nil": nil, "zero": {}} {
+		got := stored(policy)
+		if got.OpeningPrice != raw.OpeningPrice || got.CandleAccTradeVolume != raw.CandleAccTradeVolume ||
+			got.CandleAccTradePrice != raw.CandleAccTradePrice {
+			t.Errorf("%s 정책인데 값이 바뀜: %+v", name, got)
+		}
+	}
+
+	got := stored(&RoundingPolicy{PriceTick: 1, RoundVolume: true, VolumeDecimals: 8})
+	if got.OpeningPrice != 100 || got.HighPrice != 102 || got.LowPrice != 99 || got.TradePrice != 101 {
+		t.Errorf("가격 반올림 = %+v", got)
+	}
+	if got.CandleAccTradeVolume != 0.12345679 || got.CandleAccTradePrice != raw.CandleAccTradePrice {
+		t.Errorf("거래량/거래대금 = %v, %v, want 0.12345679, %v", got.CandleAccTradeVolume, got.CandleAccTradePrice, raw.CandleAccTradePrice)
+	}
+
+	got = stored(&RoundingPolicy{RoundValue: true, ValueDecimals: 0})
+	if got.CandleAccTradePrice != 12346 || got.OpeningPrice != raw.OpeningPrice || got.CandleAccTradeVolume != raw.CandleAccTradeVolume {
+		t.Errorf("거래대금만 반올림 = %+v", got)
+	}
+}
+
+func TestRoundingPolicyValidate(t *testing.T) {
+	for _, policy := range []RoundingPolicy{
+		{PriceTick: -1},
+		{RoundVolume: true, VolumeDecimals: -1},
+		{RoundValue: true, ValueDecimals: -2},
+	} {
+		if policy.validate() == nil {
+			t.Errorf("%+v 를 허용함", policy)
+		}
+	}
+	if err := (RoundingPolicy{VolumeDecimals: -1}).validate(); err != nil {
+		t.Errorf("꺼진 자릿수는 검사하지 않아야 함: %v", err)
+	}
+}
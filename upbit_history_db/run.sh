@@ -60,7 +60,7 @@ if [[ $REPLY == "1" ]]; then
 
     # 빌드
     echo "🔨 빌드 중..."
-    go build -o upbit-collector main.go
+    go build -o upbit-collector .
     echo "✓ 빌드 완료"
     echo ""
 
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOnSaveReceivesInsertedCandles(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, tf, t0, t0.Add(2*time.Minute))
+
+	var calls int
+	var got []string
+	c.OnSave = func(candles []Candle, hookTF Timeframe) error {
+		calls++
+		if hookTF.Name != tf.Name {
+			t.Errorf("훅 시간단위 = %s, want %s", hookTF.Name, tf.Name)
+		}
+		for _, candle := range candles {
+			got = append(got, candle.CandleDateTimeKST)
+		}
+		return nil
+	}
+
+	var batch []Candle
+	for _, ts := range kstMinutes(t0, 4) {
+		batch = append(batch, testCandle(ts, 100))
+	}
+	saved, _, err := c.saveCandles(tf, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{t0.Add(time.Minute).Format(timestampLayout), t0.Add(3 * time.Minute).Format(timestampLayout)}
+	if saved != 2 || calls != 1 || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("saved = %d, 훅 %d회 %v, want 2, 1회 %v", saved, calls, got, want)
+	}
+
+	// 새로 저장된 캔들이 없으면 훅을 부르지 않음
+	if _, _, err := c.saveCandles(tf, batch); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("중복 배치에서 훅 호출 (%d회)", calls)
+	}
+}
+
+func TestOnSaveErrorHandling(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	hookErr := errors.New("broker down")
+	c.OnSave = func([]Candle, Timeframe) error { return hookErr }
+
+	saved, _, err := c.saveCandles(tf, []Candle{testCandle(t0, 100)})
+	if err != nil || saved != 1 {
+		t.Errorf("saved = %d, err = %v, want 1, nil (훅 오류는 로그만)", saved, err)
+	}
+	if !strings.Contains(out.String(), "OnSave 훅 실패") {
+		t.Errorf("훅 오류 로그 없음: %q", out.String())
+	}
+
+	c.AbortOnHookError = true
+	saved, _, err = c.saveCandles(tf, []Candle{testCandle(t0.Add(time.Minute), 100)})
+	if !errors.Is(err, hookErr) || saved != 1 {
+		t.Errorf("saved = %d, err = %v, want 1, %v", saved, err, hookErr)
+	}
+	if n := countRows(t, c, tf, ""); n != 2 {
+		t.Errorf("저장된 캔들 %d개, want 2 (훅 오류가 커밋을 되돌리면 안 됨)", n)
+	}
+}
+
+// holdWriteLock - c 의 DB 파일에 다른 연결로 쓰기 트랜잭션을 열어 둠 (반환 함수로 해제)
+//
+// c.db 는 잠금을 기다리지 않도록 busy_timeout 을 0 으로 바꾼 연결 하나만 쓴다.
+func holdWriteLock(t *testing.T, c *Collector) (release func()) {
+	t.Helper()
+	c.db.SetMaxOpenConns(1)
+	if _, err := c.db.Exec("PRAGMA busy_timeout = 0"); err != nil {
+		t.Fatal(err)
+	}
+	other, err := sql.Open("sqlite3", c.dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { other.Close() })
+	tx, err := other.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("DELETE FROM bitcoin_day"); err != nil {
+		t.Fatal(err)
+	}
+	var once sync.Once
+	release = func() { once.Do(func() { tx.Rollback() }) }
+	t.Cleanup(release)
+	return release
+}
+
+func TestSaveCandlesRetriesWhileBusy(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	var out bytes.Buffer
+	c.Output = &out
+	tf := mustTimeframe(t, "minute1")
+	release := holdWriteLock(t, c)
+	time.AfterFunc(250*time.Millisecond, release)
+
+	saved, _, err := c.saveCandles(tf, []Candle{testCandle(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 100)})
+	if err != nil || saved != 1 {
+		t.Fatalf("saved = %d, err = %v, want 1, nil", saved, err)
+	}
+	if !strings.Contains(out.String(), "DB 잠금으로 저장 재시도") {
+		t.Errorf("재시도 로그 없음: %q", out.String())
+	}
+}
+
+func TestSaveCandlesBusyGivesUp(t *testing.T) {
+	if testing.Short() {
+		t.Skip("재시도 백오프 전체를 기다림 (약 3초)")
+	}
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	holdWriteLock(t, c)
+
+	saved, _, err := c.saveCandles(tf, []Candle{testCandle(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 100)})
+	var dbErr *DBError
+	if !errors.As(err, &dbErr) || dbErr.Op != "save" || !isBusy(err) {
+		t.Fatalf("err = %v, want 잠금 오류를 감싼 save DBError", err)
+	}
+	if saved != 0 {
+		t.Errorf("saved = %d, want 0", saved)
+	}
+}
+
+func TestSaveCandlesRejectsFutureCandle(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute60")
+	now := time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC) // KST 12:30
+	c.now = func() time.Time { return now }
+	kst := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := testCandle(kst.Add(15*time.Hour), 100) // 기준(KST 13:30)보다 미래
+
+	saved, failed, err := c.saveCandles(tf, []Candle{
+		testCandle(kst.Add(11*time.Hour), 100),
+		future,
+		testCandle(kst.Add(13*time.Hour), 100), // 진행 중 캔들 다음 간격까지는 허용
+	})
+	if err != nil {
+		t.Fatalf("미래 캔들 하나로 배치가 실패함: %v", err)
+	}
+	if saved != 2 {
+		t.Errorf("saved = %d, want 2", saved)
+	}
+	if len(failed) != 1 || failed[0].Candle.CandleDateTimeKST != future.CandleDateTimeKST {
+		t.Fatalf("failed = %+v, want %s 하나", failed, future.CandleDateTimeKST)
+	}
+	if !strings.Contains(failed[0].Err.Error(), "미래") {
+		t.Errorf("거부 사유 = %v", failed[0].Err)
+	}
+	if n := countRows(t, c, tf, "timestamp = ?", future.CandleDateTimeKST); n != 0 {
+		t.Error("미래 캔들이 저장됨")
+	}
+}
+
+func TestSaveCandlesConflictModes(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	existing, fresh := t0.Format(timestampLayout), t0.Add(time.Minute).Format(timestampLayout)
+	tests := []struct {
+		mode       ConflictMode
+		wantSaved  int
+		wantErr    bool
+		wantPrice  float64 // 미리 있던 행의 종가
+		wantNewRow bool
+	}{
+		{ConflictIgnore, 1, false, 100, true},
+		{ConflictReplace, 2, false, 200, true},
+		{ConflictError, 0, true, 100, false}, // 배치 전체 롤백
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			c := openTestDB(t, "KRW-BTC")
+			tf := mustTimeframe(t, "minute1")
+			seed(t, c, tf, t0) // 종가 100
+			c.Conflict = tt.mode
+
+			saved, _, err := c.saveCandles(tf, []Candle{testCandle(t0, 200), testCandle(t0.Add(time.Minute), 200)})
+			if (err != nil) != tt.wantErr || saved != tt.wantSaved {
+				t.Errorf("saved = %d, err = %v, want %d, 오류 %v", saved, err, tt.wantSaved, tt.wantErr)
+			}
+			if n := countRows(t, c, tf, "timestamp = ? AND trade_price = ?", existing, tt.wantPrice); n != 1 {
+				t.Errorf("기존 행 종가가 %v 가 아님", tt.wantPrice)
+			}
+			if n := countRows(t, c, tf, "timestamp = ?", fresh); (n == 1) != tt.wantNewRow {
+				t.Errorf("새 행 저장 = %v, want %v", n == 1, tt.wantNewRow)
+			}
+		})
+	}
+}
+
+func TestSaveCandlesKeepsGoodRowsOfBatch(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	// DB 제약으로 음수 가격 행만 INSERT 가 실패하게 함 (RAISE(ABORT) 는 그 문장만 되돌림)
+	if _, err := c.db.Exec(`CREATE TRIGGER reject_negative BEFORE INSERT ON bitcoin_minute1
+		WHEN NEW.trade_price < 0 BEGIN SELECT RAISE(ABORT, 'negative price'); END`); err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	batch := make([]Candle, 200)
+	for i := range batch {
+		batch[i] = testCandle(t0.Add(time.Duration(i)*time.Minute), 100)
+	}
+	batch[50].TradePrice = -1
+	batch[120].CandleDateTimeKST = "not-a-time"
+
+	saved, failed, err := c.saveCandles(tf, batch)
+	if err != nil {
+		t.Fatalf("잘못된 행 때문에 배치 전체가 실패함: %v", err)
+	}
+	if saved != 198 || countRows(t, c, tf, "") != 198 {
+		t.Errorf("saved = %d, 행 %d개, want 198", saved, countRows(t, c, tf, ""))
+	}
+	if len(failed) != 2 {
+		t.Fatalf("failed = %+v, want 2개", failed)
+	}
+	reasons := make(map[string]string)
+	for _, f := range failed {
+		reasons[f.Candle.CandleDateTimeKST] = f.Err.Error()
+	}
+	if r := reasons[batch[50].CandleDateTimeKST]; !strings.Contains(r, "negative price") {
+		t.Errorf("%s 실패 사유 = %q", batch[50].CandleDateTimeKST, r)
+	}
+	if _, ok := reasons["not-a-time"]; !ok {
+		t.Errorf("잘못된 timestamp 가 실패 목록에 없음: %v", reasons)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"sync"
+)
+
+// Scheduler - 여러 (마켓, 시간단위) 요청을 하나의 rate limiter 로 공정하게 배분
+//
+// 요청마다 lane(예: "KRW-BTC/minute1")을 지정하고, 대기 중인 lane 을 등록 순서대로
+// 돌아가며 한 번에 하나씩 허가한다. 특정 goroutine 이 토큰을 연달아 가져가 다른 lane 이
+// 굶는 일이 없도록 한다.
+type Scheduler struct {
+	limiter *RateLimiter
+
+	mu     sync.Mutex
+	lanes  []*schedLane
+	byName map[string]*schedLane
+	next   int // 다음에 확인할 lanes 인덱스
+
+	signal chan struct{} // 새 대기 요청 알림 (버퍼 1)
+	done   chan struct{}
+	once   sync.Once
+}
+
+type schedLane struct {
+	name    string
+	waiting []chan struct{} // 허가를 기다리는 요청 (도착 순)
+	granted int
+}
+
+// NewScheduler - limiter 속도로 허가를 배분하는 스케줄러 (사용 후 Close 필요)
+func NewScheduler(limiter *RateLimiter) *Scheduler {
+	s := &Scheduler{
+		limiter: limiter,
+		byName:  make(map[string]*schedLane),
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Wait - lane 차례가 오고 rate limit 를 통과할 때까지 대기 (Close 이후에는 바로 반환)
+func (s *Scheduler) Wait(lane string) {
+	grant := make(chan struct{})
+
+	s.mu.Lock()
+	l, ok := s.byName[lane]
+	if !ok {
+		l = &schedLane{name: lane}
+		s.byName[lane] = l
+		s.lanes = append(s.lanes, l)
+	}
+	l.waiting = append(l.waiting, grant)
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-grant:
+	case <-s.done:
+	}
+}
+
+// Granted - lane 별 누적 허가 수
+func (s *Scheduler) Granted() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int, len(s.lanes))
+	for _, l := range s.lanes {
+		counts[l.name] = l.granted
+	}
+	return counts
+}
+
+// Close - 배분 goroutine 종료 (대기 중인 Wait 도 반환)
+func (s *Scheduler) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *Scheduler) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.signal:
+		}
+
+		for {
+			s.mu.Lock()
+			grant := s.nextGrant()
+			s.mu.Unlock()
+			if grant == nil {
+				break
+			}
+
+			s.limiter.Wait()
+			close(grant)
+		}
+	}
+}
+
+// nextGrant - s.next 부터 돌아가며 대기 요청이 있는 첫 lane 의 요청을 꺼냄 (s.mu 보유 상태에서 호출)
+func (s *Scheduler) nextGrant() chan struct{} {
+	for i := 0; i < len(s.lanes); i++ {
+		idx := (s.next + i) % len(s.lanes)
+		l := s.lanes[idx]
+		if len(l.waiting) == 0 {
+			continue
+		}
+
+		grant := l.waiting[0]
+		l.waiting = l.waiting[1:]
+		l.granted++
+		s.next = idx + 1
+		return grant
+	}
+	return nil
+}
+
+// CollectAllMarkets - 여러 마켓을 동시에 수집하며 모든 API 요청을 하나의 Scheduler 로 공정하게 배분
+//
+// 각 Collector 의 rate limiter 대신 limiter 하나를 공유한다. 결과는 collectors 순서와 같다.
+func CollectAllMarkets(collectors []*Collector, limiter *RateLimiter) [][]CollectResult {
+	scheduler := NewScheduler(limiter)
+	defer scheduler.Close()
+
+	results := make([][]CollectResult, len(collectors))
+	var wg sync.WaitGroup
+	for i, c := range collectors {
+		wg.Add(1)
+		go func(i int, c *Collector) {
+			defer wg.Done()
+			c.scheduler = scheduler
+			defer func() { c.scheduler = nil }()
+			results[i] = c.CollectAll()
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRoundRobin(t *testing.T) {
+	s := NewScheduler(NewRateLimiter(200))
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	worker := func(lane string) {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.Wait(lane)
+		}
+	}
+	// lane A 는 goroutine 5개로 요청을 몰아도 B 와 번갈아 허가받아야 함
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go worker("A")
+	}
+	wg.Add(1)
+	go worker("B")
+	time.Sleep(500 * time.Millisecond)
+	close(stop)
+	s.Close()
+	wg.Wait()
+
+	granted := s.Granted()
+	if granted["A"] < 20 || granted["B"] < 20 {
+		t.Fatalf("허가 수 = %v, 너무 적음", granted)
+	}
+	if d := granted["A"] - granted["B"]; d > 2 || d < -2 {
+		t.Errorf("허가 수 = %v, want 거의 같음", granted)
+	}
+}
+
+func TestCollectAllMarketsBalancedProgress(t *testing.T) {
+	f := &fakeUpbit{head: time.Now().UTC().Truncate(time.Minute).Add(-time.Minute)}
+	fake := f.handler(t)
+	var mu sync.Mutex
+	calls := map[string]int{}
+	maxLead := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Query().Get("market")]++
+		lead := calls["KRW-BTC"] - calls["KRW-ETH"]
+		maxLead = max(maxLead, lead, -lead)
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	btc, eth := openTestDB(t, "KRW-BTC"), openTestDB(t, "KRW-ETH")
+	for _, c := range []*Collector{btc, eth} {
+		c.apiURL = srv.URL
+		c.MaxPages = 3
+	}
+	results := CollectAllMarkets([]*Collector{btc, eth}, NewRateLimiter(100))
+	if len(results) != 2 {
+		t.Fatalf("결과 %d개, want 2", len(results))
+	}
+	for i, market := range []*Collector{btc, eth} {
+		for _, result := range results[i] {
+			if result.Err != nil {
+				t.Errorf("%s %s: %v", market.market, result.Timeframe, result.Err)
+			}
+		}
+		if n := countRows(t, market, mustTimeframe(t, "minute1"), "is_interpolated = 0"); n != 600 {
+			t.Errorf("%s minute1 %d개, want 600", market.market, n)
+		}
+	}
+
+	// (마켓, 시간단위) 순서로 한 바퀴씩 돌므로 한 마켓이 앞서도 시간단위 수 이내
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["KRW-BTC"] != calls["KRW-ETH"] {
+		t.Errorf("요청 수 = %v, want 같음", calls)
+	}
+	if maxLead > len(timeframes) {
+		t.Errorf("한 마켓이 최대 %d 요청 앞섬, want %d 이하", maxLead, len(timeframes))
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// column - 테이블 컬럼 이름과 CREATE TABLE 선언
+type column struct {
+	name string
+	decl string
+}
+
+// tableSchema - 테이블 컬럼 목록 (CREATE/INSERT/SELECT SQL 을 모두 여기서 생성)
+type tableSchema struct {
+	columns []column
+}
+
+// candleSchema - 시간단위별 캔들 테이블 (컬럼 순서는 candleFields 와 같아야 함)
+var candleSchema = tableSchema{columns: []column{
+	{"timestamp", "TEXT PRIMARY KEY"},
+	{"opening_price", "REAL NOT NULL"},
+	{"high_price", "REAL NOT NULL"},
+	{"low_price", "REAL NOT NULL"},
+	{"trade_price", "REAL NOT NULL"},
+	{"candle_acc_trade_volume", "REAL NOT NULL"},
+	{"candle_acc_trade_price", "REAL NOT NULL"},
+	{"is_interpolated", "INTEGER DEFAULT 0"},
+	{"is_provisional", "INTEGER DEFAULT 0"},
+}}
+
+// tickSchema - 마켓별 체결 테이블 (컬럼 순서는 tickFields 와 같아야 함)
+var tickSchema = tableSchema{columns: []column{
+	{"sequential_id", "INTEGER PRIMARY KEY"},
+	{"timestamp", "INTEGER NOT NULL"},
+	{"trade_price", "REAL NOT NULL"},
+	{"trade_volume", "REAL NOT NULL"},
+	{"ask_bid", "TEXT NOT NULL"},
+}}
+
+func (s tableSchema) names() string {
+	names := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		names[i] = col.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// createSQL - CREATE TABLE IF NOT EXISTS 문
+func (s tableSchema) createSQL(table string) string {
+	decls := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		decls[i] = col.name + " " + col.decl
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", table, strings.Join(decls, ",\n\t"))
+}
+
+// addMissingColumns - 예전 버전이 만든 table 에 없는 컬럼을 ALTER TABLE ADD COLUMN 으로 추가 (추가한 컬럼 이름 반환)
+//
+// 새 버전에서 컬럼만 늘어난 경우를 위한 자동 이전이다. 기존 행은 기본값을 갖도록 NOT NULL 컬럼에는
+// DEFAULT 를 붙이며, PRIMARY KEY 컬럼은 나중에 추가할 수 없으므로 오류를 반환한다.
+func (s tableSchema) addMissingColumns(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[name] = true
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	for _, col := range s.columns {
+		if existing[col.name] {
+			continue
+		}
+		decl, err := col.addDecl()
+		if err != nil {
+			return added, err
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, col.name, decl)); err != nil {
+			return added, fmt.Errorf("%s 컬럼 추가 실패: %w", col.name, err)
+		}
+		added = append(added, col.name)
+	}
+	return added, nil
+}
+
+// addDecl - ALTER TABLE ADD COLUMN 용 선언 (NOT NULL 이면 기본값 추가)
+func (col column) addDecl() (string, error) {
+	upper := strings.ToUpper(col.decl)
+	if strings.Contains(upper, "PRIMARY KEY") {
+		return "", fmt.Errorf("%s 는 기본 키 컬럼이라 추가할 수 없습니다", col.name)
+	}
+	if !strings.Contains(upper, "NOT NULL") || strings.Contains(upper, "DEFAULT") {
+		return col.decl, nil
+	}
+	if strings.HasPrefix(upper, "TEXT") {
+		return col.decl + " DEFAULT ''", nil
+	}
+	return col.decl + " DEFAULT 0", nil
+}
+
+// insertSQL - 전체 컬럼 INSERT 문 (verb: "INSERT", "INSERT OR REPLACE", "INSERT OR IGNORE")
+func (s tableSchema) insertSQL(verb, table string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(s.columns)), ", ")
+	return fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", verb, table, s.names(), placeholders)
+}
+
+// selectSQL - 전체 컬럼 SELECT 문 (WHERE/ORDER BY 는 호출자가 덧붙임)
+func (s tableSchema) selectSQL(table string) string {
+	return fmt.Sprintf("SELECT %s FROM %s", s.names(), table)
+}
+
+// candleFields - candleSchema 컬럼 순서의 Candle 필드 포인터
+//
+// INSERT 인자(database/sql 이 포인터를 역참조)와 Scan 대상으로 함께 쓰므로 저장/조회 순서가 어긋날 수 없다.
+func candleFields(c *Candle) []any {
+	return []any{
+		&c.CandleDateTimeKST,
+		&c.OpeningPrice,
+		&c.HighPrice,
+		&c.LowPrice,
+		&c.TradePrice,
+		&c.CandleAccTradeVolume,
+		&c.CandleAccTradePrice,
+		(*flagColumn)(&c.IsInterpolated),
+		(*flagColumn)(&c.IsProvisional),
+	}
+}
+
+// tickFields - tickSchema 컬럼 순서의 Tick 필드 포인터
+func tickFields(t *Tick) []any {
+	return []any{&t.SequentialID, &t.Timestamp, &t.TradePrice, &t.TradeVolume, &t.AskBid}
+}
+
+// flagColumn - is_interpolated, is_provisional 같은 0/1 INTEGER 컬럼 (0 이 아니면 true)
+type flagColumn bool
+
+func (f flagColumn) Value() (driver.Value, error) {
+	if f {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+func (f *flagColumn) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*f = v != 0
+	case nil:
+		*f = false
+	default:
+		return fmt.Errorf("플래그 컬럼 값 해석 실패: %v", src)
+	}
+	return nil
+}
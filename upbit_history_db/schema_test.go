@@ -0,0 +1,165 @@
+package main
+
+import (
+	"database/sql"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCandleSchemaColumnOrder(t *testing.T) {
+	insert := candleSchema.insertSQL("INSERT", "bitcoin_minute1")
+	selected := candleSchema.selectSQL("bitcoin_minute1")
+	for _, query := range []string{insert, selected} {
+		if !strings.Contains(query, candleSchema.names()) {
+			t.Errorf("컬럼 목록이 스키마 순서와 다름: %s", query)
+		}
+	}
+	if got := strings.Count(insert, "?"); got != len(candleSchema.columns) {
+		t.Errorf("placeholder %d개, want %d", got, len(candleSchema.columns))
+	}
+	if n := len(candleFields(&Candle{})); n != len(candleSchema.columns) {
+		t.Fatalf("candleFields %d개, 컬럼 %d개", n, len(candleSchema.columns))
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "schema.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(candleSchema.createSQL("bitcoin_minute1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 필드마다 다른 값이라 순서가 하나라도 어긋나면 컬럼별 값이 바뀜
+	in := Candle{
+		CandleDateTimeKST:    "2024-01-01T09:00:00",
+		OpeningPrice:         1,
+		HighPrice:            2,
+		LowPrice:             3,
+		TradePrice:           4,
+		CandleAccTradeVolume: 5,
+		CandleAccTradePrice:  6,
+		IsInterpolated:       true,
+	}
+	if _, err := db.Exec(insert, candleFields(&in)...); err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]any{
+		"timestamp":               "2024-01-01T09:00:00",
+		"opening_price":           1.0,
+		"high_price":              2.0,
+		"low_price":               3.0,
+		"trade_price":             4.0,
+		"candle_acc_trade_volume": 5.0,
+		"candle_acc_trade_price":  6.0,
+		"is_interpolated":         int64(1),
+		"is_provisional":          int64(0),
+	}
+	for _, col := range candleSchema.columns {
+		var v any
+		if err := db.QueryRow("SELECT " + col.name + " FROM bitcoin_minute1").Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		if s, ok := v.([]byte); ok {
+			v = string(s)
+		}
+		if v != byName[col.name] {
+			t.Errorf("%s = %v, want %v", col.name, v, byName[col.name])
+		}
+	}
+
+	var out Candle
+	if err := db.QueryRow(selected).Scan(candleFields(&out)...); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("조회 = %+v, want %+v", out, in)
+	}
+}
+
+func TestInitDatabaseAddsMissingColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 보간/진행 중 표시와 거래대금이 없던 예전 버전의 테이블
+	if _, err := db.Exec(`CREATE TABLE bitcoin_minute1 (
+		timestamp TEXT PRIMARY KEY,
+		opening_price REAL NOT NULL,
+		high_price REAL NOT NULL,
+		low_price REAL NOT NULL,
+		trade_price REAL NOT NULL,
+		candle_acc_trade_volume REAL NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO bitcoin_minute1 VALUES ('2024-01-01T09:00:00', 1, 2, 3, 4, 5)"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	c, err := NewCollector(path, "KRW-BTC")
+	if err != nil {
+		t.Fatalf("예전 스키마 DB 를 열지 못함: %v", err)
+	}
+	c.Output = io.Discard
+	defer c.Close()
+
+	rows, err := c.db.Query("PRAGMA table_info(bitcoin_minute1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			t.Fatal(err)
+		}
+		have[name] = true
+	}
+	rows.Close()
+	for _, col := range candleSchema.columns {
+		if !have[col.name] {
+			t.Errorf("%s 컬럼이 추가되지 않음", col.name)
+		}
+	}
+
+	// 기존 행은 기본값으로 읽히고 새 행도 저장됨
+	tf := mustTimeframe(t, "minute1")
+	candles, err := c.GetCandles(tf, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 1 || candles[0].CandleAccTradePrice != 0 || candles[0].IsInterpolated || candles[0].TradePrice != 4 {
+		t.Errorf("기존 행 = %+v", candles)
+	}
+	if _, _, err := c.saveCandles(tf, []Candle{testCandle(time.Date(2024, 1, 1, 9, 1, 0, 0, time.UTC), 100)}); err != nil {
+		t.Errorf("컬럼 추가 후 저장 실패: %v", err)
+	}
+}
+
+func TestAddDeclDefaults(t *testing.T) {
+	for _, tc := range []struct {
+		col  column
+		want string
+	}{
+		{column{"candle_acc_trade_price", "REAL NOT NULL"}, "REAL NOT NULL DEFAULT 0"},
+		{column{"ask_bid", "TEXT NOT NULL"}, "TEXT NOT NULL DEFAULT ''"},
+		{column{"is_provisional", "INTEGER DEFAULT 0"}, "INTEGER DEFAULT 0"},
+	} {
+		if got, err := tc.col.addDecl(); err != nil || got != tc.want {
+			t.Errorf("%s addDecl = %q, %v, want %q", tc.col.name, got, err, tc.want)
+		}
+	}
+	if _, err := (column{"timestamp", "TEXT PRIMARY KEY"}).addDecl(); err == nil {
+		t.Error("기본 키 컬럼 추가에 오류 없음")
+	}
+}
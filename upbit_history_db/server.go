@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// /candles 조회 개수 제한
+const (
+	defaultCandleLimit = 500
+	maxCandleLimit     = 5000
+)
+
+// CandlesResponse - GET /candles 응답
+type CandlesResponse struct {
+	Market     string   `json:"market"`
+	Timeframe  string   `json:"timeframe"`
+	Candles    []Candle `json:"candles"`
+	NextCursor string   `json:"next_cursor,omitempty"` // 다음 페이지 요청 시 cursor 로 전달
+}
+
+// apiServer - 수집한 캔들을 JSON 으로 제공하는 읽기 전용 HTTP API
+type apiServer struct {
+	collector  *Collector
+	corsOrigin string // 비어 있으면 CORS 헤더 없음
+}
+
+func newAPIServer(c *Collector, corsOrigin string) http.Handler {
+	s := &apiServer{collector: c, corsOrigin: corsOrigin}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/candles", s.handleCandles)
+	mux.HandleFunc("/stats", s.handleStats)
+	return s.withCORS(mux)
+}
+
+func (s *apiServer) withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.corsOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", s.corsOrigin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "GET 만 지원합니다")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCandles - GET /candles?market=&timeframe=&from=&to=&limit=&cursor=
+func (s *apiServer) handleCandles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if market := q.Get("market"); market != "" && market != s.collector.market {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("제공하지 않는 마켓: %s", market))
+		return
+	}
+	if q.Get("timeframe") == "" {
+		writeError(w, http.StatusBadRequest, "timeframe 이 필요합니다")
+		return
+	}
+	tf, err := findTimeframe(q.Get("timeframe"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	from, err := parseQueryTime(q.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "잘못된 from: "+err.Error())
+		return
+	}
+	to, err := parseQueryTime(q.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "잘못된 to: "+err.Error())
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		writeError(w, http.StatusBadRequest, "from 이 to 보다 늦습니다")
+		return
+	}
+
+	limit := defaultCandleLimit
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxCandleLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit 은 1~%d 사이여야 합니다", maxCandleLimit))
+			return
+		}
+	}
+
+	// cursor - 이전 페이지 마지막 캔들 timestamp (그 다음 캔들부터 조회)
+	if cursor := q.Get("cursor"); cursor != "" {
+		after, err := time.Parse(timestampLayout, cursor)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "잘못된 cursor")
+			return
+		}
+		if next := after.Add(time.Second); next.After(from) {
+			from = next
+		}
+	}
+
+	// 다음 페이지 존재 여부 확인용으로 하나 더 조회
+	candles, err := s.collector.queryCandles(tf, from, to, limit+1)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := CandlesResponse{Market: s.collector.market, Timeframe: tf.Name, Candles: candles}
+	if len(candles) > limit {
+		resp.Candles = candles[:limit]
+		resp.NextCursor = candles[limit-1].CandleDateTimeKST
+	}
+	if resp.Candles == nil {
+		resp.Candles = []Candle{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleStats - GET /stats
+func (s *apiServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.collector.Statistics()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// parseQueryTime - "2006-01-02" 또는 "2006-01-02T15:04:05" (KST), 빈 값은 zero
+func parseQueryTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(timestampLayout, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiErrorResponse - 오류 응답 본문
+type apiErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiErrorResponse{Error: message})
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	addr := fs.String("addr", ":8080", "HTTP 서버 주소")
+	cors := fs.String("cors-origin", "", "Access-Control-Allow-Origin 값 (예: *, 비우면 CORS 헤더 없음)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newAPIServer(collector, *cors),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("%s 읽기 API 서버 시작: %s (GET /candles, /stats)\n", collector.mark(markLaunch), *addr)
+	return server.ListenAndServe()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestAPIServer(t *testing.T, cors string) http.Handler {
+	t.Helper()
+	c := openTestDB(t, "KRW-BTC")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	seed(t, c, mustTimeframe(t, "day"), t0, t0.AddDate(0, 0, 1), t0.AddDate(0, 0, 2))
+	return newAPIServer(c, cors)
+}
+
+func serveGet(h http.Handler, method, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, url, nil))
+	return rec
+}
+
+func TestServeCandlesPagination(t *testing.T) {
+	h := newTestAPIServer(t, "")
+
+	var page CandlesResponse
+	rec := serveGet(h, http.MethodGet, "/candles?timeframe=day&limit=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Candles) != 2 || page.NextCursor != "2024-01-02T09:00:00" || page.Timeframe != "day" || page.Market != "KRW-BTC" {
+		t.Fatalf("첫 페이지 = %d개, cursor %q", len(page.Candles), page.NextCursor)
+	}
+
+	rec = serveGet(h, http.MethodGet, "/candles?timeframe=day&limit=2&cursor="+page.NextCursor)
+	page = CandlesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Candles) != 1 || page.Candles[0].CandleDateTimeKST != "2024-01-03T09:00:00" || page.NextCursor != "" {
+		t.Errorf("두 번째 페이지 = %+v", page)
+	}
+
+	rec = serveGet(h, http.MethodGet, "/candles?timeframe=day&from=2025-01-01")
+	page = CandlesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, err = %v", rec.Code, err)
+	}
+	if page.Candles == nil || len(page.Candles) != 0 {
+		t.Errorf("빈 결과는 [] 여야 함: %s", rec.Body)
+	}
+}
+
+func TestServeCandlesValidation(t *testing.T) {
+	h := newTestAPIServer(t, "")
+	for url, want := range map[string]int{
+		"/candles":                                             http.StatusBadRequest,
+		"/candles?timeframe=minute2":                           http.StatusNotFound,
+		"/candles?timeframe=day&market=KRW-ETH":                http.StatusNotFound,
+		"/candles?timeframe=day&from=yesterday":                http.StatusBadRequest,
+		"/candles?timeframe=day&from=2024-02-01&to=2024-01-01": http.StatusBadRequest,
+		"/candles?timeframe=day&limit=0":                       http.StatusBadRequest,
+		"/candles?timeframe=day&limit=5001":                    http.StatusBadRequest,
+		"/candles?timeframe=day&cursor=abc":                    http.StatusBadRequest,
+	} {
+		rec := serveGet(h, http.MethodGet, url)
+		if rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", url, rec.Code, want)
+			continue
+		}
+		var body apiErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+			t.Errorf("%s: 오류 메시지 없음: %s", url, rec.Body)
+		}
+	}
+	if rec := serveGet(h, http.MethodPost, "/candles?timeframe=day"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestServeStatsAndCORS(t *testing.T) {
+	h := newTestAPIServer(t, "*")
+
+	rec := serveGet(h, http.MethodGet, "/stats")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("status = %d, CORS = %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	var stats []TimeframeStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range stats {
+		if s.Timeframe == "day" && s.Total != 3 {
+			t.Errorf("day total = %d, want 3", s.Total)
+		}
+	}
+	if rec := serveGet(h, http.MethodOptions, "/candles"); rec.Code != http.StatusNoContent {
+		t.Errorf("OPTIONS status = %d, want 204", rec.Code)
+	}
+	if rec := serveGet(newTestAPIServer(t, ""), http.MethodGet, "/stats"); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("--cors-origin 없이 CORS 헤더가 붙음")
+	}
+}
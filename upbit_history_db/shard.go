@@ -0,0 +1,142 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// yearShards - 연도별 캔들 DB 파일 (upbit_bitcoin.db → upbit_bitcoin_2021.db, upbit_bitcoin_2022.db ...)
+//
+// 캔들 테이블만 연도 파일에 나뉘어 저장되고, 그 외 테이블은 기본 DB 파일(c.db)에 남는다.
+type yearShards struct {
+	mu     sync.Mutex
+	prefix string // 확장자를 뺀 기본 DB 경로
+	ext    string
+	dbs    map[int]*sql.DB
+	init   func(db *sql.DB) error // 새 파일의 캔들 테이블 생성
+}
+
+func (s *yearShards) path(year int) string {
+	return fmt.Sprintf("%s_%d%s", s.prefix, year, s.ext)
+}
+
+// get - 해당 연도 DB (없으면 파일 생성 후 테이블 초기화)
+func (s *yearShards) get(year int) (*sql.DB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if db, ok := s.dbs[year]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open("sqlite3", sqliteDSN(s.path(year)))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.init(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%d년 DB 초기화 실패: %w", year, err)
+	}
+	s.dbs[year] = db
+	return db, nil
+}
+
+// years - 열려 있는 연도 (오름차순)
+func (s *yearShards) years() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	years := make([]int, 0, len(s.dbs))
+	for year := range s.dbs {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+func (s *yearShards) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for year, db := range s.dbs {
+		if err := db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.dbs, year)
+	}
+	return firstErr
+}
+
+// EnableYearSharding - 캔들 테이블을 연도별 DB 파일로 나눠 저장 (기존 연도 파일은 자동으로 연결)
+func (c *Collector) EnableYearSharding() error {
+	ext := filepath.Ext(c.dbPath)
+	shards := &yearShards{
+		prefix: strings.TrimSuffix(c.dbPath, ext),
+		ext:    ext,
+		dbs:    make(map[int]*sql.DB),
+		init:   c.initCandleTables,
+	}
+
+	matches, err := filepath.Glob(shards.prefix + "_[0-9][0-9][0-9][0-9]" + ext)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		yearText := strings.TrimSuffix(strings.TrimPrefix(match, shards.prefix+"_"), ext)
+		year, err := strconv.Atoi(yearText)
+		if err != nil {
+			continue
+		}
+		if _, err := shards.get(year); err != nil {
+			shards.close()
+			return err
+		}
+	}
+
+	c.shards = shards
+	return nil
+}
+
+// candleDBs - 캔들 테이블이 있는 DB 목록 (단일 파일이면 c.db, 연도 분할이면 연도 오름차순)
+func (c *Collector) candleDBs() []*sql.DB {
+	return c.candleDBsBetween(time.Time{}, time.Time{})
+}
+
+// candleDBsBetween - from~to 연도에 해당하는 캔들 DB 목록 (zero 값이면 해당 방향 제한 없음)
+func (c *Collector) candleDBsBetween(from, to time.Time) []*sql.DB {
+	if c.shards == nil {
+		return []*sql.DB{c.db}
+	}
+
+	var dbs []*sql.DB
+	for _, year := range c.shards.years() {
+		if (!from.IsZero() && year < from.Year()) || (!to.IsZero() && year > to.Year()) {
+			continue
+		}
+		db, err := c.shards.get(year)
+		if err == nil {
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs
+}
+
+// candleDBFor - timestamp(KST) 캔들이 저장될 DB
+func (c *Collector) candleDBFor(timestamp string) (*sql.DB, error) {
+	if c.shards == nil {
+		return c.db, nil
+	}
+
+	t, err := time.Parse(timestampLayout, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 timestamp %q: %w", timestamp, err)
+	}
+	return c.shards.get(t.Year())
+}
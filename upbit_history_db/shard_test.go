@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestYearShardingSplitsByYear(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	if err := c.EnableYearSharding(); err != nil {
+		t.Fatal(err)
+	}
+	tf := mustTimeframe(t, "minute1")
+	start := time.Date(2023, 12, 31, 23, 58, 0, 0, time.UTC)
+	seed(t, c, tf, kstMinutes(start, 5)...)
+
+	for _, year := range []int{2023, 2024} {
+		if _, err := os.Stat(c.shards.path(year)); err != nil {
+			t.Errorf("%d년 DB 파일 없음: %v", year, err)
+		}
+	}
+	db2023, _ := c.shards.get(2023)
+	db2024, _ := c.shards.get(2024)
+	var n2023, n2024 int
+	if err := db2023.QueryRow("SELECT COUNT(*) FROM bitcoin_minute1").Scan(&n2023); err != nil {
+		t.Fatal(err)
+	}
+	if err := db2024.QueryRow("SELECT COUNT(*) FROM bitcoin_minute1").Scan(&n2024); err != nil {
+		t.Fatal(err)
+	}
+	if n2023 != 2 || n2024 != 3 {
+		t.Errorf("2023년 %d개, 2024년 %d개, want 2, 3", n2023, n2024)
+	}
+
+	candles, err := c.GetCandles(tf, start, start.Add(4*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 5 {
+		t.Fatalf("연도를 걸친 조회 %d개, want 5", len(candles))
+	}
+	for i, candle := range candles {
+		if want := start.Add(time.Duration(i) * time.Minute).Format(timestampLayout); candle.CandleDateTimeKST != want {
+			t.Errorf("candles[%d] = %s, want %s", i, candle.CandleDateTimeKST, want)
+		}
+	}
+}
+
+func TestSaveCandlesKeepsCommittedShardOnFailure(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	if err := c.EnableYearSharding(); err != nil {
+		t.Fatal(err)
+	}
+	var hooked []Candle
+	c.OnSave = func(candles []Candle, tf Timeframe) error {
+		hooked = append(hooked, candles...)
+		return nil
+	}
+	tf := mustTimeframe(t, "minute1")
+	// 2024년 파일의 테이블이 없어 두 번째 파일 저장이 실패함
+	db2024, err := c.shards.get(2024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db2024.Exec("DROP TABLE bitcoin_minute1"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2023, 12, 31, 23, 57, 0, 0, time.UTC)
+	candles := make([]Candle, 5)
+	for i, ts := range kstMinutes(start, 5) {
+		candles[i] = testCandle(ts, 100)
+	}
+	saved, _, err := c.saveCandles(tf, candles)
+	if err == nil {
+		t.Fatal("2024년 저장 실패인데 오류 없음")
+	}
+	if saved != 3 || len(hooked) != 3 {
+		t.Errorf("saved = %d, OnSave 캔들 %d개, want 3 (2023년 파일에 커밋된 캔들)", saved, len(hooked))
+	}
+	db2023, err := c.shards.get(2023)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db2023.QueryRow("SELECT COUNT(*) FROM bitcoin_minute1").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("2023년 파일 캔들 %d개, want 3", n)
+	}
+}
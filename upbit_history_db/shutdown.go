@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+// walCheckpoint - PRAGMA wal_checkpoint 결과
+type walCheckpoint struct {
+	Busy         bool // 다른 연결이 읽기/쓰기 중이라 일부만 반영
+	Log          int  // 남은 WAL 페이지 수 (TRUNCATE 성공 시 0, WAL 모드가 아니면 -1)
+	Checkpointed int  // 반영된 페이지 수 (TRUNCATE 성공 시 0)
+}
+
+// checkpointWAL - WAL 내용을 DB 파일에 모두 반영하고 -wal 파일을 비움 (WAL 모드가 아니면 아무것도 하지 않음)
+func checkpointWAL(db *sql.DB) (walCheckpoint, error) {
+	var busy int
+	var cp walCheckpoint
+	err := db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &cp.Log, &cp.Checkpointed)
+	cp.Busy = busy != 0
+	return cp, err
+}
+
+// checkpointAll - 기본 DB 와 열린 연도 DB 파일마다 WAL 체크포인트 후 결과 출력
+func (c *Collector) checkpointAll() {
+	report := func(path string, db *sql.DB) {
+		var walSize int64
+		if info, err := os.Stat(path + "-wal"); err == nil {
+			walSize = info.Size()
+		}
+		cp, err := checkpointWAL(db)
+		name := filepath.Base(path)
+		switch {
+		case err != nil:
+			fmt.Fprintf(c.Output, "%s WAL 체크포인트 실패 (%s): %v\n", c.mark(markWarn), name, err)
+		case cp.Log < 0:
+			// WAL 모드가 아님
+		case cp.Busy:
+			fmt.Fprintf(c.Output, "%s WAL 체크포인트 (%s): 사용 중인 연결이 있어 %d/%d 페이지만 반영\n",
+				c.mark(markWarn), name, cp.Checkpointed, cp.Log)
+		case !c.quiet:
+			fmt.Fprintf(c.Output, "%s WAL 체크포인트 (%s): %s 바이트 반영 후 -wal 비움\n", c.mark(markOK), name, formatNumber(int(walSize)))
+		}
+	}
+
+	report(c.dbPath, c.db)
+	if c.shards == nil {
+		return
+	}
+	for _, year := range c.shards.years() {
+		if db, err := c.shards.get(year); err == nil {
+			report(c.shards.path(year), db)
+		}
+	}
+}
+
+// closeOnSignal - 수집 중 SIGINT/SIGTERM 을 받으면 collectors 를 닫고(WAL 체크포인트 포함) 종료
+//
+// 반환한 stop 을 호출하면 신호 처리를 해제한다 (정상 종료 시 defer 로 호출).
+func closeOnSignal(collectors ...*Collector) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			if len(collectors) > 0 {
+				c := collectors[0]
+				fmt.Fprintf(c.Output, "\n%s 종료 신호(%v) - 데이터베이스를 정리하고 종료합니다\n", c.mark(markWarn), sig)
+			}
+			for _, c := range collectors {
+				c.Close()
+			}
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
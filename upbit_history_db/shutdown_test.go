@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloseTruncatesWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candles.db")
+	c, err := NewCollector(path, "KRW-BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	c.Output = &out
+	if _, err := c.db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		t.Fatal(err)
+	}
+	// 다른 연결이 열려 있으면 마지막 연결 종료 시 SQLite 가 -wal 을 지우지 않으므로 체크포인트만으로 비워야 함
+	other, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := other.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	seed(t, c, mustTimeframe(t, "minute1"), kstMinutes(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), 200)...)
+	if info, err := os.Stat(path + "-wal"); err != nil || info.Size() == 0 {
+		t.Fatalf("저장 후 -wal 파일이 비어 있음 (WAL 을 흉내내지 못함): %v", err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path + "-wal"); err == nil && info.Size() != 0 {
+		t.Errorf("Close 후 -wal %d 바이트, want 0", info.Size())
+	}
+	if !strings.Contains(out.String(), "WAL 체크포인트 (candles.db)") {
+		t.Errorf("체크포인트 결과 출력 없음:\n%s", out.String())
+	}
+
+	// 체크포인트 후에도 다른 연결에서 모든 캔들이 보임
+	var n int
+	if err := other.QueryRow("SELECT COUNT(*) FROM bitcoin_minute1").Scan(&n); err != nil || n != 200 {
+		t.Errorf("다른 연결의 캔들 %d개 (err %v), want 200", n, err)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// OpenAPISpec - serve 가 제공하는 /candles, /stats 의 OpenAPI 3 문서
+//
+// 응답 스키마는 CandlesResponse, TimeframeStats 구조체의 json 태그에서 만들므로 구조체를 바꾸면
+// 스펙도 함께 바뀐다. omitempty 가 없는 필드만 required 로 표시한다.
+func OpenAPISpec() map[string]any {
+	names := make([]any, len(timeframes))
+	for i, tf := range timeframes {
+		names[i] = tf.Name
+	}
+	timeParam := func(name, description string) map[string]any {
+		return map[string]any{
+			"name": name, "in": "query", "description": description,
+			"schema": map[string]any{"type": "string", "example": "2024-01-01T09:00:00"},
+		}
+	}
+	errorResponse := func(description string) map[string]any {
+		return jsonResponse(description, reflect.TypeOf(apiErrorResponse{}))
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "upbit-collector read API",
+			"version": "1.0.0",
+			"description": "수집한 업비트 캔들을 조회하는 읽기 전용 API (serve 서브커맨드). " +
+				"시각은 모두 KST 문자열(YYYY-MM-DDTHH:MM:SS)이다.",
+		},
+		"paths": map[string]any{
+			"/candles": map[string]any{
+				"get": map[string]any{
+					"summary": "시간단위 캔들 조회 (시간 오름차순, cursor 로 페이지 이동)",
+					"parameters": []any{
+						map[string]any{
+							"name": "timeframe", "in": "query", "required": true,
+							"schema": map[string]any{"type": "string", "enum": names},
+						},
+						map[string]any{
+							"name": "market", "in": "query", "description": "서버가 제공하는 마켓만 허용 (생략 가능)",
+							"schema": map[string]any{"type": "string", "example": defaultMarket},
+						},
+						timeParam("from", "시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS, 포함)"),
+						timeParam("to", "종료 시각 (KST, 포함)"),
+						map[string]any{
+							"name": "limit", "in": "query",
+							"schema": map[string]any{"type": "integer", "minimum": 1, "maximum": maxCandleLimit, "default": defaultCandleLimit},
+						},
+						timeParam("cursor", "이전 응답의 next_cursor (그 다음 캔들부터 조회)"),
+					},
+					"responses": map[string]any{
+						"200": jsonResponse("캔들 목록", reflect.TypeOf(CandlesResponse{})),
+						"400": errorResponse("잘못된 파라미터"),
+						"404": errorResponse("없는 시간단위 또는 마켓"),
+						"500": errorResponse("DB 조회 실패"),
+					},
+				},
+			},
+			"/stats": map[string]any{
+				"get": map[string]any{
+					"summary": "시간단위별 저장 현황 (stats --json 과 같음)",
+					"responses": map[string]any{
+						"200": jsonResponse("시간단위별 저장 현황", reflect.TypeOf([]TimeframeStats{})),
+						"500": errorResponse("DB 조회 실패"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// jsonResponse - application/json 응답 객체
+func jsonResponse(description string, t reflect.Type) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaOf(t)},
+		},
+	}
+}
+
+// schemaOf - Go 타입의 JSON Schema (encoding/json 규칙: json 태그 이름, "-" 제외, omitempty 는 선택 필드)
+func schemaOf(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		var required []any
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = schemaOf(field.Type)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]any{}
+	}
+}
+
+// writeOpenAPISpec - OpenAPISpec 을 들여쓴 JSON 으로 출력
+func writeOpenAPISpec(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(OpenAPISpec())
+}
+
+func runSpec(args []string) error {
+	fs := flag.NewFlagSet("spec", flag.ContinueOnError)
+	out := fs.String("out", "", "저장할 파일 경로 (비우면 표준 출력)")
+	plain := fs.Bool("plain", false, "이모지 없이 ASCII 접두어([INFO]/[WARN]/[OK])로 출력")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return writeOpenAPISpec(os.Stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeOpenAPISpec(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("%s OpenAPI 스펙 저장: %s\n", markOK.text(*plain), *out)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeOpenAPISpec(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("유효한 JSON 이 아님: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+	var paths []string
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "/candles,/stats" {
+		t.Errorf("paths = %v, want /candles, /stats", paths)
+	}
+
+	// 캔들 스키마는 Candle 구조체의 json 태그와 같아야 함 (omitempty 필드는 required 제외)
+	item := schemaOf(reflect.TypeOf(CandlesResponse{}))["properties"].(map[string]any)["candles"].(map[string]any)["items"].(map[string]any)
+	props := item["properties"].(map[string]any)
+	encoded, err := json.Marshal(Candle{IsInterpolated: true, IsProvisional: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if len(props) != len(fields) {
+		t.Errorf("스키마 필드 %d개, Candle JSON 필드 %d개", len(props), len(fields))
+	}
+	for name := range fields {
+		if _, ok := props[name]; !ok {
+			t.Errorf("스키마에 %s 없음", name)
+		}
+	}
+	for _, name := range item["required"].([]any) {
+		if name == "is_interpolated" || name == "is_provisional" {
+			t.Errorf("omitempty 필드 %s 가 required", name)
+		}
+	}
+}
+
+func TestRunSpecPlainOutput(t *testing.T) {
+	for _, plain := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "openapi.json")
+		args := []string{"--out", path}
+		want := markOK.emoji + " OpenAPI 스펙 저장: " + path
+		if plain {
+			args = append(args, "--plain")
+			want = markOK.plain + " OpenAPI 스펙 저장: " + path
+		}
+
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout := os.Stdout
+		os.Stdout = w
+		printed := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			printed <- string(data)
+		}()
+		err = runSpec(args)
+		os.Stdout = stdout
+		w.Close()
+		got := strings.TrimSpace(<-printed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("plain=%v: 출력 %q, want %q", plain, got, want)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("plain=%v: 스펙 파일 없음: %v", plain, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ensureStatsSummary - 캔들 테이블 통계(개수, 보간 수, 기간)를 timeframe_summary 에 유지하는 트리거 생성
+//
+// 트리거는 같은 DB 파일의 테이블만 고칠 수 있으므로 요약 테이블은 캔들 DB 파일마다 있다. 트리거가
+// 없으면(새 테이블, 예전 버전 DB, ResetTimeframe 후) 만들면서 요약 행을 전체 집계로 다시 채운다.
+// INSERT OR REPLACE 로 지워지는 행도 세려면 연결에 recursive_triggers 가 켜져 있어야 한다 (sqliteDSN).
+func (c *Collector) ensureStatsSummary(db *sql.DB, tf Timeframe) error {
+	table := c.table(tf)
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS timeframe_summary (
+			tbl TEXT PRIMARY KEY,
+			total INTEGER NOT NULL,
+			interpolated INTEGER NOT NULL,
+			oldest TEXT,
+			newest TEXT
+		)
+	`); err != nil {
+		return err
+	}
+
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?",
+		table+"_summary_insert").Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	triggers := []string{
+		`CREATE TRIGGER IF NOT EXISTS %[1]s_summary_insert AFTER INSERT ON %[1]s BEGIN
+			UPDATE timeframe_summary SET
+				total = total + 1,
+				interpolated = interpolated + (COALESCE(NEW.is_interpolated, 0) = 1),
+				oldest = CASE WHEN oldest IS NULL OR NEW.timestamp < oldest THEN NEW.timestamp ELSE oldest END,
+				newest = CASE WHEN newest IS NULL OR NEW.timestamp > newest THEN NEW.timestamp ELSE newest END
+			WHERE tbl = '%[1]s';
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS %[1]s_summary_delete AFTER DELETE ON %[1]s BEGIN
+			UPDATE timeframe_summary SET
+				total = total - 1,
+				interpolated = interpolated - (COALESCE(OLD.is_interpolated, 0) = 1),
+				oldest = CASE WHEN OLD.timestamp = oldest THEN (SELECT MIN(timestamp) FROM %[1]s) ELSE oldest END,
+				newest = CASE WHEN OLD.timestamp = newest THEN (SELECT MAX(timestamp) FROM %[1]s) ELSE newest END
+			WHERE tbl = '%[1]s';
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS %[1]s_summary_update AFTER UPDATE OF timestamp, is_interpolated ON %[1]s BEGIN
+			UPDATE timeframe_summary SET
+				interpolated = interpolated + (COALESCE(NEW.is_interpolated, 0) = 1) - (COALESCE(OLD.is_interpolated, 0) = 1),
+				oldest = (SELECT MIN(timestamp) FROM %[1]s),
+				newest = (SELECT MAX(timestamp) FROM %[1]s)
+			WHERE tbl = '%[1]s';
+		END`,
+	}
+	for _, trigger := range triggers {
+		if _, err := tx.Exec(fmt.Sprintf(trigger, table)); err != nil {
+			return fmt.Errorf("통계 트리거 생성 실패: %w", err)
+		}
+	}
+	if err := refreshStatsSummary(tx, table); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// refreshStatsSummary - table 전체를 집계해 요약 행을 다시 씀
+func refreshStatsSummary(tx *sql.Tx, table string) error {
+	_, err := tx.Exec(fmt.Sprintf(`
+		INSERT OR REPLACE INTO timeframe_summary (tbl, total, interpolated, oldest, newest)
+		SELECT ?, COUNT(*), COALESCE(SUM(CASE WHEN is_interpolated = 1 THEN 1 ELSE 0 END), 0), MIN(timestamp), MAX(timestamp)
+		FROM %s
+	`, table), table)
+	return err
+}
+
+// RecomputeStatistics - 모든 캔들 DB 의 timeframe_summary 를 전체 집계로 다시 계산 (트리거 밖에서 고친 경우 등)
+func (c *Collector) RecomputeStatistics() error {
+	for _, db := range c.candleDBs() {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		for _, tf := range timeframes {
+			if err := refreshStatsSummary(tx, c.table(tf)); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("%s 통계 재계산 실패: %w", c.table(tf), err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summaryStats - 한 DB 파일의 tf 요약 행 (없으면 ok=false)
+func (c *Collector) summaryStats(db *sql.DB, tf Timeframe) (stats TimeframeStats, ok bool, err error) {
+	var oldest, newest sql.NullString
+	err = db.QueryRow("SELECT total, interpolated, oldest, newest FROM timeframe_summary WHERE tbl = ?", c.table(tf)).
+		Scan(&stats.Total, &stats.Interpolated, &oldest, &newest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TimeframeStats{}, false, nil
+	}
+	if err != nil {
+		return TimeframeStats{}, false, err
+	}
+	stats.Original = stats.Total - stats.Interpolated
+	stats.Oldest, stats.Newest = oldest.String, newest.String
+	return stats, true, nil
+}
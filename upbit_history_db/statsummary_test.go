@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsSummaryMatchesRecompute(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	tf := mustTimeframe(t, "minute1")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	minute := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Minute) }
+
+	// check - 트리거로 유지한 요약이 전체 테이블 집계와 같은지
+	check := func(step string, wantTotal int) {
+		t.Helper()
+		got, err := c.timeframeStats(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := c.scanTimeframeStats(c.db, tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want.Timeframe = tf.Name
+		if got != want || got.Total != wantTotal {
+			t.Errorf("%s: 요약 %+v, 전체 집계 %+v (want 총 %d)", step, got, want, wantTotal)
+		}
+	}
+
+	check("빈 테이블", 0)
+	seed(t, c, tf, minute(5), minute(6), minute(10))
+	check("저장", 3)
+	seed(t, c, tf, minute(0), minute(6))
+	check("앞쪽 캔들 + 중복 무시", 4)
+	if _, err := c.interpolateMissingData(tf); err != nil {
+		t.Fatal(err)
+	}
+	check("보간", 11)
+	// 보간 캔들을 실제 캔들로 덮어쓰기 (INSERT OR REPLACE 가 지운 행도 셈)
+	if _, _, err := c.saveCandlesAs(tf, []Candle{testCandle(minute(2), 1), testCandle(minute(8), 1)}, ConflictReplace); err != nil {
+		t.Fatal(err)
+	}
+	check("덮어쓰기", 11)
+	if _, err := c.db.Exec("DELETE FROM bitcoin_minute1 WHERE timestamp IN (?, ?)",
+		minute(0).Format(timestampLayout), minute(10).Format(timestampLayout)); err != nil {
+		t.Fatal(err)
+	}
+	check("양 끝 삭제", 9)
+
+	// 트리거 밖에서 고친 요약도 RecomputeStatistics 로 복구
+	if _, err := c.db.Exec("UPDATE timeframe_summary SET total = 0, oldest = NULL WHERE tbl = 'bitcoin_minute1'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RecomputeStatistics(); err != nil {
+		t.Fatal(err)
+	}
+	check("재계산", 9)
+}
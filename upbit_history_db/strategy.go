@@ -0,0 +1,67 @@
+package main
+
+import "fmt"
+
+// Signal - 전략 판단 결과
+type Signal int
+
+const (
+	Hold Signal = iota
+	Buy
+	Sell
+)
+
+func (s Signal) String() string {
+	switch s {
+	case Hold:
+		return "hold"
+	case Buy:
+		return "buy"
+	case Sell:
+		return "sell"
+	default:
+		return fmt.Sprintf("Signal(%d)", int(s))
+	}
+}
+
+// Strategy - 캔들이 마감될 때마다 호출되는 매매 전략
+//
+// history 는 시간 오름차순이며 마지막 원소가 방금 마감된 캔들이다.
+type Strategy interface {
+	Evaluate(history []Candle) Signal
+}
+
+// SMACrossover - 단기 SMA 가 장기 SMA 를 상향 돌파하면 Buy, 하향 돌파하면 Sell
+type SMACrossover struct {
+	Short int
+	Long  int
+	Field PriceField // 평균낼 가격 (기본: 종가)
+}
+
+func (s SMACrossover) Evaluate(history []Candle) Signal {
+	if s.Short < 1 || s.Long <= s.Short || len(history) < s.Long+1 {
+		return Hold
+	}
+
+	last := len(history) - 1
+	prevShort, prevLong := priceSMA(history, last-1, s.Short, s.Field), priceSMA(history, last-1, s.Long, s.Field)
+	short, long := priceSMA(history, last, s.Short, s.Field), priceSMA(history, last, s.Long, s.Field)
+
+	switch {
+	case prevShort <= prevLong && short > long:
+		return Buy
+	case prevShort >= prevLong && short < long:
+		return Sell
+	default:
+		return Hold
+	}
+}
+
+// priceSMA - candles[end] 까지 period 개 field 가격 평균
+func priceSMA(candles []Candle, end, period int, field PriceField) float64 {
+	sum := 0.0
+	for _, candle := range candles[end-period+1 : end+1] {
+		sum += field.Of(candle)
+	}
+	return sum / float64(period)
+}
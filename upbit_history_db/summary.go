@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunSummary - CollectAll 1회 실행 요약 (SummaryPath 에 JSON 으로 기록)
+type RunSummary struct {
+	Market       string             `json:"market"`
+	StartedAt    time.Time          `json:"started_at"`
+	FinishedAt   time.Time          `json:"finished_at"`
+	Failed       bool               `json:"failed"`                  // 한 시간단위라도 오류가 있으면 true
+	BreakerState string             `json:"breaker_state,omitempty"` // 종료 시 회로 차단기 상태 (closed, open, half-open)
+	BreakerTrips int                `json:"breaker_trips,omitempty"` // 회로 차단기가 열린 횟수
+	Timeframes   []TimeframeSummary `json:"timeframes"`
+}
+
+// TimeframeSummary - CollectResult 의 JSON 표현 (오류는 메시지 문자열)
+type TimeframeSummary struct {
+	Timeframe    string `json:"timeframe"`
+	Pages        int    `json:"pages"`
+	Fetched      int    `json:"fetched"`
+	Saved        int    `json:"saved"`
+	Rejected     int    `json:"rejected"`
+	Interpolated int    `json:"interpolated"`
+	Requests     int    `json:"requests,omitempty"`  // MaxRequests 설정 시 사용한 API 요청 수
+	TimedOut     bool   `json:"timed_out,omitempty"` // PerTimeframeTimeout 으로 중단됨
+	Retries      int    `json:"retries,omitempty"`   // 실패 후 다시 수집한 횟수
+	Error        string `json:"error,omitempty"`
+}
+
+// newRunSummary - 수집 결과로 요약 생성
+func (c *Collector) newRunSummary(started, finished time.Time, results []CollectResult) RunSummary {
+	summary := RunSummary{
+		Market:     c.market,
+		StartedAt:  started,
+		FinishedAt: finished,
+		Timeframes: make([]TimeframeSummary, len(results)),
+	}
+	if b := c.breaker; b != nil {
+		state, trips := b.stats()
+		summary.BreakerState, summary.BreakerTrips = state.String(), trips
+	}
+	for i, r := range results {
+		summary.Timeframes[i] = TimeframeSummary{
+			Timeframe:    r.Timeframe,
+			Pages:        r.Pages,
+			Fetched:      r.Fetched,
+			Saved:        r.Saved,
+			Rejected:     r.Rejected,
+			Interpolated: r.Interpolated,
+			Requests:     r.Requests,
+			TimedOut:     r.TimedOut,
+			Retries:      r.Retries,
+		}
+		if r.Err != nil {
+			summary.Timeframes[i].Error = r.Err.Error()
+			summary.Failed = true
+		}
+	}
+	return summary
+}
+
+// completionWebhookTimeout - CompletionWebhook 요청 제한 시간 (알림 때문에 수집 종료가 늦어지지 않도록 짧게)
+const completionWebhookTimeout = 5 * time.Second
+
+// completionPayload - CompletionWebhook 에 보내는 본문 (RunSummary 에 합계와 오류 목록을 더함)
+type completionPayload struct {
+	RunSummary
+	TotalSaved        int      `json:"total_saved"`
+	TotalInterpolated int      `json:"total_interpolated"`
+	DurationSeconds   float64  `json:"duration_seconds"`
+	Errors            []string `json:"errors,omitempty"` // "시간단위: 오류" 형식
+}
+
+// newCompletionPayload - 실행 요약으로 완료 알림 본문 생성
+func newCompletionPayload(summary RunSummary) completionPayload {
+	payload := completionPayload{
+		RunSummary:      summary,
+		DurationSeconds: summary.FinishedAt.Sub(summary.StartedAt).Seconds(),
+	}
+	for _, tf := range summary.Timeframes {
+		payload.TotalSaved += tf.Saved
+		payload.TotalInterpolated += tf.Interpolated
+		if tf.Error != "" {
+			payload.Errors = append(payload.Errors, tf.Timeframe+": "+tf.Error)
+		}
+	}
+	return payload
+}
+
+// notifyCompletion - CompletionWebhook 으로 완료 알림 전송 (실패는 경고 로그만 남김)
+func (c *Collector) notifyCompletion(summary RunSummary) {
+	client := &http.Client{Timeout: completionWebhookTimeout}
+	if err := postJSON(client, c.CompletionWebhook, newCompletionPayload(summary)); err != nil {
+		fmt.Fprintf(c.Output, "%s 완료 알림 전송 실패: %v\n", c.mark(markWarn), err)
+		return
+	}
+	if !c.quiet {
+		fmt.Fprintf(c.Output, "%s 완료 알림 전송\n", c.mark(markOK))
+	}
+}
+
+// writeJSONAtomic - 같은 디렉터리의 임시 파일에 쓴 뒤 rename (읽는 쪽이 반쯤 쓰인 파일을 보지 않도록)
+func writeJSONAtomic(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // rename 성공 후에는 이미 없는 파일
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
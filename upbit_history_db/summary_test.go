@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollectAllWritesSummary(t *testing.T) {
+	c, f := newTestCollector(t)
+	fake := f.handler(t)
+	// 일봉만 재시도하지 않는 오류로 실패
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/days") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"name":"invalid_query_payload","message":"bad"}}`))
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c.apiURL = srv.URL
+	c.MaxPages = 1
+	dir := t.TempDir()
+	c.SummaryPath = filepath.Join(dir, "last_run.json")
+
+	results := c.CollectAll()
+	data, err := os.ReadFile(c.SummaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary RunSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Market != "KRW-BTC" || !summary.Failed || summary.FinishedAt.Before(summary.StartedAt) {
+		t.Errorf("요약 = market %s, failed %v, %v ~ %v", summary.Market, summary.Failed, summary.StartedAt, summary.FinishedAt)
+	}
+	if len(summary.Timeframes) != len(results) {
+		t.Fatalf("시간단위 %d개, want %d", len(summary.Timeframes), len(results))
+	}
+	for i, r := range results {
+		got := summary.Timeframes[i]
+		want := TimeframeSummary{
+			Timeframe: r.Timeframe, Pages: r.Pages, Fetched: r.Fetched, Saved: r.Saved, Rejected: r.Rejected,
+			Interpolated: r.Interpolated, Requests: r.Requests, TimedOut: r.TimedOut, Retries: r.Retries,
+		}
+		if r.Err != nil {
+			want.Error = r.Err.Error()
+		}
+		if got != want {
+			t.Errorf("[%s] 파일 = %+v\nwant %+v", r.Timeframe, got, want)
+		}
+		if (got.Error != "") != (r.Timeframe == "day") {
+			t.Errorf("[%s] error = %q", r.Timeframe, got.Error)
+		}
+	}
+
+	// 임시 파일은 rename 으로 사라짐
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("디렉터리 파일 %d개, want last_run.json 하나", len(entries))
+	}
+}
+
+func TestCollectAllCompletionWebhook(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		c, f := newTestCollector(t)
+		var out bytes.Buffer
+		c.Output = &out
+		fake := f.handler(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/days") {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"name":"invalid_query_payload","message":"bad"}}`))
+				return
+			}
+			fake.ServeHTTP(w, r)
+		}))
+		c.apiURL = srv.URL
+		c.MaxPages = 1
+
+		bodies := make(chan []byte, 1)
+		hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies <- body
+			w.WriteHeader(status)
+		}))
+		c.CompletionWebhook = hook.URL
+
+		results := c.CollectAll()
+		srv.Close()
+		hook.Close()
+
+		var payload completionPayload
+		if err := json.Unmarshal(<-bodies, &payload); err != nil {
+			t.Fatal(err)
+		}
+		saved := 0
+		for _, r := range results {
+			saved += r.Saved
+		}
+		if payload.TotalSaved != saved || saved == 0 || !payload.Failed || len(payload.Timeframes) != len(results) {
+			t.Errorf("본문 = total_saved %d (want %d), failed %v, 시간단위 %d개", payload.TotalSaved, saved, payload.Failed, len(payload.Timeframes))
+		}
+		if len(payload.Errors) != 1 || !strings.HasPrefix(payload.Errors[0], "day: ") {
+			t.Errorf("errors = %q, want day 오류 하나", payload.Errors)
+		}
+		if payload.DurationSeconds < 0 {
+			t.Errorf("duration_seconds = %v", payload.DurationSeconds)
+		}
+
+		// 알림이 실패해도 수집 결과는 그대로이고 경고만 남김
+		warned := strings.Contains(out.String(), "완료 알림 전송 실패")
+		if warned != (status != http.StatusOK) {
+			t.Errorf("status %d: 전송 실패 로그 = %v", status, warned)
+		}
+		if len(results) != len(timeframes) || results[0].Err != nil {
+			t.Errorf("status %d: 결과가 바뀜: %+v", status, results[0])
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Tick - 업비트 체결 1건 (GET /v1/trades/ticks)
+type Tick struct {
+	SequentialID int64   `json:"sequential_id"`
+	Timestamp    int64   `json:"timestamp"` // 체결 시각 (Unix ms)
+	TradePrice   float64 `json:"trade_price"`
+	TradeVolume  float64 `json:"trade_volume"`
+	AskBid       string  `json:"ask_bid"` // ASK(매도) / BID(매수)
+}
+
+// TickCollector - 체결 내역 수집기
+//
+// 캔들 Collector 와 DB 연결, HTTP 클라이언트, rate limiter, 재시도 로직을 공유하고
+// 체결은 기본 DB 파일의 ticks_<market> 테이블에 저장한다 (연도 분할 대상 아님).
+type TickCollector struct {
+	c      *Collector
+	apiURL string
+
+	// MaxPages - 최대 요청 페이지 수 (0 = 중복 또는 빈 페이지가 나올 때까지)
+	MaxPages int
+}
+
+// NewTickCollector - c 의 연결과 rate limiter 를 공유하는 체결 수집기
+func NewTickCollector(c *Collector) (*TickCollector, error) {
+	t := &TickCollector{c: c, apiURL: "https://api.upbit.com/v1/trades/ticks"}
+	if err := t.createTable(); err != nil {
+		return nil, fmt.Errorf("%s 테이블 생성 실패: %w", t.table(), err)
+	}
+	return t, nil
+}
+
+// table - 마켓별 체결 테이블 이름 (예: ticks_krw_btc)
+func (t *TickCollector) table() string {
+	return "ticks_" + strings.ToLower(strings.ReplaceAll(t.c.market, "-", "_"))
+}
+
+func (t *TickCollector) createTable() error {
+	if _, err := t.c.db.Exec(tickSchema.createSQL(t.table())); err != nil {
+		return err
+	}
+	_, err := tickSchema.addMissingColumns(t.c.db, t.table())
+	return err
+}
+
+// fetchTicks - cursor(sequential_id) 이전 체결 최대 200건 요청 (cursor 0 이면 최신부터)
+func (t *TickCollector) fetchTicks(cursor int64) ([]Tick, error) {
+	var ticks []Tick
+	err := t.c.withRetry(context.Background(), "ticks", func() error {
+		var err error
+		ticks, err = t.requestTicks(cursor)
+		return err
+	})
+	return ticks, err
+}
+
+// requestTicks - rate limit 대기 없이 API 1회 요청
+func (t *TickCollector) requestTicks(cursor int64) ([]Tick, error) {
+	query := url.Values{}
+	query.Set("market", t.c.market)
+	query.Set("count", "200")
+	if cursor > 0 {
+		query.Set("cursor", strconv.FormatInt(cursor, 10))
+	}
+
+	resp, err := t.c.httpClient.Get(t.apiURL + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var ticks []Tick
+	if err := decodeResponse(resp.StatusCode, body, &ticks); err != nil {
+		return nil, err
+	}
+	return ticks, nil
+}
+
+// saveTicks - 배치 저장 (이미 있는 sequential_id 는 건너뜀), 새로 저장한 건수 반환
+func (t *TickCollector) saveTicks(ticks []Tick) (int, error) {
+	tx, err := t.c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(tickSchema.insertSQL("INSERT OR IGNORE", t.table()))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	saved := 0
+	for _, tick := range ticks {
+		result, err := stmt.Exec(tickFields(&tick)...)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			saved++
+		}
+	}
+	return saved, tx.Commit()
+}
+
+// Collect - 최신 체결부터 cursor 로 과거 방향 페이지 수집
+//
+// 빈 페이지, 이미 저장된 체결만 있는 페이지, 또는 MaxPages 에 도달하면 멈춘다.
+func (t *TickCollector) Collect() (int, error) {
+	var cursor int64
+	total := 0
+	for page := 0; t.MaxPages == 0 || page < t.MaxPages; page++ {
+		ticks, err := t.fetchTicks(cursor)
+		if err != nil {
+			return total, fmt.Errorf("체결 요청 실패: %w", err)
+		}
+		if len(ticks) == 0 {
+			break
+		}
+
+		saved, err := t.saveTicks(ticks)
+		if err != nil {
+			return total, fmt.Errorf("체결 저장 실패: %w", err)
+		}
+		total += saved
+		fmt.Fprintf(t.c.Output, "[ticks] %s %d/%d건 저장 (누적 %s)\n", t.c.mark(markOK), saved, len(ticks), formatNumber(total))
+		if saved == 0 {
+			break
+		}
+
+		cursor = ticks[0].SequentialID
+		for _, tick := range ticks[1:] {
+			cursor = min(cursor, tick.SequentialID)
+		}
+	}
+	return total, nil
+}
+
+func runTicks(args []string) error {
+	fs := flag.NewFlagSet("ticks", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	pages := fs.Int("pages", 10, "최대 요청 페이지 수 (0 = 중복이 나올 때까지)")
+	rate := fs.Int("rate", 9, "초당 최대 API 요청 수 (업비트 제한: 10)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *rate <= 0 {
+		return fmt.Errorf("--rate 는 1 이상이어야 합니다")
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.rateLimiter = NewRateLimiter(*rate)
+
+	ticks, err := NewTickCollector(collector)
+	if err != nil {
+		return err
+	}
+	ticks.MaxPages = *pages
+
+	total, err := ticks.Collect()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s 체결 %s건 저장\n", collector.mark(markOK), ticks.table(), formatNumber(total))
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeTicks - sequential_id newest ~ oldest 체결을 200건씩 최신순으로 주는 서버
+//
+// cursor 가 있으면 cursor 체결부터 다시 포함해, 페이지가 한 건씩 겹치는 응답을 흉내낸다.
+type fakeTicks struct {
+	newest, oldest int64
+	mu             sync.Mutex
+	cursors        []string
+}
+
+func (f *fakeTicks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cursor := r.URL.Query().Get("cursor")
+	f.mu.Lock()
+	f.cursors = append(f.cursors, cursor)
+	f.mu.Unlock()
+
+	top := f.newest
+	if cursor != "" {
+		top, _ = strconv.ParseInt(cursor, 10, 64)
+	}
+	ticks := []Tick{}
+	for id := top; id >= f.oldest && len(ticks) < 200; id-- {
+		ticks = append(ticks, Tick{SequentialID: id, Timestamp: 1704067200000 + id, TradePrice: 100, TradeVolume: 0.1, AskBid: "BID"})
+	}
+	json.NewEncoder(w).Encode(ticks)
+}
+
+func TestTickCollectorPaginatesAndDedups(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	f := &fakeTicks{newest: 1000, oldest: 501}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	ticks, err := NewTickCollector(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks.apiURL = srv.URL
+
+	total, err := ticks.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 500 {
+		t.Errorf("저장 %d건, want 500 (겹친 체결은 한 번만)", total)
+	}
+	// 1000~801, 801~602, 602~501, 501(중복만) 순서로 요청
+	want := []string{"", "801", "602", "501"}
+	if len(f.cursors) != len(want) {
+		t.Fatalf("cursor = %q, want %q", f.cursors, want)
+	}
+	for i := range want {
+		if f.cursors[i] != want[i] {
+			t.Errorf("[%d] cursor = %q, want %q", i, f.cursors[i], want[i])
+		}
+	}
+
+	var rows, distinct int
+	if err := c.db.QueryRow("SELECT COUNT(*), COUNT(DISTINCT sequential_id) FROM "+ticks.table()).Scan(&rows, &distinct); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 500 || distinct != 500 {
+		t.Errorf("행 %d개 (고유 %d개), want 500", rows, distinct)
+	}
+
+	// 다시 수집하면 첫 페이지가 모두 중복이라 바로 멈춤
+	if total, err = ticks.Collect(); err != nil || total != 0 {
+		t.Errorf("재수집 = %d건, %v, want 0", total, err)
+	}
+}
+
+func TestTickCollectorAPIError(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"name":"invalid_query_payload","message":"bad cursor"}}`))
+	}))
+	defer srv.Close()
+
+	ticks, err := NewTickCollector(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks.apiURL = srv.URL
+	if _, err := ticks.Collect(); err == nil {
+		t.Error("API 오류 응답인데 성공함")
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TopUp - 마지막 실제 캔들 이후부터 마감된 최신 캔들까지만 tf 수집 (최신 페이지부터 과거 방향)
+//
+// 이미 채워진 DB 를 최신으로 유지하는 용도다. 마지막 실제 캔들에 닿은 페이지에서 멈추므로 빈 구간이 짧으면
+// 요청 1회로 끝난다. 진행 중 캔들은 값이 바뀌므로 저장하지 않는다. 저장된 실제 캔들이 없으면 아무것도 하지
+// 않는다 (처음 수집은 collect). 보간은 하지 않는다 (UpdateAll 이 이어서 실행).
+//
+// collect 가 진행 중에 저장한 캔들(is_provisional)은 가장 오래된 것까지 내려가 INSERT OR REPLACE 로 다시
+// 받은 값을 덮어쓰고, 나머지 과거 캔들은 Conflict 설정(기본 INSERT OR IGNORE)대로 저장한다.
+// KeepProvisional 이면 덮어쓰지 않는다.
+func (c *Collector) TopUp(tf Timeframe) CollectResult {
+	result := CollectResult{Timeframe: tf.Name}
+	if _, err := c.ClearFillToNow(tf); err != nil {
+		fmt.Fprintf(c.Output, "[%s] %s 채움 캔들 삭제 실패: %v\n", tf.Name, c.mark(markWarn), err)
+	}
+	last, ok, err := c.lastRealCandle(tf)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if !ok {
+		fmt.Fprintf(c.Output, "[%s] %s 저장된 캔들이 없어 건너뜀 (collect 로 먼저 수집)\n", tf.Name, c.mark(markWarn))
+		return result
+	}
+	head := last.CandleDateTimeKST
+	provisional := map[string]bool{}
+	if !c.KeepProvisional {
+		if provisional, err = c.provisionalTimestamps(tf); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	// 마지막 실제 캔들보다 오래된 진행 중 캔들이 있으면 그 이전까지 내려가야 멈춤
+	stop := head
+	for ts := range provisional {
+		if ts < stop {
+			stop = ts
+		}
+	}
+
+	ctx := context.Background()
+	to, prevOldest := "", ""
+	for {
+		result.Pages++
+		candles, err := c.fetchCandles(ctx, tf, to, nil)
+		if errors.Is(err, errRequestBudget) {
+			result.Pages--
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 예산(%d회) 소진 - 받은 페이지까지 저장 후 중단\n",
+				tf.Name, c.mark(markPause), c.MaxRequests)
+			break
+		}
+		if err != nil && c.MaintenanceBackoff > 0 && responseStatus(err) == http.StatusServiceUnavailable {
+			result.Pages--
+			fmt.Fprintf(c.Output, "[%s] %s 업비트 점검 중 (503) - %v 후 같은 위치부터 재개\n",
+				tf.Name, c.mark(markPause), c.MaintenanceBackoff)
+			sleepCtx(ctx, c.MaintenanceBackoff)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s API 요청 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Err = fmt.Errorf("API 요청 실패: %w", err)
+			break
+		}
+		if len(candles) == 0 {
+			break
+		}
+		result.Fetched += len(candles)
+
+		oldest := candles[len(candles)-1]
+		if prevOldest != "" && oldest.CandleDateTimeKST >= prevOldest {
+			fmt.Fprintf(c.Output, "[%s] %s 동일한 데이터 반복 감지. 수집 중단.\n", tf.Name, c.mark(markWarn))
+			break
+		}
+
+		reached := false
+		var fresh, final []Candle
+		for _, candle := range candles {
+			ts := candle.CandleDateTimeKST
+			if ts < stop || (ts <= head && len(provisional) == 0) {
+				reached = true
+				break
+			}
+			switch {
+			case provisional[ts]:
+				final = append(final, candle)
+			case !c.isProvisional(tf, ts):
+				fresh = append(fresh, candle)
+			}
+		}
+
+		saved, failed, err := c.saveCandles(tf, fresh)
+		result.Saved += saved
+		result.Rejected += len(failed)
+		if err == nil && len(final) > 0 {
+			var replaced int
+			replaced, failed, err = c.saveCandlesAs(tf, final, ConflictReplace)
+			result.Saved += replaced
+			result.Rejected += len(failed)
+			if replaced > 0 {
+				fmt.Fprintf(c.Output, "[%s] %s 진행 중에 저장된 캔들 %d개를 다시 받은 값으로 덮어씀\n", tf.Name, c.mark(markOK), replaced)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 저장 실패: %v\n", tf.Name, c.mark(markFail), err)
+			result.Err = err
+			break
+		}
+		if reached {
+			break
+		}
+		if c.MaxPages > 0 && result.Pages >= c.MaxPages {
+			fmt.Fprintf(c.Output, "[%s] %s 페이지 제한(%d) 도달. %s 이후 구간은 다음 실행에서 수집.\n",
+				tf.Name, c.mark(markWarn), c.MaxPages, head)
+			break
+		}
+		to, prevOldest = pageCursor(oldest.CandleDateTimeUTC), oldest.CandleDateTimeKST
+	}
+
+	fmt.Fprintf(c.Output, "[%s] %s %s 이후 %d개 캔들 저장 (요청 %d회)\n", tf.Name, c.mark(markOK), head, result.Saved, result.Pages)
+	return result
+}
+
+// provisionalTimestamps - 저장 당시 진행 중이던 실제 캔들의 timestamp
+func (c *Collector) provisionalTimestamps(tf Timeframe) (map[string]bool, error) {
+	timestamps := make(map[string]bool)
+	for _, db := range c.candleDBs() {
+		rows, err := db.Query(fmt.Sprintf("SELECT timestamp FROM %s WHERE is_provisional = 1 AND is_interpolated = 0", c.table(tf)))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var ts string
+			if err := rows.Scan(&ts); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			timestamps[ts] = true
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return timestamps, nil
+}
+
+// UpdateAll - 모든 시간단위를 TopUp 으로 최신 상태로 만든 뒤 새 구간만 보간, 시간단위별 결과 반환
+//
+// CollectAll 과 같은 rate limiter, MaxConcurrency, MaxRequests 예산을 쓴다. 보간은 TopUp 이전의 마지막
+// 실제 캔들부터만 하므로 전체 테이블을 다시 훑지 않는다.
+func (c *Collector) UpdateAll() []CollectResult {
+	fmt.Fprintln(c.Output, "\n"+"============================================================")
+	fmt.Fprintln(c.Output, c.mark(markLaunch)+" 모든 시간단위 최신 캔들까지 갱신 시작")
+	fmt.Fprintln(c.Output, "============================================================")
+
+	if c.MaxRequests > 0 {
+		c.budget = newRequestBudget(c.MaxRequests)
+		defer func() { c.budget = nil }()
+	}
+
+	results := make([]CollectResult, len(timeframes))
+	c.forEachTimeframe(func(i int, tf Timeframe) {
+		last, hadData, err := c.lastRealCandle(tf)
+		if err != nil {
+			results[i] = CollectResult{Timeframe: tf.Name, Err: err}
+			return
+		}
+		results[i] = c.TopUp(tf)
+		if c.budget != nil {
+			results[i].Requests = c.budget.spentBy(tf.Name)
+		}
+		if !hadData || results[i].Saved == 0 {
+			return
+		}
+		interpolated, err := c.interpolateFrom(tf, last.CandleDateTimeKST)
+		results[i].Interpolated = interpolated
+		if err != nil && results[i].Err == nil {
+			results[i].Err = fmt.Errorf("보간 실패: %w", err)
+		}
+		if c.StoreReturns {
+			if _, err := c.ComputeReturns(tf); err != nil && results[i].Err == nil {
+				results[i].Err = fmt.Errorf("수익률 계산 실패: %w", err)
+			}
+		}
+	})
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(c.Output, "[%s] %s 갱신 중 오류: %v\n", r.Timeframe, c.mark(markFail), r.Err)
+		}
+	}
+	return results
+}
+
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	pages := fs.Int("pages", 0, "시간단위별 최대 요청 페이지 수 (0 = 제한 없음)")
+	concurrency := fs.Int("concurrency", 0, "동시에 갱신할 시간단위 수 (0 = 전체 동시)")
+	rate := fs.Int("rate", 9, "초당 최대 API 요청 수 (업비트 제한: 10)")
+	strategy := fs.String("interpolation", InterpolateLinear.String(), "보간 방식 (linear, zero-volume)")
+	maintenance := fs.Duration("maintenance-backoff", 5*time.Minute, "업비트 점검(503 지속) 시 대기 시간 (0 = 대기 없이 실패)")
+	maxRequests := fs.Int("max-requests", 0, "이번 실행의 전체 API 요청 수 상한 (0 = 제한 없음)")
+	storeReturns := fs.Bool("store-returns", false, "갱신/보간 후 candle_returns 테이블의 로그 수익률 갱신")
+	keepProvisional := fs.Bool("keep-provisional", false, "진행 중에 저장된 캔들을 마감 값으로 덮어쓰지 않음")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	interpolation, err := parseInterpolationStrategy(*strategy)
+	if err != nil {
+		return err
+	}
+	if *rate <= 0 {
+		return fmt.Errorf("--rate 는 1 이상이어야 합니다")
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+	collector.MaxPages = *pages
+	collector.MaxConcurrency = *concurrency
+	collector.rateLimiter = NewRateLimiter(*rate)
+	collector.Interpolation = interpolation
+	collector.MaintenanceBackoff = *maintenance
+	collector.MaxRequests = *maxRequests
+	collector.StoreReturns = *storeReturns
+	collector.KeepProvisional = *keepProvisional
+	defer closeOnSignal(collector)()
+
+	return failedResults(collector.UpdateAll())
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateAllFetchesOnlyNewestCandles(t *testing.T) {
+	f := &fakeUpbit{head: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	fake := f.handler(t)
+	var mu sync.Mutex
+	paths := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	// 12:00 1분봉은 마감, 12:00 60분봉과 01-01 일봉은 진행 중
+	c.now = func() time.Time { return f.head.Add(90 * time.Second) }
+
+	headKST := f.head.Add(9 * time.Hour)
+	tests := []struct {
+		tf       string
+		last     time.Time // 미리 저장한 마지막 실제 캔들 (KST)
+		latest   time.Time // 마감된 최신 캔들 (KST)
+		saved    int
+		requests int
+	}{
+		{"minute1", headKST.Add(-250 * time.Minute), headKST, 250, 2},
+		{"minute60", headKST.Add(-6 * time.Hour), headKST.Add(-time.Hour), 5, 1},
+		{"day", time.Date(2023, 12, 1, 9, 0, 0, 0, time.UTC), time.Date(2023, 12, 31, 9, 0, 0, 0, time.UTC), 30, 1},
+	}
+	for _, tt := range tests {
+		seed(t, c, mustTimeframe(t, tt.tf), tt.last)
+	}
+
+	results := c.UpdateAll()
+	if len(results) != len(timeframes) {
+		t.Fatalf("결과 %d개, want %d", len(results), len(timeframes))
+	}
+	want := map[string]int{}
+	for _, tt := range tests {
+		want[tt.tf] = tt.saved
+		tf := mustTimeframe(t, tt.tf)
+		got := 0
+		for path, n := range paths {
+			if strings.HasSuffix(path, "/"+tf.APIPath) {
+				got += n
+			}
+		}
+		if got != tt.requests {
+			t.Errorf("%s: 요청 %d회, want %d", tt.tf, got, tt.requests)
+		}
+		if n := countRows(t, c, tf, "is_interpolated = 0"); n != tt.saved+1 {
+			t.Errorf("%s: 실제 캔들 %d개, want %d", tt.tf, n, tt.saved+1)
+		}
+		latest := tt.latest.Format(timestampLayout)
+		if n := countRows(t, c, tf, "timestamp > ?", latest); n != 0 {
+			t.Errorf("%s: 마감된 최신 캔들 %s 이후 캔들 %d개 (진행 중 캔들 저장)", tt.tf, latest, n)
+		}
+		if n := countRows(t, c, tf, "timestamp = ?", latest); n != 1 {
+			t.Errorf("%s: 최신 캔들 %s 이 없음", tt.tf, latest)
+		}
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Timeframe, r.Err)
+		}
+		if r.Saved != want[r.Timeframe] || r.Interpolated != 0 {
+			t.Errorf("%s: saved = %d, interpolated = %d, want %d, 0", r.Timeframe, r.Saved, r.Interpolated, want[r.Timeframe])
+		}
+	}
+	// 저장된 캔들이 없는 시간단위는 요청하지 않음
+	if len(paths) != len(tests) {
+		t.Errorf("요청한 경로 = %v, want %d개 시간단위만", paths, len(tests))
+	}
+}
+
+func TestTopUpReplacesProvisionalCandle(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		f := &fakeUpbit{head: time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC)}
+		srv := httptest.NewServer(f.handler(t))
+		c := openTestDB(t, "KRW-BTC")
+		c.apiURL = srv.URL
+		c.KeepProvisional = keep
+		tf := mustTimeframe(t, "minute1")
+		minute := func(m int) time.Time { return time.Date(2024, 1, 1, 20, m, 0, 0, time.UTC) } // KST 20:m = UTC 11:m
+
+		// 11:58:30 에 저장한 진행 중 캔들, 12:00:30 에 저장한 마감된 캔들 (값은 fakeUpbit 와 다름)
+		c.now = func() time.Time { return minute(58).Add(-9*time.Hour + 30*time.Second) }
+		seed(t, c, tf, minute(58))
+		c.now = func() time.Time { return minute(60).Add(-9*time.Hour + 30*time.Second) }
+		if _, _, err := c.saveCandles(tf, []Candle{testCandle(minute(59), 5)}); err != nil {
+			t.Fatal(err)
+		}
+		provisional := minute(58).Format(timestampLayout)
+		if n := countRows(t, c, tf, "is_provisional = 1"); n != 1 || countRows(t, c, tf, "timestamp = ? AND is_provisional = 1", provisional) != 1 {
+			t.Fatalf("keep=%v: 진행 중 캔들 표시 %d개, want %s 하나", keep, n, provisional)
+		}
+
+		c.now = func() time.Time { return f.head.Add(30 * time.Second) }
+		result := c.TopUp(tf)
+		srv.Close()
+		if result.Err != nil {
+			t.Fatalf("keep=%v: %v", keep, result.Err)
+		}
+
+		final := fakePrice(minute(58).Add(-9*time.Hour)) + 1
+		replaced := countRows(t, c, tf, "timestamp = ? AND trade_price = ? AND is_provisional = 0", provisional, final)
+		if keep {
+			if replaced != 0 || countRows(t, c, tf, "timestamp = ? AND trade_price = 100 AND is_provisional = 1", provisional) != 1 {
+				t.Errorf("keep=true: 진행 중 캔들이 덮어써짐")
+			}
+		} else if replaced != 1 {
+			t.Errorf("keep=false: %s 가 마감 값 %v 로 바뀌지 않았거나 is_provisional 이 남음", provisional, final)
+		}
+		// 마감 후 저장된 과거 캔들은 INSERT OR IGNORE 그대로
+		if n := countRows(t, c, tf, "timestamp = ? AND trade_price = 5", minute(59).Format(timestampLayout)); n != 1 {
+			t.Errorf("keep=%v: 마감된 캔들 값이 바뀜", keep)
+		}
+		if n := countRows(t, c, tf, "timestamp = ?", minute(60).Format(timestampLayout)); n != 1 {
+			t.Errorf("keep=%v: 새 마감 캔들 12:00 이 저장되지 않음", keep)
+		}
+		if n := countRows(t, c, tf, "timestamp = ?", minute(61).Format(timestampLayout)); n != 0 {
+			t.Errorf("keep=%v: 진행 중인 12:01 캔들이 저장됨", keep)
+		}
+		wantProvisional := 0
+		if keep {
+			wantProvisional = 1
+		}
+		if n := countRows(t, c, tf, "is_provisional = 1"); n != wantProvisional {
+			t.Errorf("keep=%v: is_provisional 행 %d개, want %d", keep, n, wantProvisional)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// TransportConfig - API 요청 HTTP 연결 재사용 설정
+type TransportConfig struct {
+	MaxIdleConns        int           // 전체 유휴 연결 수 제한
+	MaxIdleConnsPerHost int           // 호스트별 유휴 연결 수 제한 (병렬 수집 goroutine 수 이상 권장)
+	IdleConnTimeout     time.Duration // 유휴 연결 유지 시간
+}
+
+// DefaultTransportConfig - 모든 시간단위를 동시에 수집해도 연결을 재사용하는 기본값
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        32,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+func (cfg TransportConfig) transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	return t
+}
+
+// WithTransport - HTTP 연결 재사용 설정 변경 (기존 유휴 연결은 닫음)
+func (c *Collector) WithTransport(cfg TransportConfig) *Collector {
+	if old, ok := c.httpClient.Transport.(*http.Transport); ok {
+		old.CloseIdleConnections()
+	}
+	c.httpClient.Transport = cfg.transport()
+	return c
+}
+
+// drainAndClose - 남은 본문을 읽어 버린 뒤 닫음 (끝까지 읽어야 keep-alive 연결이 재사용됨)
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
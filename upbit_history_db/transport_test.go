@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRequestCandlesReusesConnection(t *testing.T) {
+	var requests, conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 성공 응답과 오류 응답을 번갈아 돌려줌
+		if requests.Add(1)%2 == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"name":"invalid_query_payload","message":"bad"}}`)
+			return
+		}
+		io.WriteString(w, `[]`)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	tf := mustTimeframe(t, "day")
+	for i := 0; i < 6; i++ {
+		_, err := c.requestCandles(context.Background(), tf, "", nil)
+		if wantErr := i%2 == 1; (err != nil) != wantErr {
+			t.Fatalf("[%d] err = %v, want 오류 %v", i, err, wantErr)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("연결 %d개, want 1 (성공/오류 응답 모두 본문을 비워야 재사용됨)", n)
+	}
+}
+
+// trackedBody - 끝까지 읽혔는지와 닫혔는지 기록하는 응답 본문
+type trackedBody struct {
+	io.Reader
+	eof, closed bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.eof = true
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainAndClose(t *testing.T) {
+	body := &trackedBody{Reader: strings.NewReader(strings.Repeat("x", 64<<10))}
+	// 일부만 읽은 채 닫는 오류 경로
+	io.ReadFull(body, make([]byte, 10))
+	drainAndClose(body)
+	if !body.eof || !body.closed {
+		t.Errorf("eof = %v, closed = %v, want 둘 다 true", body.eof, body.closed)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+// aggregationTolerance - CrossValidateAggregation 이 같은 값으로 보는 상대 오차 (거래량 합의 부동소수점 오차 허용)
+const aggregationTolerance = 1e-6
+
+// AggregationMismatch - 하위 시간단위를 합친 값이 저장된 상위 캔들과 다른 항목
+type AggregationMismatch struct {
+	Timestamp  string  `json:"timestamp"` // 상위 캔들 시작 시각 (KST)
+	Field      string  `json:"field"`     // open, high, low, close, volume, value
+	Native     float64 `json:"native"`
+	Aggregated float64 `json:"aggregated"`
+}
+
+// ValidationReport - CrossValidateAggregation 결과
+type ValidationReport struct {
+	Source        string                `json:"source"`
+	Target        string                `json:"target"`
+	Buckets       int                   `json:"buckets"`        // 비교한 상위 캔들 수
+	MissingSource int                   `json:"missing_source"` // 하위 캔들이 하나도 없어 비교하지 못한 상위 캔들 수
+	Mismatched    int                   `json:"mismatched"`     // 한 항목이라도 다른 상위 캔들 수
+	Mismatches    []AggregationMismatch `json:"mismatches,omitempty"`
+}
+
+// CrossValidateAggregation - src 실제 캔들을 dst 구간으로 합쳐 저장된 dst 캔들과 OHLCV 비교 (읽기 전용)
+//
+// from ~ to(KST, GetCandles 와 같은 범위) 의 마감된 dst 실제 캔들마다 같은 구간의 src 실제 캔들을
+// aggregateCandles 로 합친다. 업비트는 거래가 없는 분의 캔들을 주지 않으므로 보간 캔들은 합치지 않는다.
+// 상대 오차가 aggregationTolerance 를 넘는 항목을 Mismatches 에 담는다.
+func (c *Collector) CrossValidateAggregation(src, dst Timeframe, from, to time.Time) (ValidationReport, error) {
+	report := ValidationReport{Source: src.Name, Target: dst.Name}
+	if !aggregatesInto(src, dst) {
+		return report, fmt.Errorf("%s 캔들은 %s 구간으로 나누어떨어지게 합칠 수 없습니다", src.Name, dst.Name)
+	}
+
+	natives, err := c.GetCandles(dst, from, to)
+	if err != nil {
+		return report, err
+	}
+	closed := make([]Candle, 0, len(natives))
+	for _, candle := range natives {
+		if !candle.IsInterpolated && !c.isProvisional(dst, candle.CandleDateTimeKST) {
+			closed = append(closed, candle)
+		}
+	}
+	if len(closed) == 0 {
+		return report, nil
+	}
+
+	first, err := time.Parse(timestampLayout, closed[0].CandleDateTimeKST)
+	if err != nil {
+		return report, fmt.Errorf("잘못된 timestamp %q: %w", closed[0].CandleDateTimeKST, err)
+	}
+	last, err := time.Parse(timestampLayout, closed[len(closed)-1].CandleDateTimeKST)
+	if err != nil {
+		return report, fmt.Errorf("잘못된 timestamp %q: %w", closed[len(closed)-1].CandleDateTimeKST, err)
+	}
+	sources, err := c.GetCandles(src, first, candleEnd(dst, last).Add(-time.Second))
+	if err != nil {
+		return report, err
+	}
+	actual := make([]Candle, 0, len(sources))
+	for _, candle := range sources {
+		if !candle.IsInterpolated {
+			actual = append(actual, candle)
+		}
+	}
+	aggregated := make(map[string]Candle)
+	for _, bucket := range aggregateCandles(dst, actual) {
+		aggregated[bucket.CandleDateTimeKST] = bucket
+	}
+
+	for _, native := range closed {
+		report.Buckets++
+		agg, ok := aggregated[native.CandleDateTimeKST]
+		if !ok {
+			report.MissingSource++
+			continue
+		}
+		fields := []struct {
+			name         string
+			native, aggr float64
+		}{
+			{"open", native.OpeningPrice, agg.OpeningPrice},
+			{"high", native.HighPrice, agg.HighPrice},
+			{"low", native.LowPrice, agg.LowPrice},
+			{"close", native.TradePrice, agg.TradePrice},
+			{"volume", native.CandleAccTradeVolume, agg.CandleAccTradeVolume},
+			{"value", native.CandleAccTradePrice, agg.CandleAccTradePrice},
+		}
+		mismatched := false
+		for _, f := range fields {
+			if withinTolerance(f.native, f.aggr) {
+				continue
+			}
+			mismatched = true
+			report.Mismatches = append(report.Mismatches, AggregationMismatch{
+				Timestamp: native.CandleDateTimeKST, Field: f.name, Native: f.native, Aggregated: f.aggr,
+			})
+		}
+		if mismatched {
+			report.Mismatched++
+		}
+	}
+	return report, nil
+}
+
+// aggregatesInto - src 캔들 구간이 dst 캔들 구간 경계에 맞게 나누어지는지 (월봉은 일봉 이하만)
+func aggregatesInto(src, dst Timeframe) bool {
+	if dst.Name == "month" {
+		return src.Minutes <= 1440
+	}
+	return src.Minutes < dst.Minutes && dst.Minutes%src.Minutes == 0
+}
+
+// withinTolerance - a, b 의 상대 오차가 aggregationTolerance 이하인지
+func withinTolerance(a, b float64) bool {
+	scale := max(math.Abs(a), math.Abs(b))
+	return math.Abs(a-b) <= aggregationTolerance*scale
+}
+
+func runValidateAggregation(args []string) error {
+	fs := flag.NewFlagSet("validate-aggregation", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	source := fs.String("source", "minute1", "합칠 하위 시간단위")
+	target := fs.String("target", "day", "비교할 상위 시간단위")
+	from := fs.String("from", "", "비교 시작 시각 (KST, YYYY-MM-DD 또는 YYYY-MM-DDTHH:MM:SS)")
+	to := fs.String("to", "", "비교 종료 시각 (KST, 포함)")
+	limit := fs.Int("limit", 20, "출력할 불일치 항목 수 (0 = 전부)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := findTimeframe(*source)
+	if err != nil {
+		return err
+	}
+	dst, err := findTimeframe(*target)
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseQueryTime(*from)
+	if err != nil {
+		return fmt.Errorf("잘못된 --from: %w", err)
+	}
+	toTime, err := parseQueryTime(*to)
+	if err != nil {
+		return fmt.Errorf("잘못된 --to: %w", err)
+	}
+
+	collector, err := common.open()
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	report, err := collector.CrossValidateAggregation(src, dst, fromTime, toTime)
+	if err != nil {
+		return err
+	}
+	for i, m := range report.Mismatches {
+		if *limit > 0 && i >= *limit {
+			fmt.Printf("  ... 외 %d개\n", len(report.Mismatches)-i)
+			break
+		}
+		fmt.Printf("[%s] %s %s %s: 저장 %s, 합계 %s\n", dst.Name, collector.mark(markWarn),
+			m.Timestamp, m.Field, formatFloat(m.Native), formatFloat(m.Aggregated))
+	}
+	mark := markOK
+	if report.Mismatched > 0 {
+		mark = markWarn
+	}
+	fmt.Printf("[%s] %s %s 캔들 %s개 비교: 불일치 %s개, %s 캔들 없음 %s개\n", dst.Name, collector.mark(mark),
+		dst.Name, formatNumber(report.Buckets), formatNumber(report.Mismatched), src.Name, formatNumber(report.MissingSource))
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCrossValidateAggregationFlagsWrongMinute(t *testing.T) {
+	c := openTestDB(t, "KRW-BTC")
+	minute1, minute60 := mustTimeframe(t, "minute1"), mustTimeframe(t, "minute60")
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// 3시간치 1분봉과 그 값으로 직접 계산한 60분봉, 그리고 1분봉이 없는 네 번째 60분봉
+	var minutes, hours []Candle
+	for h := 0; h < 4; h++ {
+		start := t0.Add(time.Duration(h) * time.Hour)
+		hour := testCandle(start, 0)
+		hour.CandleAccTradeVolume, hour.CandleAccTradePrice = 0, 0
+		for m := 0; m < 60 && h < 3; m++ {
+			price := float64(100 + (h*60+m)%7)
+			minutes = append(minutes, testCandle(start.Add(time.Duration(m)*time.Minute), price))
+			if m == 0 {
+				hour.OpeningPrice, hour.HighPrice, hour.LowPrice = price, price+1, price-1
+			}
+			hour.HighPrice, hour.LowPrice = max(hour.HighPrice, price+1), min(hour.LowPrice, price-1)
+			hour.TradePrice = price
+			hour.CandleAccTradeVolume++
+			hour.CandleAccTradePrice += price
+		}
+		hours = append(hours, hour)
+	}
+	if _, _, err := c.saveCandles(minute1, minutes); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.saveCandles(minute60, hours); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := c.CrossValidateAggregation(minute1, minute60, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Buckets != 4 || report.MissingSource != 1 || report.Mismatched != 0 || len(report.Mismatches) != 0 {
+		t.Fatalf("정상 데이터 결과 = %+v, want 4개 비교, 1개 원본 없음, 불일치 없음", report)
+	}
+
+	// 10:30 1분봉 고가를 잘못 기록
+	wrong := t0.Add(90 * time.Minute).Format(timestampLayout)
+	if _, err := c.db.Exec("UPDATE bitcoin_minute1 SET high_price = 9999 WHERE timestamp = ?", wrong); err != nil {
+		t.Fatal(err)
+	}
+	report, err = c.CrossValidateAggregation(minute1, minute60, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AggregationMismatch{{Timestamp: hours[1].CandleDateTimeKST, Field: "high", Native: hours[1].HighPrice, Aggregated: 9999}}
+	if report.Mismatched != 1 || !reflect.DeepEqual(report.Mismatches, want) {
+		t.Errorf("불일치 = %d, %+v, want %+v", report.Mismatched, report.Mismatches, want)
+	}
+
+	// 범위를 좁히면 해당 구간만 비교
+	report, err = c.CrossValidateAggregation(minute1, minute60, t0, t0.Add(30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Buckets != 1 || report.Mismatched != 0 {
+		t.Errorf("09시만 비교한 결과 = %+v, want 1개, 불일치 없음", report)
+	}
+
+	if _, err := c.CrossValidateAggregation(mustTimeframe(t, "minute3"), mustTimeframe(t, "minute5"), time.Time{}, time.Time{}); err == nil {
+		t.Error("minute3 → minute5 오류 없음")
+	}
+}
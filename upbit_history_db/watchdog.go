@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// maxStallRestarts - 한 시간단위에서 watchdog 이 수집을 다시 시작하는 최대 횟수
+const maxStallRestarts = 5
+
+// collectWithWatchdog - collectPages 를 별도 goroutine 에서 실행하며 StallTimeout 동안 진행이 없으면
+// 요청을 취소(진행 중인 HTTP 요청 중단)하고 마지막으로 저장한 위치부터 다시 수집 (parent 가 취소되면 재시작 없이 반환)
+func (c *Collector) collectWithWatchdog(parent context.Context, tf Timeframe, cur *collectCursor) {
+	for restarts := 0; ; restarts++ {
+		ctx, cancel := context.WithCancel(parent)
+		var progress atomic.Int64 // 마지막 진행 시각 (점검 대기 중에는 재개 예정 시각, UnixNano)
+		touch := func(until time.Time) { progress.Store(until.UnixNano()) }
+		touch(time.Now())
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.collectPages(ctx, tf, cur, touch)
+		}()
+
+		stalled := c.watchProgress(done, &progress)
+		cancel()
+		<-done
+		if !stalled || parent.Err() != nil {
+			return
+		}
+
+		if restarts >= maxStallRestarts {
+			fmt.Fprintf(c.Output, "[%s] %s %v 동안 진행 없음 - 재시작 한도(%d회) 초과, 수집 중단\n",
+				tf.Name, c.mark(markFail), c.StallTimeout, maxStallRestarts)
+			cur.result.Err = fmt.Errorf("%v 동안 진행 없음 (재시작 %d회)", c.StallTimeout, restarts)
+			return
+		}
+		from := cur.prevOldest
+		if from == "" {
+			from = "최신"
+		}
+		fmt.Fprintf(c.Output, "[%s] %s %v 동안 진행 없음 - 요청 취소 후 %s 부터 재시작 (%d/%d)\n",
+			tf.Name, c.mark(markWarn), c.StallTimeout, from, restarts+1, maxStallRestarts)
+	}
+}
+
+// watchProgress - done 전에 progress 가 StallTimeout 보다 오래 갱신되지 않으면 true
+func (c *Collector) watchProgress(done <-chan struct{}, progress *atomic.Int64) bool {
+	ticker := time.NewTicker(min(c.StallTimeout/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return false
+		case <-ticker.C:
+			if time.Since(time.Unix(0, progress.Load())) > c.StallTimeout {
+				return true
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchdogRestartsHungTimeframe(t *testing.T) {
+	c, f := newTestCollector(t)
+	fake := f.handler(t)
+	var mu sync.Mutex
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("to"))
+		hang := len(cursors) == 2
+		mu.Unlock()
+		if hang {
+			// 두 번째 페이지 요청이 취소될 때까지 응답하지 않음
+			<-r.Context().Done()
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c.apiURL = srv.URL
+	var out bytes.Buffer
+	c.Output = &out
+	c.MaxPages = 3
+	c.StallTimeout = 300 * time.Millisecond
+
+	started := time.Now()
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	if result.Err != nil {
+		t.Fatalf("재시작 후에도 실패: %v\n%s", result.Err, out.String())
+	}
+	if result.Pages != 3 || result.Saved != 600 {
+		t.Errorf("pages = %d, saved = %d, want 3, 600", result.Pages, result.Saved)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("복구까지 %v 걸림 (HTTP timeout 이 아니라 watchdog 이 취소해야 함)", elapsed)
+	}
+	if !strings.Contains(out.String(), "진행 없음 - 요청 취소 후") || !strings.Contains(out.String(), "재시작 (1/") {
+		t.Errorf("재시작 로그 없음:\n%s", out.String())
+	}
+
+	// 재시작은 멈춘 요청과 같은 위치부터
+	mu.Lock()
+	defer mu.Unlock()
+	if len(cursors) != 4 || cursors[1] == "" || cursors[2] != cursors[1] {
+		t.Errorf("요청 to = %q, want 두 번째 요청 위치를 그대로 다시 요청", cursors)
+	}
+}
+
+func TestWatchdogGivesUpAfterRestarts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	c := openTestDB(t, "KRW-BTC")
+	c.apiURL = srv.URL
+	c.StallTimeout = 100 * time.Millisecond
+
+	result := c.collectTimeframe(mustTimeframe(t, "minute1"))
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "진행 없음") {
+		t.Errorf("err = %v, want 재시작 한도 초과 오류", result.Err)
+	}
+	if result.Pages != 0 || result.Saved != 0 {
+		t.Errorf("pages = %d, saved = %d, want 0", result.Pages, result.Saved)
+	}
+}